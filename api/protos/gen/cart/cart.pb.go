@@ -0,0 +1,1847 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: api/protos/cart/cart.proto
+
+package cart
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AddItemRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	Sku             uint32                 `protobuf:"varint,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Count           uint32                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddItemRequest) Reset() {
+	*x = AddItemRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddItemRequest) ProtoMessage() {}
+
+func (x *AddItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddItemRequest.ProtoReflect.Descriptor instead.
+func (*AddItemRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddItemRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *AddItemRequest) GetSku() uint32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *AddItemRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AddItemRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type AddItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddItemResponse) Reset() {
+	*x = AddItemResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddItemResponse) ProtoMessage() {}
+
+func (x *AddItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddItemResponse.ProtoReflect.Descriptor instead.
+func (*AddItemResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{1}
+}
+
+type BatchItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           uint32                 `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItem) Reset() {
+	*x = BatchItem{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItem) ProtoMessage() {}
+
+func (x *BatchItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItem.ProtoReflect.Descriptor instead.
+func (*BatchItem) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BatchItem) GetSku() uint32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *BatchItem) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type AddItemsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	Items           []*BatchItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddItemsRequest) Reset() {
+	*x = AddItemsRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddItemsRequest) ProtoMessage() {}
+
+func (x *AddItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddItemsRequest.ProtoReflect.Descriptor instead.
+func (*AddItemsRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AddItemsRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *AddItemsRequest) GetItems() []*BatchItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *AddItemsRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+// BatchItemResult is the result of one entry of AddItems. reason and error are
+// set if the entry was not added: reason is one of "product_not_found",
+// "insufficient_stock" and "limit_exceeded", error describes the failure.
+type BatchItemResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           uint32                 `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Added         bool                   `protobuf:"varint,3,opt,name=added,proto3" json:"added,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchItemResult) Reset() {
+	*x = BatchItemResult{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchItemResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchItemResult) ProtoMessage() {}
+
+func (x *BatchItemResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchItemResult.ProtoReflect.Descriptor instead.
+func (*BatchItemResult) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *BatchItemResult) GetSku() uint32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *BatchItemResult) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *BatchItemResult) GetAdded() bool {
+	if x != nil {
+		return x.Added
+	}
+	return false
+}
+
+func (x *BatchItemResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BatchItemResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type AddItemsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// results are in request order
+	Results       []*BatchItemResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddItemsResponse) Reset() {
+	*x = AddItemsResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddItemsResponse) ProtoMessage() {}
+
+func (x *AddItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddItemsResponse.ProtoReflect.Descriptor instead.
+func (*AddItemsResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AddItemsResponse) GetResults() []*BatchItemResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type RemoveItemRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	Sku             uint32                 `protobuf:"varint,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveItemRequest) Reset() {
+	*x = RemoveItemRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveItemRequest) ProtoMessage() {}
+
+func (x *RemoveItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveItemRequest.ProtoReflect.Descriptor instead.
+func (*RemoveItemRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RemoveItemRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *RemoveItemRequest) GetSku() uint32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *RemoveItemRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemoveItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveItemResponse) Reset() {
+	*x = RemoveItemResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveItemResponse) ProtoMessage() {}
+
+func (x *RemoveItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveItemResponse.ProtoReflect.Descriptor instead.
+func (*RemoveItemResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{7}
+}
+
+type SetItemQuantityRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	Sku             uint32                 `protobuf:"varint,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Count           uint32                 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetItemQuantityRequest) Reset() {
+	*x = SetItemQuantityRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetItemQuantityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetItemQuantityRequest) ProtoMessage() {}
+
+func (x *SetItemQuantityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetItemQuantityRequest.ProtoReflect.Descriptor instead.
+func (*SetItemQuantityRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetItemQuantityRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *SetItemQuantityRequest) GetSku() uint32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *SetItemQuantityRequest) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *SetItemQuantityRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type SetItemQuantityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetItemQuantityResponse) Reset() {
+	*x = SetItemQuantityResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetItemQuantityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetItemQuantityResponse) ProtoMessage() {}
+
+func (x *SetItemQuantityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetItemQuantityResponse.ProtoReflect.Descriptor instead.
+func (*SetItemQuantityResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{9}
+}
+
+type ChangeItemQuantityRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	Sku             uint32                 `protobuf:"varint,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Delta           int32                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,4,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChangeItemQuantityRequest) Reset() {
+	*x = ChangeItemQuantityRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeItemQuantityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeItemQuantityRequest) ProtoMessage() {}
+
+func (x *ChangeItemQuantityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeItemQuantityRequest.ProtoReflect.Descriptor instead.
+func (*ChangeItemQuantityRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ChangeItemQuantityRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *ChangeItemQuantityRequest) GetSku() uint32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *ChangeItemQuantityRequest) GetDelta() int32 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *ChangeItemQuantityRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ChangeItemQuantityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeItemQuantityResponse) Reset() {
+	*x = ChangeItemQuantityResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeItemQuantityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeItemQuantityResponse) ProtoMessage() {}
+
+func (x *ChangeItemQuantityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeItemQuantityResponse.ProtoReflect.Descriptor instead.
+func (*ChangeItemQuantityResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{11}
+}
+
+type ClearCartRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ClearCartRequest) Reset() {
+	*x = ClearCartRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearCartRequest) ProtoMessage() {}
+
+func (x *ClearCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearCartRequest.ProtoReflect.Descriptor instead.
+func (*ClearCartRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClearCartRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *ClearCartRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ClearCartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClearCartResponse) Reset() {
+	*x = ClearCartResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearCartResponse) ProtoMessage() {}
+
+func (x *ClearCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearCartResponse.ProtoReflect.Descriptor instead.
+func (*ClearCartResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{13}
+}
+
+type GetCartRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	// currency is the ISO 4217 code to show prices in; empty keeps the cart's own
+	Currency      string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartRequest) Reset() {
+	*x = GetCartRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartRequest) ProtoMessage() {}
+
+func (x *GetCartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartRequest.ProtoReflect.Descriptor instead.
+func (*GetCartRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCartRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *GetCartRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Money is an amount in minor currency units with an ISO 4217 currency code
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Money) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Money) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CartItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sku   uint32                 `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Count uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Price *Money                 `protobuf:"bytes,7,opt,name=price,proto3" json:"price,omitempty"`
+	Name  string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	// priceChanged is set if the price changed since the item was added;
+	// previousPrice is then the price the customer saw before
+	PriceChanged  bool   `protobuf:"varint,5,opt,name=priceChanged,proto3" json:"priceChanged,omitempty"`
+	PreviousPrice *Money `protobuf:"bytes,8,opt,name=previousPrice,proto3" json:"previousPrice,omitempty"`
+	// discount is taken off the price of all count items by promotions; unset if there is none
+	Discount *Money `protobuf:"bytes,9,opt,name=discount,proto3" json:"discount,omitempty"`
+	// unavailable is set if the product was removed from the catalog; the
+	// item must be removed before checkout
+	Unavailable   bool `protobuf:"varint,10,opt,name=unavailable,proto3" json:"unavailable,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CartItem) Reset() {
+	*x = CartItem{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CartItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CartItem) ProtoMessage() {}
+
+func (x *CartItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CartItem.ProtoReflect.Descriptor instead.
+func (*CartItem) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CartItem) GetSku() uint32 {
+	if x != nil {
+		return x.Sku
+	}
+	return 0
+}
+
+func (x *CartItem) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CartItem) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *CartItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CartItem) GetPriceChanged() bool {
+	if x != nil {
+		return x.PriceChanged
+	}
+	return false
+}
+
+func (x *CartItem) GetPreviousPrice() *Money {
+	if x != nil {
+		return x.PreviousPrice
+	}
+	return nil
+}
+
+func (x *CartItem) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *CartItem) GetUnavailable() bool {
+	if x != nil {
+		return x.Unavailable
+	}
+	return false
+}
+
+// AppliedPromotion is a promotion that discounts the cart
+type AppliedPromotion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Discount      *Money                 `protobuf:"bytes,3,opt,name=discount,proto3" json:"discount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AppliedPromotion) Reset() {
+	*x = AppliedPromotion{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AppliedPromotion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AppliedPromotion) ProtoMessage() {}
+
+func (x *AppliedPromotion) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AppliedPromotion.ProtoReflect.Descriptor instead.
+func (*AppliedPromotion) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AppliedPromotion) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AppliedPromotion) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AppliedPromotion) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+type GetCartResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Items      []*CartItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	TotalPrice *Money                 `protobuf:"bytes,4,opt,name=totalPrice,proto3" json:"totalPrice,omitempty"`
+	Version    uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Coupon     string                 `protobuf:"bytes,5,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	Promotions []*AppliedPromotion    `protobuf:"bytes,6,rep,name=promotions,proto3" json:"promotions,omitempty"`
+	// discount is the sum of the promotion discounts, discountedTotal what the customer pays
+	Discount        *Money `protobuf:"bytes,7,opt,name=discount,proto3" json:"discount,omitempty"`
+	DiscountedTotal *Money `protobuf:"bytes,8,opt,name=discountedTotal,proto3" json:"discountedTotal,omitempty"`
+	Region          string `protobuf:"bytes,9,opt,name=region,proto3" json:"region,omitempty"`
+	// prices include taxes: subtotal is the discounted total without them,
+	// grandTotal what the customer pays including them
+	Subtotal      *Money     `protobuf:"bytes,10,opt,name=subtotal,proto3" json:"subtotal,omitempty"`
+	Taxes         []*TaxLine `protobuf:"bytes,11,rep,name=taxes,proto3" json:"taxes,omitempty"`
+	GrandTotal    *Money     `protobuf:"bytes,12,opt,name=grandTotal,proto3" json:"grandTotal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCartResponse) Reset() {
+	*x = GetCartResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCartResponse) ProtoMessage() {}
+
+func (x *GetCartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCartResponse.ProtoReflect.Descriptor instead.
+func (*GetCartResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetCartResponse) GetItems() []*CartItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *GetCartResponse) GetTotalPrice() *Money {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return nil
+}
+
+func (x *GetCartResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetCartResponse) GetCoupon() string {
+	if x != nil {
+		return x.Coupon
+	}
+	return ""
+}
+
+func (x *GetCartResponse) GetPromotions() []*AppliedPromotion {
+	if x != nil {
+		return x.Promotions
+	}
+	return nil
+}
+
+func (x *GetCartResponse) GetDiscount() *Money {
+	if x != nil {
+		return x.Discount
+	}
+	return nil
+}
+
+func (x *GetCartResponse) GetDiscountedTotal() *Money {
+	if x != nil {
+		return x.DiscountedTotal
+	}
+	return nil
+}
+
+func (x *GetCartResponse) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *GetCartResponse) GetSubtotal() *Money {
+	if x != nil {
+		return x.Subtotal
+	}
+	return nil
+}
+
+func (x *GetCartResponse) GetTaxes() []*TaxLine {
+	if x != nil {
+		return x.Taxes
+	}
+	return nil
+}
+
+func (x *GetCartResponse) GetGrandTotal() *Money {
+	if x != nil {
+		return x.GrandTotal
+	}
+	return nil
+}
+
+// TaxLine is the tax included in the prices of the items of one tax category
+type TaxLine struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Category string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	// rate is in percent, e.g. "20"
+	Rate          string `protobuf:"bytes,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	Taxable       *Money `protobuf:"bytes,3,opt,name=taxable,proto3" json:"taxable,omitempty"`
+	Amount        *Money `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaxLine) Reset() {
+	*x = TaxLine{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaxLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaxLine) ProtoMessage() {}
+
+func (x *TaxLine) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaxLine.ProtoReflect.Descriptor instead.
+func (*TaxLine) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *TaxLine) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *TaxLine) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+func (x *TaxLine) GetTaxable() *Money {
+	if x != nil {
+		return x.Taxable
+	}
+	return nil
+}
+
+func (x *TaxLine) GetAmount() *Money {
+	if x != nil {
+		return x.Amount
+	}
+	return nil
+}
+
+type CheckoutRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	// confirmPriceChanges accepts prices that changed since the caller last saw the cart;
+	// without it such a checkout fails with ABORTED
+	ConfirmPriceChanges bool `protobuf:"varint,3,opt,name=confirmPriceChanges,proto3" json:"confirmPriceChanges,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CheckoutRequest) Reset() {
+	*x = CheckoutRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutRequest) ProtoMessage() {}
+
+func (x *CheckoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutRequest.ProtoReflect.Descriptor instead.
+func (*CheckoutRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CheckoutRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *CheckoutRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *CheckoutRequest) GetConfirmPriceChanges() bool {
+	if x != nil {
+		return x.ConfirmPriceChanges
+	}
+	return false
+}
+
+type CheckoutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       int64                  `protobuf:"varint,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckoutResponse) Reset() {
+	*x = CheckoutResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckoutResponse) ProtoMessage() {}
+
+func (x *CheckoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckoutResponse.ProtoReflect.Descriptor instead.
+func (*CheckoutResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *CheckoutResponse) GetOrderID() int64 {
+	if x != nil {
+		return x.OrderID
+	}
+	return 0
+}
+
+type ApplyCouponRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	Code            string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ApplyCouponRequest) Reset() {
+	*x = ApplyCouponRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyCouponRequest) ProtoMessage() {}
+
+func (x *ApplyCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyCouponRequest.ProtoReflect.Descriptor instead.
+func (*ApplyCouponRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ApplyCouponRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *ApplyCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ApplyCouponRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type ApplyCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyCouponResponse) Reset() {
+	*x = ApplyCouponResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyCouponResponse) ProtoMessage() {}
+
+func (x *ApplyCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyCouponResponse.ProtoReflect.Descriptor instead.
+func (*ApplyCouponResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{23}
+}
+
+type RemoveCouponRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,2,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RemoveCouponRequest) Reset() {
+	*x = RemoveCouponRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCouponRequest) ProtoMessage() {}
+
+func (x *RemoveCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCouponRequest.ProtoReflect.Descriptor instead.
+func (*RemoveCouponRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *RemoveCouponRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *RemoveCouponRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type RemoveCouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveCouponResponse) Reset() {
+	*x = RemoveCouponResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveCouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveCouponResponse) ProtoMessage() {}
+
+func (x *RemoveCouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveCouponResponse.ProtoReflect.Descriptor instead.
+func (*RemoveCouponResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{25}
+}
+
+type SetRegionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	User            int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
+	Region          string                 `protobuf:"bytes,2,opt,name=region,proto3" json:"region,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,3,opt,name=expectedVersion,proto3" json:"expectedVersion,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetRegionRequest) Reset() {
+	*x = SetRegionRequest{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRegionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRegionRequest) ProtoMessage() {}
+
+func (x *SetRegionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRegionRequest.ProtoReflect.Descriptor instead.
+func (*SetRegionRequest) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetRegionRequest) GetUser() int64 {
+	if x != nil {
+		return x.User
+	}
+	return 0
+}
+
+func (x *SetRegionRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *SetRegionRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type SetRegionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRegionResponse) Reset() {
+	*x = SetRegionResponse{}
+	mi := &file_api_protos_cart_cart_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRegionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRegionResponse) ProtoMessage() {}
+
+func (x *SetRegionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_protos_cart_cart_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRegionResponse.ProtoReflect.Descriptor instead.
+func (*SetRegionResponse) Descriptor() ([]byte, []int) {
+	return file_api_protos_cart_cart_proto_rawDescGZIP(), []int{27}
+}
+
+var File_api_protos_cart_cart_proto protoreflect.FileDescriptor
+
+const file_api_protos_cart_cart_proto_rawDesc = "" +
+	"\n" +
+	"\x1aapi/protos/cart/cart.proto\x12\x04cart\"v\n" +
+	"\x0eAddItemRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\rR\x03sku\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count\x12(\n" +
+	"\x0fexpectedVersion\x18\x04 \x01(\x04R\x0fexpectedVersion\"\x11\n" +
+	"\x0fAddItemResponse\"3\n" +
+	"\tBatchItem\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\rR\x03sku\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\"v\n" +
+	"\x0fAddItemsRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12%\n" +
+	"\x05items\x18\x02 \x03(\v2\x0f.cart.BatchItemR\x05items\x12(\n" +
+	"\x0fexpectedVersion\x18\x03 \x01(\x04R\x0fexpectedVersion\"}\n" +
+	"\x0fBatchItemResult\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\rR\x03sku\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\x12\x14\n" +
+	"\x05added\x18\x03 \x01(\bR\x05added\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"C\n" +
+	"\x10AddItemsResponse\x12/\n" +
+	"\aresults\x18\x01 \x03(\v2\x15.cart.BatchItemResultR\aresults\"c\n" +
+	"\x11RemoveItemRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\rR\x03sku\x12(\n" +
+	"\x0fexpectedVersion\x18\x03 \x01(\x04R\x0fexpectedVersion\"\x14\n" +
+	"\x12RemoveItemResponse\"~\n" +
+	"\x16SetItemQuantityRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\rR\x03sku\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\rR\x05count\x12(\n" +
+	"\x0fexpectedVersion\x18\x04 \x01(\x04R\x0fexpectedVersion\"\x19\n" +
+	"\x17SetItemQuantityResponse\"\x81\x01\n" +
+	"\x19ChangeItemQuantityRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\rR\x03sku\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x05R\x05delta\x12(\n" +
+	"\x0fexpectedVersion\x18\x04 \x01(\x04R\x0fexpectedVersion\"\x1c\n" +
+	"\x1aChangeItemQuantityResponse\"P\n" +
+	"\x10ClearCartRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12(\n" +
+	"\x0fexpectedVersion\x18\x02 \x01(\x04R\x0fexpectedVersion\"\x13\n" +
+	"\x11ClearCartResponse\"@\n" +
+	"\x0eGetCartRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\";\n" +
+	"\x05Money\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\"\x97\x02\n" +
+	"\bCartItem\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\rR\x03sku\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\x12!\n" +
+	"\x05price\x18\a \x01(\v2\v.cart.MoneyR\x05price\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\"\n" +
+	"\fpriceChanged\x18\x05 \x01(\bR\fpriceChanged\x121\n" +
+	"\rpreviousPrice\x18\b \x01(\v2\v.cart.MoneyR\rpreviousPrice\x12'\n" +
+	"\bdiscount\x18\t \x01(\v2\v.cart.MoneyR\bdiscount\x12 \n" +
+	"\vunavailable\x18\n" +
+	" \x01(\bR\vunavailableJ\x04\b\x03\x10\x04J\x04\b\x06\x10\a\"m\n" +
+	"\x10AppliedPromotion\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12'\n" +
+	"\bdiscount\x18\x03 \x01(\v2\v.cart.MoneyR\bdiscount\"\xc7\x03\n" +
+	"\x0fGetCartResponse\x12$\n" +
+	"\x05items\x18\x01 \x03(\v2\x0e.cart.CartItemR\x05items\x12+\n" +
+	"\n" +
+	"totalPrice\x18\x04 \x01(\v2\v.cart.MoneyR\n" +
+	"totalPrice\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\x12\x16\n" +
+	"\x06coupon\x18\x05 \x01(\tR\x06coupon\x126\n" +
+	"\n" +
+	"promotions\x18\x06 \x03(\v2\x16.cart.AppliedPromotionR\n" +
+	"promotions\x12'\n" +
+	"\bdiscount\x18\a \x01(\v2\v.cart.MoneyR\bdiscount\x125\n" +
+	"\x0fdiscountedTotal\x18\b \x01(\v2\v.cart.MoneyR\x0fdiscountedTotal\x12\x16\n" +
+	"\x06region\x18\t \x01(\tR\x06region\x12'\n" +
+	"\bsubtotal\x18\n" +
+	" \x01(\v2\v.cart.MoneyR\bsubtotal\x12#\n" +
+	"\x05taxes\x18\v \x03(\v2\r.cart.TaxLineR\x05taxes\x12+\n" +
+	"\n" +
+	"grandTotal\x18\f \x01(\v2\v.cart.MoneyR\n" +
+	"grandTotalJ\x04\b\x02\x10\x03\"\x85\x01\n" +
+	"\aTaxLine\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\tR\x04rate\x12%\n" +
+	"\ataxable\x18\x03 \x01(\v2\v.cart.MoneyR\ataxable\x12#\n" +
+	"\x06amount\x18\x04 \x01(\v2\v.cart.MoneyR\x06amount\"\x81\x01\n" +
+	"\x0fCheckoutRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12(\n" +
+	"\x0fexpectedVersion\x18\x02 \x01(\x04R\x0fexpectedVersion\x120\n" +
+	"\x13confirmPriceChanges\x18\x03 \x01(\bR\x13confirmPriceChanges\",\n" +
+	"\x10CheckoutResponse\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\x03R\aorderID\"f\n" +
+	"\x12ApplyCouponRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12(\n" +
+	"\x0fexpectedVersion\x18\x03 \x01(\x04R\x0fexpectedVersion\"\x15\n" +
+	"\x13ApplyCouponResponse\"S\n" +
+	"\x13RemoveCouponRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12(\n" +
+	"\x0fexpectedVersion\x18\x02 \x01(\x04R\x0fexpectedVersion\"\x16\n" +
+	"\x14RemoveCouponResponse\"h\n" +
+	"\x10SetRegionRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\x03R\x04user\x12\x16\n" +
+	"\x06region\x18\x02 \x01(\tR\x06region\x12(\n" +
+	"\x0fexpectedVersion\x18\x03 \x01(\x04R\x0fexpectedVersion\"\x13\n" +
+	"\x11SetRegionResponse2\xf3\x05\n" +
+	"\x04Cart\x128\n" +
+	"\aAddItem\x12\x14.cart.AddItemRequest\x1a\x15.cart.AddItemResponse\"\x00\x12A\n" +
+	"\n" +
+	"RemoveItem\x12\x17.cart.RemoveItemRequest\x1a\x18.cart.RemoveItemResponse\"\x00\x12;\n" +
+	"\bAddItems\x12\x15.cart.AddItemsRequest\x1a\x16.cart.AddItemsResponse\"\x00\x12P\n" +
+	"\x0fSetItemQuantity\x12\x1c.cart.SetItemQuantityRequest\x1a\x1d.cart.SetItemQuantityResponse\"\x00\x12Y\n" +
+	"\x12ChangeItemQuantity\x12\x1f.cart.ChangeItemQuantityRequest\x1a .cart.ChangeItemQuantityResponse\"\x00\x12>\n" +
+	"\tClearCart\x12\x16.cart.ClearCartRequest\x1a\x17.cart.ClearCartResponse\"\x00\x128\n" +
+	"\aGetCart\x12\x14.cart.GetCartRequest\x1a\x15.cart.GetCartResponse\"\x00\x12;\n" +
+	"\bCheckout\x12\x15.cart.CheckoutRequest\x1a\x16.cart.CheckoutResponse\"\x00\x12D\n" +
+	"\vApplyCoupon\x12\x18.cart.ApplyCouponRequest\x1a\x19.cart.ApplyCouponResponse\"\x00\x12G\n" +
+	"\fRemoveCoupon\x12\x19.cart.RemoveCouponRequest\x1a\x1a.cart.RemoveCouponResponse\"\x00\x12>\n" +
+	"\tSetRegion\x12\x16.cart.SetRegionRequest\x1a\x17.cart.SetRegionResponse\"\x00B#Z!route256/cart/api/protos/gen/cartb\x06proto3"
+
+var (
+	file_api_protos_cart_cart_proto_rawDescOnce sync.Once
+	file_api_protos_cart_cart_proto_rawDescData []byte
+)
+
+func file_api_protos_cart_cart_proto_rawDescGZIP() []byte {
+	file_api_protos_cart_cart_proto_rawDescOnce.Do(func() {
+		file_api_protos_cart_cart_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_protos_cart_cart_proto_rawDesc), len(file_api_protos_cart_cart_proto_rawDesc)))
+	})
+	return file_api_protos_cart_cart_proto_rawDescData
+}
+
+var file_api_protos_cart_cart_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
+var file_api_protos_cart_cart_proto_goTypes = []any{
+	(*AddItemRequest)(nil),             // 0: cart.AddItemRequest
+	(*AddItemResponse)(nil),            // 1: cart.AddItemResponse
+	(*BatchItem)(nil),                  // 2: cart.BatchItem
+	(*AddItemsRequest)(nil),            // 3: cart.AddItemsRequest
+	(*BatchItemResult)(nil),            // 4: cart.BatchItemResult
+	(*AddItemsResponse)(nil),           // 5: cart.AddItemsResponse
+	(*RemoveItemRequest)(nil),          // 6: cart.RemoveItemRequest
+	(*RemoveItemResponse)(nil),         // 7: cart.RemoveItemResponse
+	(*SetItemQuantityRequest)(nil),     // 8: cart.SetItemQuantityRequest
+	(*SetItemQuantityResponse)(nil),    // 9: cart.SetItemQuantityResponse
+	(*ChangeItemQuantityRequest)(nil),  // 10: cart.ChangeItemQuantityRequest
+	(*ChangeItemQuantityResponse)(nil), // 11: cart.ChangeItemQuantityResponse
+	(*ClearCartRequest)(nil),           // 12: cart.ClearCartRequest
+	(*ClearCartResponse)(nil),          // 13: cart.ClearCartResponse
+	(*GetCartRequest)(nil),             // 14: cart.GetCartRequest
+	(*Money)(nil),                      // 15: cart.Money
+	(*CartItem)(nil),                   // 16: cart.CartItem
+	(*AppliedPromotion)(nil),           // 17: cart.AppliedPromotion
+	(*GetCartResponse)(nil),            // 18: cart.GetCartResponse
+	(*TaxLine)(nil),                    // 19: cart.TaxLine
+	(*CheckoutRequest)(nil),            // 20: cart.CheckoutRequest
+	(*CheckoutResponse)(nil),           // 21: cart.CheckoutResponse
+	(*ApplyCouponRequest)(nil),         // 22: cart.ApplyCouponRequest
+	(*ApplyCouponResponse)(nil),        // 23: cart.ApplyCouponResponse
+	(*RemoveCouponRequest)(nil),        // 24: cart.RemoveCouponRequest
+	(*RemoveCouponResponse)(nil),       // 25: cart.RemoveCouponResponse
+	(*SetRegionRequest)(nil),           // 26: cart.SetRegionRequest
+	(*SetRegionResponse)(nil),          // 27: cart.SetRegionResponse
+}
+var file_api_protos_cart_cart_proto_depIdxs = []int32{
+	2,  // 0: cart.AddItemsRequest.items:type_name -> cart.BatchItem
+	4,  // 1: cart.AddItemsResponse.results:type_name -> cart.BatchItemResult
+	15, // 2: cart.CartItem.price:type_name -> cart.Money
+	15, // 3: cart.CartItem.previousPrice:type_name -> cart.Money
+	15, // 4: cart.CartItem.discount:type_name -> cart.Money
+	15, // 5: cart.AppliedPromotion.discount:type_name -> cart.Money
+	16, // 6: cart.GetCartResponse.items:type_name -> cart.CartItem
+	15, // 7: cart.GetCartResponse.totalPrice:type_name -> cart.Money
+	17, // 8: cart.GetCartResponse.promotions:type_name -> cart.AppliedPromotion
+	15, // 9: cart.GetCartResponse.discount:type_name -> cart.Money
+	15, // 10: cart.GetCartResponse.discountedTotal:type_name -> cart.Money
+	15, // 11: cart.GetCartResponse.subtotal:type_name -> cart.Money
+	19, // 12: cart.GetCartResponse.taxes:type_name -> cart.TaxLine
+	15, // 13: cart.GetCartResponse.grandTotal:type_name -> cart.Money
+	15, // 14: cart.TaxLine.taxable:type_name -> cart.Money
+	15, // 15: cart.TaxLine.amount:type_name -> cart.Money
+	0,  // 16: cart.Cart.AddItem:input_type -> cart.AddItemRequest
+	6,  // 17: cart.Cart.RemoveItem:input_type -> cart.RemoveItemRequest
+	3,  // 18: cart.Cart.AddItems:input_type -> cart.AddItemsRequest
+	8,  // 19: cart.Cart.SetItemQuantity:input_type -> cart.SetItemQuantityRequest
+	10, // 20: cart.Cart.ChangeItemQuantity:input_type -> cart.ChangeItemQuantityRequest
+	12, // 21: cart.Cart.ClearCart:input_type -> cart.ClearCartRequest
+	14, // 22: cart.Cart.GetCart:input_type -> cart.GetCartRequest
+	20, // 23: cart.Cart.Checkout:input_type -> cart.CheckoutRequest
+	22, // 24: cart.Cart.ApplyCoupon:input_type -> cart.ApplyCouponRequest
+	24, // 25: cart.Cart.RemoveCoupon:input_type -> cart.RemoveCouponRequest
+	26, // 26: cart.Cart.SetRegion:input_type -> cart.SetRegionRequest
+	1,  // 27: cart.Cart.AddItem:output_type -> cart.AddItemResponse
+	7,  // 28: cart.Cart.RemoveItem:output_type -> cart.RemoveItemResponse
+	5,  // 29: cart.Cart.AddItems:output_type -> cart.AddItemsResponse
+	9,  // 30: cart.Cart.SetItemQuantity:output_type -> cart.SetItemQuantityResponse
+	11, // 31: cart.Cart.ChangeItemQuantity:output_type -> cart.ChangeItemQuantityResponse
+	13, // 32: cart.Cart.ClearCart:output_type -> cart.ClearCartResponse
+	18, // 33: cart.Cart.GetCart:output_type -> cart.GetCartResponse
+	21, // 34: cart.Cart.Checkout:output_type -> cart.CheckoutResponse
+	23, // 35: cart.Cart.ApplyCoupon:output_type -> cart.ApplyCouponResponse
+	25, // 36: cart.Cart.RemoveCoupon:output_type -> cart.RemoveCouponResponse
+	27, // 37: cart.Cart.SetRegion:output_type -> cart.SetRegionResponse
+	27, // [27:38] is the sub-list for method output_type
+	16, // [16:27] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_api_protos_cart_cart_proto_init() }
+func file_api_protos_cart_cart_proto_init() {
+	if File_api_protos_cart_cart_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_protos_cart_cart_proto_rawDesc), len(file_api_protos_cart_cart_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   28,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_protos_cart_cart_proto_goTypes,
+		DependencyIndexes: file_api_protos_cart_cart_proto_depIdxs,
+		MessageInfos:      file_api_protos_cart_cart_proto_msgTypes,
+	}.Build()
+	File_api_protos_cart_cart_proto = out.File
+	file_api_protos_cart_cart_proto_goTypes = nil
+	file_api_protos_cart_cart_proto_depIdxs = nil
+}
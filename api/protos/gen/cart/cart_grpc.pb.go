@@ -0,0 +1,527 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: api/protos/cart/cart.proto
+
+package cart
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Cart_AddItem_FullMethodName            = "/cart.Cart/AddItem"
+	Cart_RemoveItem_FullMethodName         = "/cart.Cart/RemoveItem"
+	Cart_AddItems_FullMethodName           = "/cart.Cart/AddItems"
+	Cart_SetItemQuantity_FullMethodName    = "/cart.Cart/SetItemQuantity"
+	Cart_ChangeItemQuantity_FullMethodName = "/cart.Cart/ChangeItemQuantity"
+	Cart_ClearCart_FullMethodName          = "/cart.Cart/ClearCart"
+	Cart_GetCart_FullMethodName            = "/cart.Cart/GetCart"
+	Cart_Checkout_FullMethodName           = "/cart.Cart/Checkout"
+	Cart_ApplyCoupon_FullMethodName        = "/cart.Cart/ApplyCoupon"
+	Cart_RemoveCoupon_FullMethodName       = "/cart.Cart/RemoveCoupon"
+	Cart_SetRegion_FullMethodName          = "/cart.Cart/SetRegion"
+)
+
+// CartClient is the client API for Cart service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CartClient interface {
+	// AddItem adds an item to the user's cart
+	AddItem(ctx context.Context, in *AddItemRequest, opts ...grpc.CallOption) (*AddItemResponse, error)
+	// RemoveItem removes an item from the user's cart
+	RemoveItem(ctx context.Context, in *RemoveItemRequest, opts ...grpc.CallOption) (*RemoveItemResponse, error)
+	// AddItems adds a batch of items to the user's cart in a single update;
+	// entries that cannot be added are reported instead of failing the call
+	AddItems(ctx context.Context, in *AddItemsRequest, opts ...grpc.CallOption) (*AddItemsResponse, error)
+	// SetItemQuantity sets the quantity of an item in the user's cart; zero removes it
+	SetItemQuantity(ctx context.Context, in *SetItemQuantityRequest, opts ...grpc.CallOption) (*SetItemQuantityResponse, error)
+	// ChangeItemQuantity adds a signed delta to the quantity of an item in the user's cart,
+	// removing the item if the quantity drops to zero or below
+	ChangeItemQuantity(ctx context.Context, in *ChangeItemQuantityRequest, opts ...grpc.CallOption) (*ChangeItemQuantityResponse, error)
+	// ClearCart removes all items from the user's cart
+	ClearCart(ctx context.Context, in *ClearCartRequest, opts ...grpc.CallOption) (*ClearCartResponse, error)
+	// GetCart retrieves the cart contents
+	GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*GetCartResponse, error)
+	// Checkout creates an order from the cart and clears it
+	Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*CheckoutResponse, error)
+	// ApplyCoupon applies a coupon to the user's cart, replacing the previous one
+	ApplyCoupon(ctx context.Context, in *ApplyCouponRequest, opts ...grpc.CallOption) (*ApplyCouponResponse, error)
+	// RemoveCoupon removes the coupon from the user's cart
+	RemoveCoupon(ctx context.Context, in *RemoveCouponRequest, opts ...grpc.CallOption) (*RemoveCouponResponse, error)
+	// SetRegion sets the region the user's cart is delivered to, which decides its taxes
+	SetRegion(ctx context.Context, in *SetRegionRequest, opts ...grpc.CallOption) (*SetRegionResponse, error)
+}
+
+type cartClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCartClient(cc grpc.ClientConnInterface) CartClient {
+	return &cartClient{cc}
+}
+
+func (c *cartClient) AddItem(ctx context.Context, in *AddItemRequest, opts ...grpc.CallOption) (*AddItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddItemResponse)
+	err := c.cc.Invoke(ctx, Cart_AddItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) RemoveItem(ctx context.Context, in *RemoveItemRequest, opts ...grpc.CallOption) (*RemoveItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveItemResponse)
+	err := c.cc.Invoke(ctx, Cart_RemoveItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) AddItems(ctx context.Context, in *AddItemsRequest, opts ...grpc.CallOption) (*AddItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddItemsResponse)
+	err := c.cc.Invoke(ctx, Cart_AddItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) SetItemQuantity(ctx context.Context, in *SetItemQuantityRequest, opts ...grpc.CallOption) (*SetItemQuantityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetItemQuantityResponse)
+	err := c.cc.Invoke(ctx, Cart_SetItemQuantity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) ChangeItemQuantity(ctx context.Context, in *ChangeItemQuantityRequest, opts ...grpc.CallOption) (*ChangeItemQuantityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeItemQuantityResponse)
+	err := c.cc.Invoke(ctx, Cart_ChangeItemQuantity_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) ClearCart(ctx context.Context, in *ClearCartRequest, opts ...grpc.CallOption) (*ClearCartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClearCartResponse)
+	err := c.cc.Invoke(ctx, Cart_ClearCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) GetCart(ctx context.Context, in *GetCartRequest, opts ...grpc.CallOption) (*GetCartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCartResponse)
+	err := c.cc.Invoke(ctx, Cart_GetCart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) Checkout(ctx context.Context, in *CheckoutRequest, opts ...grpc.CallOption) (*CheckoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckoutResponse)
+	err := c.cc.Invoke(ctx, Cart_Checkout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) ApplyCoupon(ctx context.Context, in *ApplyCouponRequest, opts ...grpc.CallOption) (*ApplyCouponResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyCouponResponse)
+	err := c.cc.Invoke(ctx, Cart_ApplyCoupon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) RemoveCoupon(ctx context.Context, in *RemoveCouponRequest, opts ...grpc.CallOption) (*RemoveCouponResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveCouponResponse)
+	err := c.cc.Invoke(ctx, Cart_RemoveCoupon_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cartClient) SetRegion(ctx context.Context, in *SetRegionRequest, opts ...grpc.CallOption) (*SetRegionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetRegionResponse)
+	err := c.cc.Invoke(ctx, Cart_SetRegion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CartServer is the server API for Cart service.
+// All implementations must embed UnimplementedCartServer
+// for forward compatibility.
+type CartServer interface {
+	// AddItem adds an item to the user's cart
+	AddItem(context.Context, *AddItemRequest) (*AddItemResponse, error)
+	// RemoveItem removes an item from the user's cart
+	RemoveItem(context.Context, *RemoveItemRequest) (*RemoveItemResponse, error)
+	// AddItems adds a batch of items to the user's cart in a single update;
+	// entries that cannot be added are reported instead of failing the call
+	AddItems(context.Context, *AddItemsRequest) (*AddItemsResponse, error)
+	// SetItemQuantity sets the quantity of an item in the user's cart; zero removes it
+	SetItemQuantity(context.Context, *SetItemQuantityRequest) (*SetItemQuantityResponse, error)
+	// ChangeItemQuantity adds a signed delta to the quantity of an item in the user's cart,
+	// removing the item if the quantity drops to zero or below
+	ChangeItemQuantity(context.Context, *ChangeItemQuantityRequest) (*ChangeItemQuantityResponse, error)
+	// ClearCart removes all items from the user's cart
+	ClearCart(context.Context, *ClearCartRequest) (*ClearCartResponse, error)
+	// GetCart retrieves the cart contents
+	GetCart(context.Context, *GetCartRequest) (*GetCartResponse, error)
+	// Checkout creates an order from the cart and clears it
+	Checkout(context.Context, *CheckoutRequest) (*CheckoutResponse, error)
+	// ApplyCoupon applies a coupon to the user's cart, replacing the previous one
+	ApplyCoupon(context.Context, *ApplyCouponRequest) (*ApplyCouponResponse, error)
+	// RemoveCoupon removes the coupon from the user's cart
+	RemoveCoupon(context.Context, *RemoveCouponRequest) (*RemoveCouponResponse, error)
+	// SetRegion sets the region the user's cart is delivered to, which decides its taxes
+	SetRegion(context.Context, *SetRegionRequest) (*SetRegionResponse, error)
+	mustEmbedUnimplementedCartServer()
+}
+
+// UnimplementedCartServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCartServer struct{}
+
+func (UnimplementedCartServer) AddItem(context.Context, *AddItemRequest) (*AddItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddItem not implemented")
+}
+func (UnimplementedCartServer) RemoveItem(context.Context, *RemoveItemRequest) (*RemoveItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveItem not implemented")
+}
+func (UnimplementedCartServer) AddItems(context.Context, *AddItemsRequest) (*AddItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddItems not implemented")
+}
+func (UnimplementedCartServer) SetItemQuantity(context.Context, *SetItemQuantityRequest) (*SetItemQuantityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetItemQuantity not implemented")
+}
+func (UnimplementedCartServer) ChangeItemQuantity(context.Context, *ChangeItemQuantityRequest) (*ChangeItemQuantityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeItemQuantity not implemented")
+}
+func (UnimplementedCartServer) ClearCart(context.Context, *ClearCartRequest) (*ClearCartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearCart not implemented")
+}
+func (UnimplementedCartServer) GetCart(context.Context, *GetCartRequest) (*GetCartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCart not implemented")
+}
+func (UnimplementedCartServer) Checkout(context.Context, *CheckoutRequest) (*CheckoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Checkout not implemented")
+}
+func (UnimplementedCartServer) ApplyCoupon(context.Context, *ApplyCouponRequest) (*ApplyCouponResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyCoupon not implemented")
+}
+func (UnimplementedCartServer) RemoveCoupon(context.Context, *RemoveCouponRequest) (*RemoveCouponResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveCoupon not implemented")
+}
+func (UnimplementedCartServer) SetRegion(context.Context, *SetRegionRequest) (*SetRegionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetRegion not implemented")
+}
+func (UnimplementedCartServer) mustEmbedUnimplementedCartServer() {}
+func (UnimplementedCartServer) testEmbeddedByValue()              {}
+
+// UnsafeCartServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CartServer will
+// result in compilation errors.
+type UnsafeCartServer interface {
+	mustEmbedUnimplementedCartServer()
+}
+
+func RegisterCartServer(s grpc.ServiceRegistrar, srv CartServer) {
+	// If the following call pancis, it indicates UnimplementedCartServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Cart_ServiceDesc, srv)
+}
+
+func _Cart_AddItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).AddItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_AddItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).AddItem(ctx, req.(*AddItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_RemoveItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).RemoveItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_RemoveItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).RemoveItem(ctx, req.(*RemoveItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_AddItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).AddItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_AddItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).AddItems(ctx, req.(*AddItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_SetItemQuantity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetItemQuantityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).SetItemQuantity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_SetItemQuantity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).SetItemQuantity(ctx, req.(*SetItemQuantityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_ChangeItemQuantity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeItemQuantityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).ChangeItemQuantity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_ChangeItemQuantity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).ChangeItemQuantity(ctx, req.(*ChangeItemQuantityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_ClearCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).ClearCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_ClearCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).ClearCart(ctx, req.(*ClearCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_GetCart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCartRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).GetCart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_GetCart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).GetCart(ctx, req.(*GetCartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_Checkout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).Checkout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_Checkout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).Checkout(ctx, req.(*CheckoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_ApplyCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).ApplyCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_ApplyCoupon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).ApplyCoupon(ctx, req.(*ApplyCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_RemoveCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).RemoveCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_RemoveCoupon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).RemoveCoupon(ctx, req.(*RemoveCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cart_SetRegion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetRegionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CartServer).SetRegion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Cart_SetRegion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CartServer).SetRegion(ctx, req.(*SetRegionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Cart_ServiceDesc is the grpc.ServiceDesc for Cart service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Cart_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cart.Cart",
+	HandlerType: (*CartServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddItem",
+			Handler:    _Cart_AddItem_Handler,
+		},
+		{
+			MethodName: "RemoveItem",
+			Handler:    _Cart_RemoveItem_Handler,
+		},
+		{
+			MethodName: "AddItems",
+			Handler:    _Cart_AddItems_Handler,
+		},
+		{
+			MethodName: "SetItemQuantity",
+			Handler:    _Cart_SetItemQuantity_Handler,
+		},
+		{
+			MethodName: "ChangeItemQuantity",
+			Handler:    _Cart_ChangeItemQuantity_Handler,
+		},
+		{
+			MethodName: "ClearCart",
+			Handler:    _Cart_ClearCart_Handler,
+		},
+		{
+			MethodName: "GetCart",
+			Handler:    _Cart_GetCart_Handler,
+		},
+		{
+			MethodName: "Checkout",
+			Handler:    _Cart_Checkout_Handler,
+		},
+		{
+			MethodName: "ApplyCoupon",
+			Handler:    _Cart_ApplyCoupon_Handler,
+		},
+		{
+			MethodName: "RemoveCoupon",
+			Handler:    _Cart_RemoveCoupon_Handler,
+		},
+		{
+			MethodName: "SetRegion",
+			Handler:    _Cart_SetRegion_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/protos/cart/cart.proto",
+}
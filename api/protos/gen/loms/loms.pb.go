@@ -22,9 +22,13 @@ const (
 )
 
 type Item struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Sku           uint32                 `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
-	Count         uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Sku   uint32                 `protobuf:"varint,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Count uint32                 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// price is what the customer pays for all count items, discounts included,
+	// in minor units of currency
+	Price         int64  `protobuf:"varint,3,opt,name=price,proto3" json:"price,omitempty"`
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,6 +77,20 @@ func (x *Item) GetCount() uint32 {
 	return 0
 }
 
+func (x *Item) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Item) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type OrderCreateRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	User          int64                  `protobuf:"varint,1,opt,name=user,proto3" json:"user,omitempty"`
@@ -525,10 +543,12 @@ var File_api_protos_loms_loms_proto protoreflect.FileDescriptor
 
 const file_api_protos_loms_loms_proto_rawDesc = "" +
 	"\n" +
-	"\x1aapi/protos/loms/loms.proto\x12\x04loms\".\n" +
+	"\x1aapi/protos/loms/loms.proto\x12\x04loms\"`\n" +
 	"\x04Item\x12\x10\n" +
 	"\x03sku\x18\x01 \x01(\rR\x03sku\x12\x14\n" +
-	"\x05count\x18\x02 \x01(\rR\x05count\"J\n" +
+	"\x05count\x18\x02 \x01(\rR\x05count\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x03R\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"J\n" +
 	"\x12OrderCreateRequest\x12\x12\n" +
 	"\x04user\x18\x01 \x01(\x03R\x04user\x12 \n" +
 	"\x05items\x18\x02 \x03(\v2\n" +
@@ -4,6 +4,7 @@ import (
 	"context"
 	"flag"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,6 +14,7 @@ import (
 	"route256/cart/config"
 	"route256/cart/internal/app"
 	"route256/cart/internal/infrastructure/api"
+	"route256/cart/internal/infrastructure/tracing"
 )
 
 func main() {
@@ -26,18 +28,30 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Set up tracing before any instrumented component is created
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		Exporter:    cfg.Tracing.Exporter,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		ServiceName: cfg.Tracing.ServiceName,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+
 	// Create application
 	app := app.NewApp(cfg)
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:              cfg.Server.Port,
-		Handler:           api.LoggingMiddleware(app.Mux),
+		Handler:           api.LoggingMiddleware(api.TracingMiddleware(api.MetricsMiddleware(app.Mux))),
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	// Channel to listen for errors coming from the listener.
-	serverErrors := make(chan error, 1)
+	// Channel to listen for errors coming from the listeners.
+	serverErrors := make(chan error, 2)
 
 	// Start the service listening for requests.
 	go func() {
@@ -45,6 +59,17 @@ func main() {
 		serverErrors <- server.ListenAndServe()
 	}()
 
+	// Start the gRPC server on its own port.
+	grpcListener, err := net.Listen("tcp", cfg.GRPCServer.Port)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC: %v", err)
+	}
+
+	go func() {
+		log.Printf("Starting gRPC server on %s", grpcListener.Addr())
+		serverErrors <- app.GRPCServer.Serve(grpcListener)
+	}()
+
 	// Channel to listen for an interrupt or terminate signal from the OS.
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
@@ -61,6 +86,13 @@ func main() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		// Stop the gRPC server in parallel, sharing the same deadline.
+		grpcStopped := make(chan struct{})
+		go func() {
+			app.GRPCServer.GracefulStop()
+			close(grpcStopped)
+		}()
+
 		// Asking listener to shut down and shed load.
 		if err := server.Shutdown(ctx); err != nil {
 			log.Printf("Graceful shutdown did not complete in 5s: %v", err)
@@ -68,6 +100,24 @@ func main() {
 				log.Fatalf("Could not stop server: %v", err)
 			}
 		}
+
+		select {
+		case <-grpcStopped:
+		case <-ctx.Done():
+			log.Printf("Graceful gRPC shutdown did not complete in 5s")
+			app.GRPCServer.Stop()
+		}
+	}
+
+	if err := app.Close(); err != nil {
+		log.Printf("Failed to close application: %v", err)
+	}
+
+	// Flush spans recorded before shutdown.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Failed to flush traces: %v", err)
 	}
 
 	log.Println("Server stopped")
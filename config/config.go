@@ -13,20 +13,133 @@ type Config struct {
 		Port string `yaml:"port"`
 	} `yaml:"server"`
 
+	GRPCServer struct {
+		Port string `yaml:"port"`
+	} `yaml:"grpc_server"`
+
 	ProductService struct {
 		URL   string `yaml:"url"`
 		Token string `yaml:"token"`
 	} `yaml:"product_service"`
 
+	ProductRateLimit struct {
+		// RPS is the average number of requests per second to the product service,
+		// Burst the number allowed at once; a zero RPS disables the limiter
+		RPS   float64 `yaml:"rps"`
+		Burst int     `yaml:"burst"`
+	} `yaml:"product_rate_limit"`
+
+	ProductCache struct {
+		Size int `yaml:"size"`
+		// TTL and NegativeTTL are in seconds; a zero NegativeTTL disables caching unknown SKUs
+		TTL         int `yaml:"ttl"`
+		NegativeTTL int `yaml:"negative_ttl"`
+	} `yaml:"product_cache"`
+
 	HTTPClient struct {
-		Timeout    int `yaml:"timeout"`
-		MaxRetries int `yaml:"max_retries"`
-		Backoff    int `yaml:"backoff"`
+		// Timeout, Backoff, MaxBackoff and MaxRetryTime are in seconds
+		Timeout      int `yaml:"timeout"`
+		MaxRetries   int `yaml:"max_retries"`
+		Backoff      int `yaml:"backoff"`
+		MaxBackoff   int `yaml:"max_backoff"`
+		MaxRetryTime int `yaml:"max_retry_time"`
+		// RetryServerErrors retries 502/503/504 and network errors on idempotent requests
+		RetryServerErrors bool `yaml:"retry_server_errors"`
 	} `yaml:"http_client"`
 
+	ExchangeRates struct {
+		// File is a YAML file with exchange rates; empty disables currency conversion
+		File string `yaml:"file"`
+	} `yaml:"exchange_rates"`
+
+	Promotions struct {
+		// File is a YAML file with promotions and coupons; empty disables discounts
+		File string `yaml:"file"`
+	} `yaml:"promotions"`
+
+	Taxes struct {
+		// File is a YAML file with tax rules; empty disables taxes
+		File string `yaml:"file"`
+	} `yaml:"taxes"`
+
+	CartLimits struct {
+		// MaxQuantityPerSKU bounds the quantity of every product, SKUMaxQuantity
+		// overrides it for some; zero values mean no limit
+		MaxQuantityPerSKU uint64            `yaml:"max_quantity_per_sku"`
+		SKUMaxQuantity    map[uint32]uint64 `yaml:"sku_max_quantity"`
+		MaxDistinctSKUs   int               `yaml:"max_distinct_skus"`
+		MaxTotalItems     uint64            `yaml:"max_total_items"`
+	} `yaml:"cart_limits"`
+
+	CartExpiration struct {
+		// TTL is how long carts are kept unchanged, in seconds; zero keeps them forever.
+		// Expired carts are deleted every Interval seconds, BatchSize at a time.
+		TTL       int `yaml:"ttl"`
+		Interval  int `yaml:"interval"`
+		BatchSize int `yaml:"batch_size"`
+	} `yaml:"cart_expiration"`
+
+	AbandonedCarts struct {
+		// Thresholds are how long carts stay unchanged before their owners are
+		// notified, in seconds, once per threshold; none disables notifications.
+		// Carts are scanned every Interval seconds, BatchSize at a time.
+		Thresholds []int `yaml:"thresholds"`
+		Interval   int   `yaml:"interval"`
+		BatchSize  int   `yaml:"batch_size"`
+		// Notifier is "log", "file" or "webhook": events are logged, appended to
+		// File or posted to WebhookURL, waiting at most WebhookTimeout seconds.
+		// NoticesFile keeps track of sent notifications across restarts if set;
+		// the webhook notifier requires it.
+		Notifier       string `yaml:"notifier"`
+		File           string `yaml:"file"`
+		WebhookURL     string `yaml:"webhook_url"`
+		WebhookTimeout int    `yaml:"webhook_timeout"`
+		NoticesFile    string `yaml:"notices_file"`
+	} `yaml:"abandoned_carts"`
+
+	GuestCarts struct {
+		// Enabled serves carts to guests known by a token. Secret signs the
+		// tokens and must be set if guest carts are enabled.
+		// MergePolicy is "sum", "max" or "guest", "sum" if empty.
+		Enabled     bool   `yaml:"enabled"`
+		Secret      string `yaml:"secret"`
+		MergePolicy string `yaml:"merge_policy"`
+	} `yaml:"guest_carts"`
+
 	LOMS struct {
 		Address string `yaml:"address"`
 	} `yaml:"loms"`
+
+	CircuitBreaker struct {
+		// FailureThreshold consecutive failures open a breaker for OpenTimeout seconds,
+		// after which HalfOpenMaxCalls successful probes close it again
+		FailureThreshold int `yaml:"failure_threshold"`
+		OpenTimeout      int `yaml:"open_timeout"`
+		HalfOpenMaxCalls int `yaml:"half_open_max_calls"`
+	} `yaml:"circuit_breaker"`
+
+	Tracing struct {
+		// Exporter is one of "none", "stdout" or "otlp"
+		Exporter    string  `yaml:"exporter"`
+		Endpoint    string  `yaml:"endpoint"`
+		Insecure    bool    `yaml:"insecure"`
+		ServiceName string  `yaml:"service_name"`
+		SampleRatio float64 `yaml:"sample_ratio"`
+	} `yaml:"tracing"`
+
+	Idempotency struct {
+		// TTL is how long checkout idempotency keys are remembered, in seconds
+		TTL int `yaml:"ttl"`
+	} `yaml:"idempotency"`
+
+	Repository struct {
+		// Type selects the cart storage: "inmemory" or "file"
+		Type string `yaml:"type"`
+		File struct {
+			Dir           string `yaml:"dir"`
+			SnapshotEvery int    `yaml:"snapshot_every"`
+		} `yaml:"file"`
+	} `yaml:"repository"`
 }
 
 // Load loads configuration from a YAML file
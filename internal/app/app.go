@@ -1,41 +1,98 @@
 package app
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"time"
 
+	cartpb "route256/cart/api/protos/gen/cart"
 	"route256/cart/config"
+	"route256/cart/internal/domain/models"
 	"route256/cart/internal/domain/ports"
 	"route256/cart/internal/infrastructure/api"
+	"route256/cart/internal/infrastructure/breaker"
 	"route256/cart/internal/infrastructure/client"
+	"route256/cart/internal/infrastructure/exchange"
+	"route256/cart/internal/infrastructure/grpcserver"
+	"route256/cart/internal/infrastructure/guest"
+	"route256/cart/internal/infrastructure/idempotency"
 	"route256/cart/internal/infrastructure/loms"
+	"route256/cart/internal/infrastructure/metrics"
+	"route256/cart/internal/infrastructure/notifier"
+	"route256/cart/internal/infrastructure/promotions"
+	"route256/cart/internal/infrastructure/repository/file"
 	"route256/cart/internal/infrastructure/repository/inmemory"
+	"route256/cart/internal/infrastructure/tax"
+	"route256/cart/internal/infrastructure/tracing"
+	"route256/cart/internal/usecase/abandoned"
 	"route256/cart/internal/usecase/cart"
+	"route256/cart/internal/usecase/expiration"
+	"route256/cart/internal/usecase/promotion"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
 )
 
 // App represents the application
 type App struct {
-	Mux     *http.ServeMux
-	Service ports.CartService
+	Mux        *http.ServeMux
+	GRPCServer *grpc.Server
+	Service    ports.CartService
+
+	repo    ports.CartRepository
+	reaper  *expiration.Reaper
+	scanner *abandoned.Scanner
+	closers []io.Closer
 }
 
 // NewApp creates a new application instance
 func NewApp(cfg *config.Config) *App {
-	// Create HTTP client with retry middleware
+	// Create HTTP client with retry and rate limit middlewares.
+	// Every retry waits for its own rate limiter token.
+	var transport http.RoundTripper = otelhttp.NewTransport(http.DefaultTransport)
+	if cfg.ProductRateLimit.RPS > 0 {
+		transport = client.NewRateLimitMiddleware(
+			transport,
+			cfg.ProductRateLimit.RPS,
+			cfg.ProductRateLimit.Burst,
+		).OnWait(metrics.RateLimitWaitObserver("product"))
+	}
+
 	httpClient := &http.Client{
-		Timeout: 5 * time.Second,
+		Timeout: time.Duration(cfg.HTTPClient.Timeout) * time.Second,
 		Transport: client.NewRetryMiddleware(
-			http.DefaultTransport,
-			3,
-			time.Second,
-		),
+			transport,
+			client.RetryPolicy{
+				MaxRetries:        cfg.HTTPClient.MaxRetries,
+				BaseBackoff:       time.Duration(cfg.HTTPClient.Backoff) * time.Second,
+				MaxBackoff:        time.Duration(cfg.HTTPClient.MaxBackoff) * time.Second,
+				MaxRetryTime:      time.Duration(cfg.HTTPClient.MaxRetryTime) * time.Second,
+				RetryServerErrors: cfg.HTTPClient.RetryServerErrors,
+			},
+		).OnRetry(metrics.RetryObserver("product")),
 	}
 
-	// Create product service client
-	productClient := client.NewProductClient(
-		cfg.ProductService.URL,
-		cfg.ProductService.Token,
-		httpClient,
+	breakerSettings := breaker.Settings{
+		FailureThreshold: cfg.CircuitBreaker.FailureThreshold,
+		OpenTimeout:      time.Duration(cfg.CircuitBreaker.OpenTimeout) * time.Second,
+		HalfOpenMaxCalls: cfg.CircuitBreaker.HalfOpenMaxCalls,
+	}
+	productBreaker := breaker.New("product", breakerSettings, breaker.IsProductFailure)
+	lomsBreaker := breaker.New("loms", breakerSettings, breaker.IsLOMSFailure)
+
+	// Create product service client behind a circuit breaker and a cache
+	productClient := client.NewProductCache(
+		breaker.NewProductService(metrics.NewProductService(client.NewProductClient(
+			cfg.ProductService.URL,
+			cfg.ProductService.Token,
+			httpClient,
+		)), productBreaker),
+		cfg.ProductCache.Size,
+		time.Duration(cfg.ProductCache.TTL)*time.Second,
+		time.Duration(cfg.ProductCache.NegativeTTL)*time.Second,
 	)
 
 	// Create LOMS client
@@ -43,20 +100,259 @@ func NewApp(cfg *config.Config) *App {
 	if err != nil {
 		panic(err)
 	}
+	lomsClient = breaker.NewLOMSClient(metrics.NewLOMSClient(lomsClient), lomsBreaker)
+
+	// Create cart repository
+	repo, err := newCartRepository(cfg)
+	if err != nil {
+		panic(err)
+	}
+
+	if counter, ok := repo.(metrics.CartCounter); ok {
+		if err := metrics.RegisterCartCount(counter); err != nil {
+			panic(err)
+		}
+	}
+
+	// Load exchange rates
+	var exchangeRates ports.ExchangeRateProvider
+	if cfg.ExchangeRates.File != "" {
+		exchangeRates, err = exchange.NewFileProvider(cfg.ExchangeRates.File)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// Load promotions
+	var promotionEngine *promotion.Engine
+	if cfg.Promotions.File != "" {
+		promotionEngine, err = promotions.LoadFile(cfg.Promotions.File)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	// Load tax rules
+	var taxes ports.TaxCalculator
+	if cfg.Taxes.File != "" {
+		taxes, err = tax.NewRuleTable(cfg.Taxes.File)
+		if err != nil {
+			panic(err)
+		}
+	}
 
-	// Create in-memory cart repository
-	repo := inmemory.NewCartRepository()
+	measuredRepo := metrics.NewCartRepository(repo)
 
 	// Create cart service
-	cartService := cart.NewCartService(repo, productClient, lomsClient)
+	cartService := tracing.NewCartService(cart.NewCartService(
+		measuredRepo,
+		productClient,
+		lomsClient,
+		exchangeRates,
+		promotionEngine,
+		taxes,
+		models.CartLimits{
+			MaxQuantityPerSKU: cfg.CartLimits.MaxQuantityPerSKU,
+			SKUMaxQuantity:    cfg.CartLimits.SKUMaxQuantity,
+			MaxDistinctSKUs:   cfg.CartLimits.MaxDistinctSKUs,
+			MaxTotalItems:     cfg.CartLimits.MaxTotalItems,
+		},
+	))
 
 	// Create HTTP router
 	mux := http.NewServeMux()
-	handler := api.NewHandler(cartService)
+	idempotencyStore := idempotency.NewStore(time.Duration(cfg.Idempotency.TTL) * time.Second)
+	handler := api.NewHandler(cartService, idempotencyStore)
+	if cfg.GuestCarts.Enabled {
+		guests, policy, err := newGuestCarts(cfg)
+		if err != nil {
+			panic(err)
+		}
+		handler.WithGuests(guests, policy)
+	}
 	api.RegisterRoutes(mux, handler)
+	api.RegisterHealthRoute(mux, api.NewHealthHandler(healthBreaker{productBreaker}, healthBreaker{lomsBreaker}))
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	// Create gRPC server
+	grpcServer := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(grpcserver.LoggingInterceptor),
+	)
+	cartpb.RegisterCartServer(grpcServer, grpcserver.NewServer(cartService))
+
+	// Delete carts nobody touched for long in the background
+	var reaper *expiration.Reaper
+	if cfg.CartExpiration.TTL > 0 {
+		interval := time.Duration(cfg.CartExpiration.Interval) * time.Second
+		if interval <= 0 {
+			interval = time.Minute
+		}
+
+		reaper = expiration.NewReaper(
+			measuredRepo,
+			time.Duration(cfg.CartExpiration.TTL)*time.Second,
+			interval,
+			cfg.CartExpiration.BatchSize,
+		)
+		reaper.Start()
+	}
+
+	// Notify about carts left unchanged for long in the background
+	var (
+		scanner *abandoned.Scanner
+		closers []io.Closer
+	)
+	if len(cfg.AbandonedCarts.Thresholds) > 0 {
+		scanner, closers, err = newAbandonedCartScanner(cfg, measuredRepo)
+		if err != nil {
+			panic(err)
+		}
+		scanner.Start()
+	}
 
 	return &App{
-		Mux:     mux,
-		Service: cartService,
+		Mux:        mux,
+		GRPCServer: grpcServer,
+		Service:    cartService,
+		repo:       repo,
+		reaper:     reaper,
+		scanner:    scanner,
+		closers:    closers,
+	}
+}
+
+// Close stops background work and releases resources held by the application
+func (a *App) Close() error {
+	if a.reaper != nil {
+		a.reaper.Stop()
+	}
+	if a.scanner != nil {
+		a.scanner.Stop()
+	}
+
+	err := closeAll(a.closers)
+	if closer, ok := a.repo.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// closeAll closes every closer and returns their errors joined
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, closer := range closers {
+		errs = append(errs, closer.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// newAbandonedCartScanner creates the abandoned cart scanner configured along
+// with the files it holds open
+func newAbandonedCartScanner(cfg *config.Config, repo ports.CartRepository) (*abandoned.Scanner, []io.Closer, error) {
+	thresholds := make([]time.Duration, len(cfg.AbandonedCarts.Thresholds))
+	for i, seconds := range cfg.AbandonedCarts.Thresholds {
+		thresholds[i] = time.Duration(seconds) * time.Second
+	}
+
+	interval := time.Duration(cfg.AbandonedCarts.Interval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	var (
+		sink    ports.AbandonedCartNotifier
+		closers []io.Closer
+	)
+	switch cfg.AbandonedCarts.Notifier {
+	case "", "log":
+		sink = notifier.NewLog()
+	case "file":
+		f, err := notifier.NewFile(cfg.AbandonedCarts.File)
+		if err != nil {
+			return nil, nil, err
+		}
+		sink = f
+		closers = append(closers, f)
+	case "webhook":
+		if cfg.AbandonedCarts.WebhookURL == "" {
+			return nil, nil, errors.New("abandoned_carts.webhook_url is not set")
+		}
+		// Otherwise every restart would notify about all abandoned carts again
+		if cfg.AbandonedCarts.NoticesFile == "" {
+			return nil, nil, errors.New("abandoned_carts.notices_file is not set")
+		}
+		sink = notifier.NewWebhook(cfg.AbandonedCarts.WebhookURL, &http.Client{
+			Timeout:   time.Duration(cfg.AbandonedCarts.WebhookTimeout) * time.Second,
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
+		})
+	default:
+		return nil, nil, fmt.Errorf("unknown abandoned cart notifier: %q", cfg.AbandonedCarts.Notifier)
+	}
+
+	notices, err := notifier.NewNotices(cfg.AbandonedCarts.NoticesFile)
+	if err != nil {
+		return nil, nil, errors.Join(err, closeAll(closers))
+	}
+	closers = append(closers, notices)
+
+	scanner, err := abandoned.NewScanner(
+		repo,
+		metrics.NewAbandonedCartNotifier(sink),
+		notices,
+		thresholds,
+		interval,
+		cfg.AbandonedCarts.BatchSize,
+	)
+	if err != nil {
+		return nil, nil, errors.Join(err, closeAll(closers))
 	}
+
+	return scanner, closers, nil
+}
+
+// newGuestCarts creates the guest cart tokens and the merge policy configured
+func newGuestCarts(cfg *config.Config) (*guest.Tokens, models.MergePolicy, error) {
+	policy := models.MergeSum
+	if cfg.GuestCarts.MergePolicy != "" {
+		var err error
+		if policy, err = models.ParseMergePolicy(cfg.GuestCarts.MergePolicy); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Tokens signed with a random secret would stop working on restart
+	if cfg.GuestCarts.Secret == "" {
+		return nil, "", errors.New("guest_carts.secret is not set")
+	}
+
+	tokens, err := guest.NewTokens([]byte(cfg.GuestCarts.Secret))
+	if err != nil {
+		return nil, "", err
+	}
+
+	return tokens, policy, nil
+}
+
+// newCartRepository creates the cart repository selected in the config
+func newCartRepository(cfg *config.Config) (ports.CartRepository, error) {
+	switch cfg.Repository.Type {
+	case "", "inmemory":
+		return inmemory.NewCartRepository(), nil
+	case "file":
+		return file.NewCartRepository(cfg.Repository.File.Dir, cfg.Repository.File.SnapshotEvery)
+	default:
+		return nil, fmt.Errorf("unknown repository type: %q", cfg.Repository.Type)
+	}
+}
+
+// healthBreaker reports the state of a circuit breaker to the health handler
+// by its name: "closed", "open" or "half-open"
+type healthBreaker struct {
+	*breaker.Breaker
+}
+
+// State implements api.CircuitBreaker
+func (b healthBreaker) State() string {
+	return b.Breaker.State().String()
 }
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// AbandonedCart is the event sent about a cart left unchanged for long
+type AbandonedCart struct {
+	// Cart is the cart as stored; item names and discounts are not filled in
+	Cart *Cart
+
+	// Stage is the number of idle thresholds the cart has crossed, starting
+	// at 1, and Threshold the last of them
+	Stage     int
+	Threshold time.Duration
+
+	// DetectedAt is when the scanner found the cart idle
+	DetectedAt time.Time
+}
+
+// AbandonedCartNotice records that the owner of an idle cart was notified
+type AbandonedCartNotice struct {
+	UserID int64
+
+	// CartUpdatedAt is when the cart was last changed before the notice.
+	// A cart changed since is idle anew and may be notified about again.
+	CartUpdatedAt time.Time
+
+	// Stage is the stage of the last notification sent
+	Stage int
+
+	SentAt time.Time
+}
@@ -0,0 +1,19 @@
+package models
+
+// MaxBatchItems bounds the number of entries of a batch the APIs accept
+const MaxBatchItems = 100
+
+// BatchItem is one entry of a batch of items added to a cart
+type BatchItem struct {
+	SKU   uint32
+	Count uint16
+}
+
+// BatchItemResult is the outcome of adding one entry of a batch
+type BatchItemResult struct {
+	SKU   uint32
+	Count uint16
+
+	// Err is why the entry was not added, nil if it was
+	Err error
+}
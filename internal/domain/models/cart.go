@@ -1,13 +1,42 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
 
 var (
 	ErrCartNotFound      = errors.New("cart not found")
 	ErrCartAlreadyExists = errors.New("cart already exists")
 	ErrProductNotFound   = errors.New("product not found")
+	ErrProductGone       = errors.New("product is no longer available")
+	ErrVersionConflict   = errors.New("cart version conflict")
+	ErrCouponNotFound    = errors.New("coupon not found")
+
+	ErrDependencyUnavailable = errors.New("dependency unavailable")
 )
 
+// AnyVersion is used as an expected version when the caller has no precondition
+const AnyVersion uint64 = 0
+
+// VersionConflictError is returned when a cart was modified concurrently
+type VersionConflictError struct {
+	UserID   int64
+	Expected uint64
+	Actual   uint64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("cart version conflict for user %d: expected %d, actual %d", e.UserID, e.Expected, e.Actual)
+}
+
+// Is makes VersionConflictError match ErrVersionConflict
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
 // Cart represents a shopping cart containing selected items and their total price.
 type Cart struct {
 	// UserID uniquely identifies the user who owns the cart
@@ -16,8 +45,43 @@ type Cart struct {
 	// Items is the collection of products in the cart
 	Items ItemList
 
-	// TotalPrice is the sum of all items' prices in cents
-	TotalPrice uint32
+	// TotalPrice is the sum of all items' prices
+	TotalPrice Money
+
+	// Coupon is the coupon code the customer applied, if any
+	Coupon string
+
+	// Promotions lists the promotions that discount the cart, Discount is the
+	// sum of their discounts and DiscountedTotal is what the customer pays.
+	// They are computed when the cart is read, see ApplyDiscounts.
+	Promotions      []AppliedPromotion
+	Discount        Money
+	DiscountedTotal Money
+
+	// Region is where the cart is delivered to; it decides the tax rates.
+	// Empty means the default region of the tax calculator.
+	Region string
+
+	// UnknownRegion is the region of a cart read after the region was dropped
+	// from the tax rules; Region is then empty and the cart is taxed at the
+	// default rates. It is computed when the cart is read.
+	UnknownRegion string
+
+	// Taxes lists the taxes included in the discounted total, Subtotal is the
+	// discounted total without them and GrandTotal what the customer pays
+	// including them, which is the discounted total. They are computed when
+	// the cart is read, see ApplyTaxes.
+	Taxes      []TaxLine
+	Subtotal   Money
+	GrandTotal Money
+
+	// Version is incremented by the repository on every stored change;
+	// zero means the cart has never been stored
+	Version uint64
+
+	// UpdatedAt is when the cart was last changed, set by the repository on
+	// every stored change. Carts that stay unchanged for long expire.
+	UpdatedAt time.Time
 }
 
 // NewCart creates a new empty cart for the given user
@@ -28,6 +92,30 @@ func NewCart(userID int64) *Cart {
 	}
 }
 
+// Clone returns a deep copy of the cart
+func (c *Cart) Clone() *Cart {
+	clone := *c
+	clone.Items = make(ItemList, len(c.Items))
+	copy(clone.Items, c.Items)
+	clone.Promotions = slices.Clone(c.Promotions)
+	clone.Taxes = slices.Clone(c.Taxes)
+	return &clone
+}
+
+// CheckVersion returns a VersionConflictError unless the cart has the expected version.
+// AnyVersion matches every cart.
+func (c *Cart) CheckVersion(expected uint64) error {
+	if expected == AnyVersion || expected == c.Version {
+		return nil
+	}
+
+	return &VersionConflictError{
+		UserID:   c.UserID,
+		Expected: expected,
+		Actual:   c.Version,
+	}
+}
+
 // AddItem adds an item to the cart or updates its quantity if it exists
 func (c *Cart) AddItem(item Item) {
 	for i, existingItem := range c.Items {
@@ -39,27 +127,265 @@ func (c *Cart) AddItem(item Item) {
 	c.Items = append(c.Items, item)
 }
 
-// RemoveItem removes an item from the cart
-func (c *Cart) RemoveItem(sku uint32) {
+// Quantity returns the quantity of sku in the cart, zero if it has none
+func (c *Cart) Quantity(sku uint32) uint16 {
+	for _, item := range c.Items {
+		if item.SKU == sku {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+// SetQuantity sets the quantity of sku in the cart. A cart without the item
+// gets a new one at price; zero removes the item.
+func (c *Cart) SetQuantity(sku uint32, quantity uint16, price Money) {
+	if quantity == 0 {
+		c.RemoveItem(sku)
+		return
+	}
+
+	for i := range c.Items {
+		if c.Items[i].SKU == sku {
+			c.Items[i].Quantity = quantity
+			return
+		}
+	}
+	c.Items = append(c.Items, Item{
+		SKU:      sku,
+		Quantity: quantity,
+		Price:    price,
+	})
+}
+
+// RemoveItem removes an item from the cart and reports whether it was present
+func (c *Cart) RemoveItem(sku uint32) bool {
 	for i, item := range c.Items {
 		if item.SKU == sku {
 			c.Items = append(c.Items[:i], c.Items[i+1:]...)
-			return
+			return true
 		}
 	}
+	return false
 }
 
-// Clear removes all items from the cart
+// Clear removes all items and the coupon from the cart
 func (c *Cart) Clear() {
 	c.Items = make(ItemList, 0)
-	c.TotalPrice = 0
+	c.TotalPrice = Money{}
+	c.Coupon = ""
+	c.resetDiscounts()
 }
 
-// CalculateTotalPrice calculates the total price of all items in the cart
-func (c *Cart) CalculateTotalPrice() {
-	total := uint32(0)
+// Reprice updates item prices to the given current prices by SKU and
+// recalculates the total. Items without a current price keep theirs.
+// Changed items remember the price the customer first saw, which is
+// forgotten again if the price goes back to it or the change is acknowledged
+// (see AcknowledgePrices). Reports whether any price
+// changed; on error the cart must be discarded. See CalculateTotalPrice for convert.
+func (c *Cart) Reprice(prices map[uint32]Money, convert ConvertFunc) (bool, error) {
+	changed := false
+	for i := range c.Items {
+		item := &c.Items[i]
+
+		price, ok := prices[item.SKU]
+		if !ok || price == item.Price {
+			continue
+		}
+
+		switch {
+		case item.PreviousPrice.IsZero():
+			item.PreviousPrice = item.Price
+		case item.PreviousPrice == price:
+			item.PreviousPrice = Money{}
+		}
+		item.Price = price
+		changed = true
+	}
+
+	if !changed {
+		return false, nil
+	}
+	return true, c.CalculateTotalPrice(convert)
+}
+
+// PriceChanged reports whether the price of any item has changed since it was added
+func (c *Cart) PriceChanged() bool {
 	for _, item := range c.Items {
-		total += item.Price * uint32(item.Quantity)
+		if item.PriceChanged() {
+			return true
+		}
+	}
+	return false
+}
+
+// AcknowledgePrices forgets the prices items had before they changed once the
+// customer has seen the current ones
+func (c *Cart) AcknowledgePrices() {
+	for i := range c.Items {
+		c.Items[i].PreviousPrice = Money{}
 	}
+}
+
+// Currency returns the currency of the cart total: that of the first item,
+// or DefaultCurrency for an empty cart
+func (c *Cart) Currency() string {
+	if len(c.Items) == 0 || c.Items[0].Price.Currency == "" {
+		return DefaultCurrency
+	}
+	return c.Items[0].Price.Currency
+}
+
+// CalculateTotalPrice calculates the total price of all items in the cart in
+// its currency. Prices in other currencies are converted with convert; without
+// it such carts fail with ErrCurrencyMismatch. Line totals are the unit price,
+// converted and rounded first, times the quantity. It fails without changing
+// the cart if the total overflows. Discounts and taxes are reset.
+func (c *Cart) CalculateTotalPrice(convert ConvertFunc) error {
+	currency := c.Currency()
+
+	total := NewMoney(0, currency)
+	for _, item := range c.Items {
+		unitPrice, err := convertMoney(item.Price, currency, convert)
+		if err != nil {
+			return err
+		}
+
+		price, err := unitPrice.Mul(int64(item.Quantity))
+		if err != nil {
+			return err
+		}
+
+		total, err = total.Add(price)
+		if err != nil {
+			return err
+		}
+	}
+
 	c.TotalPrice = total
+	c.resetDiscounts()
+	return nil
+}
+
+// ApplyDiscounts sets the discounts of items by SKU and the promotions they
+// come from, and computes the discounted total. Discounts must be in the
+// currency of the total and must not exceed the prices of the items they
+// apply to. It fails without changing the cart otherwise.
+func (c *Cart) ApplyDiscounts(discounts map[uint32]Money, promotions []AppliedPromotion) error {
+	items := slices.Clone(c.Items)
+
+	discount := NewMoney(0, c.TotalPrice.Currency)
+	for i := range items {
+		item := &items[i]
+		item.Discount = Money{}
+
+		amount := discounts[item.SKU]
+		if amount.IsZero() {
+			continue
+		}
+
+		item.Discount = amount
+		finalPrice, err := item.FinalPrice()
+		if err != nil {
+			return err
+		}
+		if amount.Amount < 0 || finalPrice.Amount < 0 {
+			return fmt.Errorf("invalid discount %s on %d x %s", amount, item.Quantity, item.Price)
+		}
+
+		if discount, err = discount.Add(amount); err != nil {
+			return err
+		}
+	}
+
+	discountedTotal, err := c.TotalPrice.Sub(discount)
+	if err != nil {
+		return err
+	}
+
+	c.Items = items
+	c.Promotions = slices.Clone(promotions)
+	c.Discount = discount
+	c.DiscountedTotal = discountedTotal
+	c.resetTaxes()
+	return nil
+}
+
+// ApplyTaxes sets the taxes included in the prices of the cart and computes
+// the subtotal without them. Call after ApplyDiscounts, if at all; taxes must
+// be in the currency of the total and add up to at most the discounted total.
+// It fails without changing the cart otherwise.
+func (c *Cart) ApplyTaxes(taxes []TaxLine) error {
+	subtotal := c.DiscountedTotal
+	for _, tax := range taxes {
+		var err error
+		if subtotal, err = subtotal.Sub(tax.Amount); err != nil {
+			return err
+		}
+	}
+	if subtotal.Amount < 0 {
+		return fmt.Errorf("taxes exceed the total of %s", c.DiscountedTotal)
+	}
+
+	c.Taxes = slices.Clone(taxes)
+	c.Subtotal = subtotal
+	c.GrandTotal = c.DiscountedTotal
+	return nil
+}
+
+// ConvertTo returns a copy of the cart with item prices and the total in currency
+func (c *Cart) ConvertTo(currency string, convert ConvertFunc) (*Cart, error) {
+	converted := c.Clone()
+	for i := range converted.Items {
+		item := &converted.Items[i]
+
+		var err error
+		if item.Price, err = convertMoney(item.Price, currency, convert); err != nil {
+			return nil, err
+		}
+		if item.PriceChanged() {
+			if item.PreviousPrice, err = convertMoney(item.PreviousPrice, currency, convert); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := converted.CalculateTotalPrice(nil); err != nil {
+		return nil, err
+	}
+	converted.TotalPrice.Currency = currency
+	converted.resetDiscounts()
+	return converted, nil
+}
+
+// resetDiscounts removes all discounts and taxes from the cart
+func (c *Cart) resetDiscounts() {
+	for i := range c.Items {
+		c.Items[i].Discount = Money{}
+	}
+	c.Promotions = nil
+	c.Discount = NewMoney(0, c.TotalPrice.Currency)
+	c.DiscountedTotal = c.TotalPrice
+	c.resetTaxes()
+}
+
+// resetTaxes removes all taxes from the cart
+func (c *Cart) resetTaxes() {
+	c.Taxes = nil
+	c.Subtotal = c.DiscountedTotal
+	c.GrandTotal = c.DiscountedTotal
+}
+
+// convertMoney converts amount to currency unless it already is in it
+func convertMoney(amount Money, currency string, convert ConvertFunc) (Money, error) {
+	switch {
+	case amount.Currency == currency:
+		return amount, nil
+	case amount == Money{}:
+		return NewMoney(0, currency), nil
+	}
+	if convert == nil {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, amount.Currency, currency)
+	}
+	return convert(amount, currency)
 }
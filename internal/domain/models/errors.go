@@ -0,0 +1,25 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DependencyUnavailableError is returned when an external service is known to be down
+// and calls to it are rejected without being attempted
+type DependencyUnavailableError struct {
+	// Dependency names the unavailable service
+	Dependency string
+
+	// RetryAfter is how long until the service will be tried again
+	RetryAfter time.Duration
+}
+
+func (e *DependencyUnavailableError) Error() string {
+	return fmt.Sprintf("%s is unavailable, retry after %s", e.Dependency, e.RetryAfter)
+}
+
+// Is makes DependencyUnavailableError match ErrDependencyUnavailable
+func (e *DependencyUnavailableError) Is(target error) bool {
+	return target == ErrDependencyUnavailable
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidMergePolicy = errors.New("invalid merge policy")
+
+// MergePolicy decides the quantity of a product that is both in a guest cart
+// merged into a user's cart and in the user's cart itself
+type MergePolicy string
+
+const (
+	// MergeSum adds the quantities up
+	MergeSum MergePolicy = "sum"
+
+	// MergeMax keeps the larger quantity
+	MergeMax MergePolicy = "max"
+
+	// MergePreferGuest keeps the quantity of the guest cart
+	MergePreferGuest MergePolicy = "guest"
+)
+
+// ParseMergePolicy parses the name of a merge policy
+func ParseMergePolicy(name string) (MergePolicy, error) {
+	switch policy := MergePolicy(name); policy {
+	case MergeSum, MergeMax, MergePreferGuest:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidMergePolicy, name)
+	}
+}
+
+// Quantity returns the merged quantity of a product the user has current of
+// and the guest cart has guest of
+func (p MergePolicy) Quantity(current, guest uint64) uint64 {
+	switch p {
+	case MergeMax:
+		return max(current, guest)
+	case MergePreferGuest:
+		return guest
+	default:
+		return current + guest
+	}
+}
+
+// IsGuestCart reports whether id identifies a guest cart rather than a
+// user's: guest carts are stored under negative IDs, which users never have
+func IsGuestCart(id int64) bool {
+	return id < 0
+}
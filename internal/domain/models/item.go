@@ -11,6 +11,42 @@ type Item struct {
 	// Quantity is the number of items
 	Quantity uint16
 
-	// Price is the price of one item in cents
-	Price uint32
+	// Price is the price of one item
+	Price Money
+
+	// PreviousPrice is the price the customer saw before the product price
+	// changed; zero if the price has not changed since the item was added
+	PreviousPrice Money
+
+	// Discount is the amount taken off the price of all items by promotions;
+	// zero if there is none. Like Name, it is computed when the cart is read.
+	Discount Money
+
+	// Name is the product name. It is not stored with the cart but filled
+	// in from the product service when the cart is read.
+	Name string
+
+	// Unavailable is set when the product was removed from the catalog; the
+	// item keeps its last known price. Like Name, it is computed when the
+	// cart is read.
+	Unavailable bool
+}
+
+// PriceChanged reports whether the price has changed since the item was added
+func (i Item) PriceChanged() bool {
+	return !i.PreviousPrice.IsZero()
+}
+
+// LineTotal returns the price of all items before discounts
+func (i Item) LineTotal() (Money, error) {
+	return i.Price.Mul(int64(i.Quantity))
+}
+
+// FinalPrice returns the price of all items after discounts
+func (i Item) FinalPrice() (Money, error) {
+	total, err := i.LineTotal()
+	if err != nil {
+		return Money{}, err
+	}
+	return total.Sub(i.Discount)
 }
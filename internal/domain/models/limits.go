@@ -0,0 +1,108 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrLimitExceeded is matched by LimitExceededError
+var ErrLimitExceeded = errors.New("cart limit exceeded")
+
+// Limits that a LimitExceededError may name
+const (
+	LimitSKUQuantity  = "sku_quantity"
+	LimitDistinctSKUs = "distinct_skus"
+	LimitTotalItems   = "total_items"
+)
+
+// LimitExceededError is returned when a change would make a cart exceed one of its limits
+type LimitExceededError struct {
+	// Limit names the limit, one of the Limit constants
+	Limit string
+
+	// Max is the value of the limit
+	Max uint64
+
+	// SKU is the product whose quantity is limited, for LimitSKUQuantity
+	SKU uint32
+}
+
+func (e *LimitExceededError) Error() string {
+	switch e.Limit {
+	case LimitSKUQuantity:
+		return fmt.Sprintf("cart limit exceeded: at most %d items of sku %d per cart", e.Max, e.SKU)
+	case LimitDistinctSKUs:
+		return fmt.Sprintf("cart limit exceeded: at most %d different products per cart", e.Max)
+	default:
+		return fmt.Sprintf("cart limit exceeded: at most %d items per cart", e.Max)
+	}
+}
+
+// Is makes LimitExceededError match ErrLimitExceeded
+func (e *LimitExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// CartLimits bounds the contents of carts. Zero values mean no limit.
+type CartLimits struct {
+	// MaxQuantityPerSKU bounds the quantity of every product
+	MaxQuantityPerSKU uint64
+
+	// SKUMaxQuantity overrides MaxQuantityPerSKU for some products
+	SKUMaxQuantity map[uint32]uint64
+
+	// MaxDistinctSKUs bounds the number of different products
+	MaxDistinctSKUs int
+
+	// MaxTotalItems bounds the sum of all quantities
+	MaxTotalItems uint64
+}
+
+// CheckQuantity returns a LimitExceededError if the cart would exceed a limit
+// with its quantity of sku set to quantity. Only limits that the change makes
+// worse are checked, so that a cart exceeding limits lowered after it was
+// filled can still shrink. Quantities never exceed math.MaxUint16.
+func (l CartLimits) CheckQuantity(cart *Cart, sku uint32, quantity uint64) error {
+	var current, others uint64
+	distinct := 0
+	for _, item := range cart.Items {
+		if item.SKU == sku {
+			current = uint64(item.Quantity)
+			continue
+		}
+		others += uint64(item.Quantity)
+		distinct++
+	}
+
+	if quantity <= current {
+		return nil
+	}
+
+	if maxQuantity := l.maxQuantity(sku); quantity > maxQuantity {
+		return &LimitExceededError{Limit: LimitSKUQuantity, Max: maxQuantity, SKU: sku}
+	}
+
+	if l.MaxDistinctSKUs > 0 && current == 0 && distinct+1 > l.MaxDistinctSKUs {
+		return &LimitExceededError{Limit: LimitDistinctSKUs, Max: uint64(l.MaxDistinctSKUs)}
+	}
+
+	if l.MaxTotalItems > 0 && others+quantity > l.MaxTotalItems {
+		return &LimitExceededError{Limit: LimitTotalItems, Max: l.MaxTotalItems}
+	}
+
+	return nil
+}
+
+// maxQuantity returns the largest quantity of sku allowed in a cart
+func (l CartLimits) maxQuantity(sku uint32) uint64 {
+	maxQuantity := l.MaxQuantityPerSKU
+	if override, ok := l.SKUMaxQuantity[sku]; ok {
+		maxQuantity = override
+	}
+
+	if maxQuantity == 0 || maxQuantity > math.MaxUint16 {
+		return math.MaxUint16
+	}
+	return maxQuantity
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCartLimits_CheckQuantity(t *testing.T) {
+	limits := CartLimits{
+		MaxQuantityPerSKU: 10,
+		SKUMaxQuantity:    map[uint32]uint64{1: 2},
+		MaxDistinctSKUs:   3,
+		MaxTotalItems:     20,
+	}
+
+	cart := &Cart{Items: ItemList{
+		{SKU: 1, Quantity: 1},
+		{SKU: 3, Quantity: 5},
+		{SKU: 4, Quantity: 5},
+	}}
+
+	tests := []struct {
+		name     string
+		limits   CartLimits
+		sku      uint32
+		quantity uint64
+		want     *LimitExceededError
+	}{
+		{name: "within limits", limits: limits, sku: 3, quantity: 10},
+		{name: "over default sku limit", limits: limits, sku: 3, quantity: 11, want: &LimitExceededError{Limit: LimitSKUQuantity, Max: 10, SKU: 3}},
+		{name: "over sku override", limits: limits, sku: 1, quantity: 3, want: &LimitExceededError{Limit: LimitSKUQuantity, Max: 2, SKU: 1}},
+		{name: "too many products", limits: limits, sku: 5, quantity: 1, want: &LimitExceededError{Limit: LimitDistinctSKUs, Max: 3}},
+		{name: "within total items", limits: limits, sku: 4, quantity: 10},
+		{name: "too many items in total", limits: CartLimits{MaxTotalItems: 12}, sku: 4, quantity: 7, want: &LimitExceededError{Limit: LimitTotalItems, Max: 12}},
+		{name: "decrease over a lowered limit", limits: CartLimits{MaxQuantityPerSKU: 1}, sku: 3, quantity: 4},
+		{name: "no limits", sku: 6, quantity: math.MaxUint16},
+		{name: "quantity overflow", sku: 6, quantity: math.MaxUint16 + 1, want: &LimitExceededError{Limit: LimitSKUQuantity, Max: math.MaxUint16, SKU: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.CheckQuantity(cart, tt.sku, tt.quantity)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrLimitExceeded)
+			assert.Equal(t, tt.want, err)
+		})
+	}
+
+	assert.EqualError(t, &LimitExceededError{Limit: LimitDistinctSKUs, Max: 3},
+		"cart limit exceeded: at most 3 different products per cart")
+}
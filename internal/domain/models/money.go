@@ -0,0 +1,210 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// DefaultCurrency is the currency of prices that come without one
+const DefaultCurrency = "RUB"
+
+var (
+	ErrMoneyOverflow    = errors.New("money amount overflows")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// ConvertFunc converts an amount of money to another currency
+type ConvertFunc func(amount Money, currency string) (Money, error)
+
+// minorUnitDigits lists the ISO 4217 currencies whose minor unit is not a hundredth
+var minorUnitDigits = map[string]int{
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+}
+
+// MinorUnitDigits returns the number of decimal digits of the minor unit of currency
+func MinorUnitDigits(currency string) int {
+	if digits, ok := minorUnitDigits[currency]; ok {
+		return digits
+	}
+	return 2
+}
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 currency code
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Money is an amount in minor currency units (e.g. kopecks) with an ISO 4217 currency code.
+// Arithmetic is checked: it fails instead of silently wrapping around.
+type Money struct {
+	// Amount is the amount in minor units
+	Amount int64
+
+	// Currency is the ISO 4217 currency code
+	Currency string
+}
+
+// NewMoney creates an amount of money in minor units
+func NewMoney(amount int64, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: currency,
+	}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add returns m + other. A zero amount without a currency adds to any currency.
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: %s + %s", ErrMoneyOverflow, m, other)
+	}
+
+	return NewMoney(sum, currency), nil
+}
+
+// Sub returns m - other. A zero amount without a currency subtracts from any currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("%w: %s - %s", ErrMoneyOverflow, m, other)
+	}
+
+	return m.Add(NewMoney(-other.Amount, other.Currency))
+}
+
+// Mul returns m multiplied by n
+func (m Money) Mul(n int64) (Money, error) {
+	if m.Amount == 0 || n == 0 {
+		return NewMoney(0, m.Currency), nil
+	}
+
+	product := m.Amount * n
+	if product/n != m.Amount || (m.Amount == -1 && n == math.MinInt64) || (n == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: %s * %d", ErrMoneyOverflow, m, n)
+	}
+
+	return NewMoney(product, m.Currency), nil
+}
+
+// Convert converts m to currency at rate, the value of one major unit of
+// m.Currency in major units of currency. The result is rounded to the minor
+// unit of currency, halves away from zero.
+func (m Money) Convert(currency string, rate *big.Rat) (Money, error) {
+	if m.Currency == currency {
+		return m, nil
+	}
+
+	// Rescale from the minor unit of the source currency to that of the target one
+	scale := new(big.Rat).SetFrac(
+		new(big.Int).SetUint64(pow10(MinorUnitDigits(currency))),
+		new(big.Int).SetUint64(pow10(MinorUnitDigits(m.Currency))),
+	)
+
+	converted, err := m.MulRat(scale.Mul(scale, rate))
+	if err != nil {
+		return Money{}, err
+	}
+
+	return NewMoney(converted.Amount, currency), nil
+}
+
+// MulRat returns m multiplied by a fraction, rounded to the minor unit
+// halves away from zero
+func (m Money) MulRat(r *big.Rat) (Money, error) {
+	value := new(big.Rat).SetInt64(m.Amount)
+	value.Mul(value, r)
+
+	amount := roundHalfAwayFromZero(value)
+	if !amount.IsInt64() {
+		return Money{}, fmt.Errorf("%w: %s * %s", ErrMoneyOverflow, m, r.RatString())
+	}
+
+	return NewMoney(amount.Int64(), m.Currency), nil
+}
+
+// String formats the amount in major units, e.g. "12.34 RUB"
+func (m Money) String() string {
+	sign := ""
+	amount := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := MinorUnitDigits(m.Currency)
+	if digits == 0 {
+		return fmt.Sprintf("%s%d %s", sign, amount, m.Currency)
+	}
+
+	unit := pow10(digits)
+	return fmt.Sprintf("%s%d.%0*d %s", sign, amount/unit, digits, amount%unit, m.Currency)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Besides objects it accepts bare
+// numbers, which is how carts stored before amounts had a currency hold prices.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var amount int64
+	if err := json.Unmarshal(data, &amount); err == nil {
+		*m = NewMoney(amount, DefaultCurrency)
+		return nil
+	}
+
+	type money Money
+	return json.Unmarshal(data, (*money)(m))
+}
+
+// commonCurrency returns the currency of the result of combining m and other
+func (m Money) commonCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == other.Currency:
+		return m.Currency, nil
+	case m.Currency == "" && m.Amount == 0:
+		return other.Currency, nil
+	case other.Currency == "" && other.Amount == 0:
+		return m.Currency, nil
+	default:
+		return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+}
+
+// roundHalfAwayFromZero rounds a rational number to an integer
+func roundHalfAwayFromZero(value *big.Rat) *big.Int {
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+
+	// Round up if |rem| / denom >= 1/2
+	if rem.Mul(rem.Abs(rem), big.NewInt(2)).Cmp(value.Denom()) >= 0 {
+		quo.Add(quo, big.NewInt(int64(value.Sign())))
+	}
+	return quo
+}
+
+// pow10 returns 10 to the power of n
+func pow10(n int) uint64 {
+	result := uint64(1)
+	for range n {
+		result *= 10
+	}
+	return result
+}
@@ -0,0 +1,210 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoney_Arithmetic(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      func() (Money, error)
+		want    Money
+		wantErr error
+	}{
+		{
+			name: "add",
+			op:   func() (Money, error) { return NewMoney(150, "RUB").Add(NewMoney(250, "RUB")) },
+			want: NewMoney(400, "RUB"),
+		},
+		{
+			name: "add to zero value",
+			op:   func() (Money, error) { return Money{}.Add(NewMoney(250, "RUB")) },
+			want: NewMoney(250, "RUB"),
+		},
+		{
+			name:    "add other currency",
+			op:      func() (Money, error) { return NewMoney(150, "RUB").Add(NewMoney(250, "USD")) },
+			wantErr: ErrCurrencyMismatch,
+		},
+		{
+			name:    "add overflows",
+			op:      func() (Money, error) { return NewMoney(math.MaxInt64, "RUB").Add(NewMoney(1, "RUB")) },
+			wantErr: ErrMoneyOverflow,
+		},
+		{
+			name: "sub",
+			op:   func() (Money, error) { return NewMoney(150, "RUB").Sub(NewMoney(250, "RUB")) },
+			want: NewMoney(-100, "RUB"),
+		},
+		{
+			name:    "sub overflows",
+			op:      func() (Money, error) { return NewMoney(0, "RUB").Sub(NewMoney(math.MinInt64, "RUB")) },
+			wantErr: ErrMoneyOverflow,
+		},
+		{
+			name: "mul",
+			op:   func() (Money, error) { return NewMoney(100000, "RUB").Mul(65535) },
+			want: NewMoney(6553500000, "RUB"),
+		},
+		{
+			name:    "mul overflows",
+			op:      func() (Money, error) { return NewMoney(math.MaxInt64/2+1, "RUB").Mul(2) },
+			wantErr: ErrMoneyOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMoney_Convert(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   Money
+		currency string
+		rate     *big.Rat
+		want     Money
+	}{
+		{name: "exact", amount: NewMoney(1000, "USD"), currency: "RUB", rate: big.NewRat(185, 2), want: NewMoney(92500, "RUB")},
+		{name: "rounds half up", amount: NewMoney(1, "RUB"), currency: "USD", rate: big.NewRat(1, 2), want: NewMoney(1, "USD")},
+		{name: "rounds down", amount: NewMoney(1, "RUB"), currency: "USD", rate: big.NewRat(49, 100), want: NewMoney(0, "USD")},
+		{name: "rounds negative half away from zero", amount: NewMoney(-1, "RUB"), currency: "USD", rate: big.NewRat(1, 2), want: NewMoney(-1, "USD")},
+		{name: "to currency without minor unit", amount: NewMoney(1050, "USD"), currency: "JPY", rate: big.NewRat(150, 1), want: NewMoney(1575, "JPY")},
+		{name: "from currency without minor unit", amount: NewMoney(1575, "JPY"), currency: "USD", rate: big.NewRat(1, 150), want: NewMoney(1050, "USD")},
+		{name: "same currency", amount: NewMoney(1234, "RUB"), currency: "RUB", rate: big.NewRat(2, 1), want: NewMoney(1234, "RUB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.amount.Convert(tt.currency, tt.rate)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := NewMoney(math.MaxInt64, "USD").Convert("RUB", big.NewRat(2, 1))
+	assert.ErrorIs(t, err, ErrMoneyOverflow)
+}
+
+func TestCart_ConvertTo(t *testing.T) {
+	// 1 USD = 92.50 RUB
+	convert := func(amount Money, currency string) (Money, error) {
+		rate := big.NewRat(185, 2)
+		if amount.Currency == "RUB" {
+			rate.Inv(rate)
+		}
+		return amount.Convert(currency, rate)
+	}
+
+	cart := &Cart{Items: ItemList{
+		{SKU: 1, Quantity: 3, Price: NewMoney(10000, "RUB")},
+		{SKU: 2, Quantity: 2, Price: NewMoney(150, "USD"), PreviousPrice: NewMoney(100, "USD")},
+	}}
+
+	// Mixed carts need exchange rates
+	assert.ErrorIs(t, cart.CalculateTotalPrice(nil), ErrCurrencyMismatch)
+	require.NoError(t, cart.CalculateTotalPrice(convert))
+	assert.Equal(t, NewMoney(57750, "RUB"), cart.TotalPrice)
+
+	// Line totals are rounded unit prices times quantities
+	converted, err := cart.ConvertTo("USD", convert)
+	require.NoError(t, err)
+	assert.Equal(t, ItemList{
+		{SKU: 1, Quantity: 3, Price: NewMoney(108, "USD")},
+		{SKU: 2, Quantity: 2, Price: NewMoney(150, "USD"), PreviousPrice: NewMoney(100, "USD")},
+	}, converted.Items)
+	assert.Equal(t, NewMoney(624, "USD"), converted.TotalPrice)
+
+	// The original cart is left alone
+	assert.Equal(t, NewMoney(10000, "RUB"), cart.Items[0].Price)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "12.34 RUB", NewMoney(1234, "RUB").String())
+	assert.Equal(t, "-0.05 USD", NewMoney(-5, "USD").String())
+	assert.Equal(t, "-92233720368547758.08 RUB", NewMoney(math.MinInt64, "RUB").String())
+	assert.Equal(t, "1575 JPY", NewMoney(1575, "JPY").String())
+	assert.Equal(t, "1.005 KWD", NewMoney(1005, "KWD").String())
+}
+
+func TestMoney_UnmarshalJSON(t *testing.T) {
+	var m Money
+	require.NoError(t, json.Unmarshal([]byte(`{"Amount":1234,"Currency":"USD"}`), &m))
+	assert.Equal(t, NewMoney(1234, "USD"), m)
+
+	// Carts stored before amounts had a currency
+	require.NoError(t, json.Unmarshal([]byte(`1234`), &m))
+	assert.Equal(t, NewMoney(1234, DefaultCurrency), m)
+}
+
+func TestCart_CalculateTotalPriceLargeCart(t *testing.T) {
+	// Used to wrap around uint32
+	cart := &Cart{Items: ItemList{{SKU: 1, Quantity: 65535, Price: NewMoney(100000, "RUB")}}}
+	require.NoError(t, cart.CalculateTotalPrice(nil))
+	assert.Equal(t, NewMoney(6553500000, "RUB"), cart.TotalPrice)
+}
+
+func FuzzMoney_Mul(f *testing.F) {
+	f.Add(int64(100000), int64(65535))
+	f.Add(int64(math.MaxInt64), int64(2))
+	f.Add(int64(-1), int64(math.MinInt64))
+
+	f.Fuzz(func(t *testing.T, amount, n int64) {
+		got, err := NewMoney(amount, "RUB").Mul(n)
+
+		want := new(big.Int).Mul(big.NewInt(amount), big.NewInt(n))
+		if !want.IsInt64() {
+			assert.ErrorIs(t, err, ErrMoneyOverflow)
+			return
+		}
+		require.NoError(t, err)
+		assert.Equal(t, want.Int64(), got.Amount)
+	})
+}
+
+func FuzzCart_CalculateTotalPrice(f *testing.F) {
+	f.Add(int64(100000), uint16(65535), int64(2202), uint16(3), int64(1), uint16(1))
+	f.Add(int64(math.MaxInt64), uint16(1), int64(1), uint16(1), int64(0), uint16(0))
+	f.Add(int64(math.MaxInt64/65535), uint16(65535), int64(math.MaxInt64/2), uint16(2), int64(0), uint16(0))
+
+	f.Fuzz(func(t *testing.T, price1 int64, qty1 uint16, price2 int64, qty2 uint16, price3 int64, qty3 uint16) {
+		cart := &Cart{Items: ItemList{
+			{SKU: 1, Quantity: qty1, Price: NewMoney(price1, "RUB")},
+			{SKU: 2, Quantity: qty2, Price: NewMoney(price2, "RUB")},
+			{SKU: 3, Quantity: qty3, Price: NewMoney(price3, "RUB")},
+		}}
+		before := cart.TotalPrice
+		err := cart.CalculateTotalPrice(nil)
+
+		// Every partial sum must fit, not just the total
+		want, overflow := new(big.Int), false
+		for _, item := range cart.Items {
+			line := new(big.Int).Mul(big.NewInt(item.Price.Amount), big.NewInt(int64(item.Quantity)))
+			want.Add(want, line)
+			overflow = overflow || !line.IsInt64() || !want.IsInt64()
+		}
+
+		if overflow {
+			assert.ErrorIs(t, err, ErrMoneyOverflow)
+			assert.Equal(t, before, cart.TotalPrice)
+			return
+		}
+		require.NoError(t, err)
+		assert.Equal(t, NewMoney(want.Int64(), "RUB"), cart.TotalPrice)
+	})
+}
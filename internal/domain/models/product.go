@@ -8,6 +8,6 @@ type Product struct {
 	// Name is the product name
 	Name string
 
-	// Price is the product price in cents
-	Price uint32
+	// Price is the product price
+	Price Money
 }
@@ -0,0 +1,13 @@
+package models
+
+// AppliedPromotion is a promotion that discounts a cart
+type AppliedPromotion struct {
+	// ID identifies the promotion
+	ID string
+
+	// Description tells the customer what the promotion is
+	Description string
+
+	// Discount is the amount the promotion takes off the cart total
+	Discount Money
+}
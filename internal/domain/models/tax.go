@@ -0,0 +1,27 @@
+package models
+
+import (
+	"math/big"
+	"strings"
+)
+
+// TaxLine is a tax charged on the items of a cart in one tax category
+type TaxLine struct {
+	// Category is the tax category of the items, e.g. "standard"
+	Category string
+
+	// Rate is the fraction of the taxable amount charged, e.g. 1/5 for 20%
+	Rate *big.Rat
+
+	// Taxable is the price of the items after discounts, less the tax
+	Taxable Money
+
+	// Amount is the tax included in the price of the items
+	Amount Money
+}
+
+// RatePercent formats the rate in percent with up to two decimals, e.g. "20" or "8.25"
+func (t TaxLine) RatePercent() string {
+	percent := new(big.Rat).Mul(t.Rate, big.NewRat(100, 1)).FloatString(2)
+	return strings.TrimSuffix(strings.TrimRight(percent, "0"), ".")
+}
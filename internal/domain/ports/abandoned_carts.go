@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"route256/cart/internal/domain/models"
+)
+
+// AbandonedCartNotifier delivers abandoned cart events, e.g. to marketing
+type AbandonedCartNotifier interface {
+	// NotifyAbandonedCart sends event; an error means it may not have been delivered
+	NotifyAbandonedCart(ctx context.Context, event models.AbandonedCart) error
+}
+
+// AbandonedCartLog remembers the abandoned cart notifications already sent,
+// so that users are not notified twice about the same idle cart
+type AbandonedCartLog interface {
+	// LastNotice returns the last notice sent to the user, or false if there is none
+	LastNotice(ctx context.Context, userID int64) (models.AbandonedCartNotice, bool, error)
+
+	// Record stores notice as the last one sent to its user
+	Record(ctx context.Context, notice models.AbandonedCartNotice) error
+
+	// Retain forgets the notices sent to all users but those in userIDs
+	Retain(ctx context.Context, userIDs map[int64]struct{}) error
+}
@@ -1,15 +1,43 @@
 package ports
 
-import "route256/cart/internal/domain/models"
+import (
+	"context"
+	"time"
 
-// CartRepository defines the interface for cart storage operations
+	"route256/cart/internal/domain/models"
+)
+
+// CartRepository defines the interface for cart storage operations.
+// Implementations never share stored carts with callers: carts passed in
+// and returned are copies, so mutations must go through SaveCart or UpdateCart.
+// Every stored change but a refresh sets the UpdatedAt of the cart.
 type CartRepository interface {
 	// GetCart retrieves a cart by user ID
-	GetCart(userID int64) (*models.Cart, error)
+	GetCart(ctx context.Context, userID int64) (*models.Cart, error)
+
+	// SaveCart saves or updates a cart.
+	// It fails with models.VersionConflictError unless cart.Version equals the
+	// stored version (zero for a cart that does not exist) and bumps cart.Version on success.
+	SaveCart(ctx context.Context, cart *models.Cart) error
+
+	// CreateCart creates a new empty cart and sets its initial version
+	CreateCart(ctx context.Context, cart *models.Cart) error
+
+	// UpdateCart atomically applies update to the user's cart and bumps its version.
+	// If the user has no cart, update receives a new empty one.
+	// Changes are stored only if update returns nil; its error is returned as is.
+	UpdateCart(ctx context.Context, userID int64, update func(cart *models.Cart) error) error
+
+	// RefreshCart is UpdateCart for changes the service makes on its own, such
+	// as repricing: it bumps the version but keeps UpdatedAt, which tracks what
+	// the customer did. It fails with models.ErrCartNotFound if there is no cart.
+	RefreshCart(ctx context.Context, userID int64, update func(cart *models.Cart) error) error
 
-	// SaveCart saves or updates a cart
-	SaveCart(cart *models.Cart) error
+	// DeleteExpired deletes at most limit carts last changed before cutoff
+	// and returns how many it deleted
+	DeleteExpired(ctx context.Context, cutoff time.Time, limit int) (int, error)
 
-	// CreateCart creates a new empty cart
-	CreateCart(cart *models.Cart) error
+	// ListIdle returns at most limit carts last changed before cutoff whose
+	// user IDs are greater than after, ordered by user ID
+	ListIdle(ctx context.Context, cutoff time.Time, after int64, limit int) ([]*models.Cart, error)
 }
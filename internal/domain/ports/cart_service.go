@@ -5,20 +5,58 @@ import (
 	"route256/cart/internal/domain/models"
 )
 
-// CartService defines the interface for cart operations
+// CartService defines the interface for cart operations.
+// Mutating methods take the cart version the caller expects; they fail with
+// models.VersionConflictError if the cart has changed since. Pass
+// models.AnyVersion to skip the check.
 type CartService interface {
 	// AddItem adds an item to the cart
-	AddItem(userID int64, sku uint32, count uint16) error
+	AddItem(ctx context.Context, userID int64, sku uint32, count uint16, expectedVersion uint64) error
+
+	// AddItems adds a batch of items to the cart in a single update. Entries are
+	// checked like AddItem; those that fail are reported in their result and
+	// skipped while the others are added.
+	AddItems(ctx context.Context, userID int64, items []models.BatchItem, expectedVersion uint64) ([]models.BatchItemResult, error)
 
 	// RemoveItem removes an item from the cart
-	RemoveItem(userID int64, sku uint32) error
+	RemoveItem(ctx context.Context, userID int64, sku uint32, expectedVersion uint64) error
+
+	// SetItemQuantity sets the quantity of an item, adding it if the cart has none;
+	// zero removes it. Increases are validated like AddItem.
+	SetItemQuantity(ctx context.Context, userID int64, sku uint32, quantity uint16, expectedVersion uint64) error
+
+	// ChangeItemQuantity adds a signed delta to the quantity of an item; the item
+	// is removed if its quantity drops to zero or below. Increases are validated
+	// like AddItem.
+	ChangeItemQuantity(ctx context.Context, userID int64, sku uint32, delta int32, expectedVersion uint64) error
+
+	// GetCart retrieves the cart contents with prices in currency, or in the
+	// cart's own currency if it is empty, the discounts of promotions and taxes
+	GetCart(ctx context.Context, userID int64, currency string) (*models.Cart, error)
+
+	// ClearCart removes all items and the coupon from the cart
+	ClearCart(ctx context.Context, userID int64, expectedVersion uint64) error
+
+	// ApplyCoupon applies a coupon to the cart, replacing the previous one.
+	// It fails with models.ErrCouponNotFound if no promotion has the code.
+	ApplyCoupon(ctx context.Context, userID int64, code string, expectedVersion uint64) error
+
+	// RemoveCoupon removes the coupon from the cart
+	RemoveCoupon(ctx context.Context, userID int64, expectedVersion uint64) error
 
-	// GetCart retrieves the cart contents
-	GetCart(userID int64) (*models.Cart, error)
+	// SetRegion sets the region the cart is delivered to, which decides its taxes.
+	// It fails with ErrRegionNotFound if the region has no tax rates.
+	SetRegion(ctx context.Context, userID int64, region string, expectedVersion uint64) error
 
-	// ClearCart removes all items from the cart
-	ClearCart(userID int64) error
+	// MergeGuestCart folds a guest cart into the user's cart, merging the
+	// quantities of products in both with policy, and empties the guest cart.
+	// Products are checked like AddItems and reported the same way.
+	MergeGuestCart(ctx context.Context, guestID, userID int64, policy models.MergePolicy, expectedVersion uint64) ([]models.BatchItemResult, error)
 
-	// Checkout creates an order from the cart and clears it
-	Checkout(ctx context.Context, userID int64) (int64, error)
+	// Checkout creates an order from the cart at current prices less
+	// discounts and removes the ordered items from it; items added meanwhile
+	// stay in the cart.
+	// If prices changed since the caller last saw the cart, it fails unless
+	// confirmPriceChanges is set.
+	Checkout(ctx context.Context, userID int64, expectedVersion uint64, confirmPriceChanges bool) (int64, error)
 }
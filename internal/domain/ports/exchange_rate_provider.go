@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"math/big"
+)
+
+// ErrExchangeRateNotFound is returned when no rate is known for a currency pair
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// ExchangeRateProvider provides currency exchange rates
+type ExchangeRateProvider interface {
+	// Rate returns the value of one major unit of from in major units of to
+	Rate(ctx context.Context, from, to string) (*big.Rat, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import "errors"
+
+var ErrInvalidGuestToken = errors.New("invalid guest token")
+
+// GuestTokens issues the opaque tokens guest carts are known by outside of
+// the service and resolves them to the IDs the carts are stored under
+type GuestTokens interface {
+	// Issue returns a new token and the ID of its guest cart
+	Issue() (token string, cartID int64, err error)
+
+	// Resolve returns the ID of the guest cart of token.
+	// It fails with ErrInvalidGuestToken if the service did not issue token.
+	Resolve(token string) (int64, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import "errors"
+
+// ErrIdempotencyKeyInProgress is returned when a request with the same key is still being processed
+var ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
+
+// IdempotencyStore remembers the outcome of checkout requests by idempotency key
+type IdempotencyStore interface {
+	// Begin reserves key for a new request.
+	// If the key already completed, it returns the stored order ID and replay=true.
+	// If the key is reserved by a request still in flight, it returns ErrIdempotencyKeyInProgress.
+	Begin(key string) (orderID int64, replay bool, err error)
+
+	// Complete stores the order ID produced for a reserved key
+	Complete(key string, orderID int64)
+
+	// Abort releases a reserved key so that the request can be retried
+	Abort(key string)
+}
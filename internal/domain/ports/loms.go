@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+
+	"route256/cart/internal/domain/models"
 )
 
 // LOMSClient defines the interface for interacting with the LOMS service
@@ -14,12 +16,18 @@ type LOMSClient interface {
 
 	// GetOrderInfo retrieves information about an order
 	GetOrderInfo(ctx context.Context, orderID int64) (*OrderInfo, error)
+
+	// CancelOrder cancels an order and releases its reserved stocks
+	CancelOrder(ctx context.Context, orderID int64) error
 }
 
 // Item represents a cart item for order creation
 type Item struct {
 	SKU   uint32
 	Count uint16
+
+	// Price is what the customer pays for all Count items, discounts included
+	Price models.Money
 }
 
 // OrderInfo represents information about an order
@@ -1,9 +1,13 @@
 package ports
 
-import "route256/cart/internal/domain/models"
+import (
+	"context"
+
+	"route256/cart/internal/domain/models"
+)
 
 // ProductService defines the interface for product operations
 type ProductService interface {
 	// GetProduct retrieves product information by SKU
-	GetProduct(sku uint32) (*models.Product, error)
+	GetProduct(ctx context.Context, sku uint32) (*models.Product, error)
 }
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"errors"
+
+	"route256/cart/internal/domain/models"
+)
+
+// ErrRegionNotFound is returned for regions without tax rates
+var ErrRegionNotFound = errors.New("region not found")
+
+// TaxCalculator calculates the taxes on carts
+type TaxCalculator interface {
+	// Taxes returns the taxes included in the prices of items delivered to
+	// region after discounts, one line per tax category. An empty region means
+	// the default one; without a default, there are no taxes.
+	Taxes(ctx context.Context, region string, items []models.Item) ([]models.TaxLine, error)
+
+	// HasRegion reports whether region has tax rates
+	HasRegion(ctx context.Context, region string) bool
+}
@@ -2,6 +2,18 @@ package dto
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+
+	"route256/cart/internal/domain/models"
+)
+
+const (
+	// maxCouponLength bounds the size of coupon codes
+	maxCouponLength = 64
+
+	// maxRegionLength bounds the size of region codes
+	maxRegionLength = 16
 )
 
 // AddItemRequest represents a request to add an item to the cart
@@ -9,17 +21,130 @@ type AddItemRequest struct {
 	Count uint16 `json:"count" validate:"required,min=1"`
 }
 
+// AddItemsRequest represents a request to add a batch of items to the cart
+type AddItemsRequest struct {
+	Items []BatchItem `json:"items"`
+}
+
+// BatchItem represents one entry of a batch add
+type BatchItem struct {
+	SKU   uint32 `json:"sku"`
+	Count uint16 `json:"count"`
+}
+
+// BatchItemsResponse reports the result of each entry of a batch add, or of
+// each item of a merged guest cart, in order
+type BatchItemsResponse struct {
+	Items []BatchItemResult `json:"items"`
+}
+
+// BatchItemResult represents the result of one entry of a batch add or merge.
+// Reason and Error are set if the entry was not added: Reason is one of
+// "product_not_found", "insufficient_stock" and "limit_exceeded", Error
+// describes the failure.
+type BatchItemResult struct {
+	SKU    uint32 `json:"sku"`
+	Count  uint16 `json:"count"`
+	Added  bool   `json:"added"`
+	Reason string `json:"reason,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// MergeGuestCartRequest represents a request to merge a guest cart into the user's cart
+type MergeGuestCartRequest struct {
+	GuestToken string `json:"guest_token"`
+
+	// Policy is one of "sum", "max" and "guest"; empty means the configured one
+	Policy string `json:"policy"`
+}
+
+// CreateGuestCartResponse represents a response with the token of a new guest cart
+type CreateGuestCartResponse struct {
+	Token string `json:"token"`
+}
+
+// SetQuantityRequest represents a request to set the quantity of an item in the cart
+type SetQuantityRequest struct {
+	// Count is a pointer so that an explicit zero, which removes the item,
+	// can be told from a missing count
+	Count *uint16 `json:"count"`
+}
+
+// ChangeQuantityRequest represents a request to change the quantity of an item in the cart
+type ChangeQuantityRequest struct {
+	// Delta is added to the quantity; negative values decrement it
+	Delta int32 `json:"delta"`
+}
+
+// ApplyCouponRequest represents a request to apply a coupon to the cart
+type ApplyCouponRequest struct {
+	Code string `json:"code"`
+}
+
+// SetRegionRequest represents a request to set the region the cart is delivered to
+type SetRegionRequest struct {
+	Region string `json:"region"`
+}
+
+// Money represents an amount in minor currency units
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
 // CartItem represents an item in the cart
 type CartItem struct {
 	SKU      uint32 `json:"sku"`
+	Name     string `json:"name"`
 	Quantity uint16 `json:"quantity"`
-	Price    uint32 `json:"price"`
+	Price    Money  `json:"price"`
+
+	// PriceChanged is set if the price changed since the item was added;
+	// PreviousPrice is then the price the customer saw before
+	PriceChanged  bool   `json:"price_changed"`
+	PreviousPrice *Money `json:"previous_price,omitempty"`
+
+	// Discount is taken off the price of all items by promotions
+	Discount *Money `json:"discount,omitempty"`
+
+	// Unavailable is set if the product was removed from the catalog; the
+	// item must be removed before checkout
+	Unavailable bool `json:"unavailable,omitempty"`
+}
+
+// AppliedPromotion represents a promotion that discounts the cart
+type AppliedPromotion struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Discount    Money  `json:"discount"`
 }
 
 // GetCartResponse represents a response with cart contents
 type GetCartResponse struct {
 	Items      []CartItem `json:"items"`
-	TotalPrice uint32     `json:"total_price"`
+	TotalPrice Money      `json:"total_price"`
+
+	// Discount is the sum of the promotion discounts, DiscountedTotal what the customer pays
+	Coupon          string             `json:"coupon,omitempty"`
+	Promotions      []AppliedPromotion `json:"promotions"`
+	Discount        Money              `json:"discount"`
+	DiscountedTotal Money              `json:"discounted_total"`
+
+	// Prices include taxes: Subtotal is the discounted total without them,
+	// GrandTotal what the customer pays including them
+	Region     string    `json:"region,omitempty"`
+	Subtotal   Money     `json:"subtotal"`
+	Taxes      []TaxLine `json:"taxes"`
+	GrandTotal Money     `json:"grand_total"`
+}
+
+// TaxLine represents a tax charged on the items of one tax category
+type TaxLine struct {
+	Category string `json:"category"`
+	// Rate is in percent, e.g. "20"
+	Rate    string `json:"rate"`
+	Taxable Money  `json:"taxable"`
+	Amount  Money  `json:"amount"`
 }
 
 // CheckoutResponse represents a response with order ID
@@ -27,6 +152,12 @@ type CheckoutResponse struct {
 	OrderID int64 `json:"order_id"`
 }
 
+// HealthResponse reports service health and the state of its dependencies
+type HealthResponse struct {
+	Status       string            `json:"status"`
+	Dependencies map[string]string `json:"dependencies"`
+}
+
 // Validate validates the request
 func (r *AddItemRequest) Validate() error {
 	if r.Count == 0 {
@@ -34,3 +165,69 @@ func (r *AddItemRequest) Validate() error {
 	}
 	return nil
 }
+
+// Validate validates the request
+func (r *AddItemsRequest) Validate() error {
+	if len(r.Items) == 0 {
+		return errors.New("items are required")
+	}
+	if len(r.Items) > models.MaxBatchItems {
+		return fmt.Errorf("at most %d items per request", models.MaxBatchItems)
+	}
+	for i, item := range r.Items {
+		if item.SKU == 0 {
+			return fmt.Errorf("item %d: invalid sku", i)
+		}
+		if item.Count == 0 {
+			return fmt.Errorf("item %d: count must be greater than 0", i)
+		}
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *MergeGuestCartRequest) Validate() error {
+	if r.GuestToken == "" {
+		return errors.New("guest_token is required")
+	}
+	if r.Policy != "" {
+		if _, err := models.ParseMergePolicy(r.Policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *SetQuantityRequest) Validate() error {
+	if r.Count == nil {
+		return errors.New("count is required")
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *ChangeQuantityRequest) Validate() error {
+	if r.Delta == 0 {
+		return errors.New("delta must not be 0")
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *ApplyCouponRequest) Validate() error {
+	code := strings.TrimSpace(r.Code)
+	if code == "" || len(code) > maxCouponLength {
+		return errors.New("invalid coupon code")
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *SetRegionRequest) Validate() error {
+	region := strings.TrimSpace(r.Region)
+	if region == "" || len(region) > maxRegionLength {
+		return errors.New("invalid region")
+	}
+	return nil
+}
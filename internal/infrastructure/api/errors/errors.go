@@ -21,20 +21,40 @@ var (
 		Message: "invalid user_id",
 	}
 
+	ErrInvalidGuestToken = &APIError{
+		Code:    http.StatusBadRequest,
+		Message: "invalid guest token",
+	}
+
 	ErrInvalidSKU = &APIError{
 		Code:    http.StatusBadRequest,
 		Message: "invalid sku_id",
 	}
 
+	ErrInvalidCurrency = &APIError{
+		Code:    http.StatusBadRequest,
+		Message: "invalid currency",
+	}
+
 	ErrCartNotFound = &APIError{
 		Code:    http.StatusNotFound,
 		Message: "cart not found",
 	}
 
+	ErrCouponNotFound = &APIError{
+		Code:    http.StatusNotFound,
+		Message: "coupon not found",
+	}
+
 	ErrItemNotFound = &APIError{
 		Code:    http.StatusNotFound,
 		Message: "item not found",
 	}
+
+	ErrPreconditionFailed = &APIError{
+		Code:    http.StatusPreconditionFailed,
+		Message: "cart has been modified",
+	}
 )
 
 // IsAPIError checks if an error is an API error
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"route256/cart/internal/domain/models"
+	apiErrors "route256/cart/internal/infrastructure/api/errors"
+)
+
+// formatETag renders a cart version as a strong entity tag
+func formatETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// parseIfMatch extracts the expected cart version from the If-Match header.
+// A missing header or "*" impose no precondition. Anything that can never
+// match one of our entity tags (weak or foreign tags, lists) fails the precondition.
+func parseIfMatch(r *http.Request) (uint64, error) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return models.AnyVersion, nil
+	}
+
+	if len(value) < 2 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, apiErrors.ErrPreconditionFailed
+	}
+
+	version, err := strconv.ParseUint(value[1:len(value)-1], 10, 64)
+	if err != nil || version == models.AnyVersion {
+		return 0, apiErrors.ErrPreconditionFailed
+	}
+
+	return version, nil
+}
@@ -15,29 +15,63 @@ import (
 	"route256/cart/internal/usecase/cart"
 )
 
+// maxIdempotencyKeyLength bounds the size of client supplied idempotency keys
+const maxIdempotencyKeyLength = 255
+
 // Handler handles HTTP requests for the cart service
 type Handler struct {
-	service ports.CartService
+	service     ports.CartService
+	idempotency ports.IdempotencyStore
+
+	guests      ports.GuestTokens
+	mergePolicy models.MergePolicy
 }
 
 // NewHandler creates a new cart service handler
-func NewHandler(service ports.CartService) *Handler {
+func NewHandler(service ports.CartService, idempotency ports.IdempotencyStore) *Handler {
 	return &Handler{
-		service: service,
+		service:     service,
+		idempotency: idempotency,
 	}
 }
 
-// AddItem handles adding an item to the cart
-func (h *Handler) AddItem(w http.ResponseWriter, r *http.Request) {
+// WithGuests enables guest carts known by the tokens guests issues, which are
+// merged into user carts with policy unless a merge request names another one
+func (h *Handler) WithGuests(guests ports.GuestTokens, policy models.MergePolicy) *Handler {
+	h.guests = guests
+	h.mergePolicy = policy
+	return h
+}
+
+// cartOwner returns the ID the cart of a request is stored under: the
+// user_id path value, or the guest cart ID of the token path value on guest routes
+func (h *Handler) cartOwner(r *http.Request) (int64, *apiErrors.APIError) {
+	if token := r.PathValue("token"); token != "" {
+		guestID, err := h.guests.Resolve(token)
+		if err != nil {
+			return 0, apiErrors.ErrInvalidGuestToken
+		}
+		return guestID, nil
+	}
+
 	userID, err := strconv.ParseInt(r.PathValue("user_id"), 10, 64)
 	if err != nil {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
-		return
+		return 0, apiErrors.ErrInvalidUserID
 	}
 
 	// Validate user_id
 	if userID <= 0 {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
+		return 0, apiErrors.ErrInvalidUserID
+	}
+
+	return userID, nil
+}
+
+// AddItem handles adding an item to the cart
+func (h *Handler) AddItem(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
 		return
 	}
 
@@ -65,37 +99,216 @@ func (h *Handler) AddItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.AddItem(userID, uint32(skuID), req.Count); err != nil {
-		if errors.Is(err, models.ErrProductNotFound) {
-			http.Error(w, err.Error(), http.StatusPreconditionFailed)
-			return
-		}
-		if err.Error() == "not enough items in stock" {
-			http.Error(w, err.Error(), http.StatusPreconditionFailed)
-			return
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	if err := h.service.AddItem(r.Context(), userID, uint32(skuID), req.Count, expectedVersion); err != nil {
+		writeItemError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// AddItems handles adding a batch of items to the cart. Entries that cannot
+// be added do not fail the request: they are reported in the response.
+func (h *Handler) AddItems(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+
+	var req dto.AddItemsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request body
+	if err := req.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	items := make([]models.BatchItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = models.BatchItem{SKU: item.SKU, Count: item.Count}
+	}
+
+	results, err := h.service.AddItems(r.Context(), userID, items, expectedVersion)
+	if err != nil {
+		writeItemError(w, err)
+		return
+	}
+
+	writeBatchResults(w, results)
+}
+
+// writeBatchResults writes the report of a batch add or a merge
+func writeBatchResults(w http.ResponseWriter, results []models.BatchItemResult) {
+	resp := dto.BatchItemsResponse{
+		Items: make([]dto.BatchItemResult, len(results)),
+	}
+	for i, result := range results {
+		resp.Items[i] = dto.BatchItemResult{
+			SKU:   result.SKU,
+			Count: result.Count,
+			Added: result.Err == nil,
 		}
-		if apiErr, ok := apiErrors.IsAPIError(err); ok {
-			http.Error(w, apiErr.Error(), apiErr.Code)
-			return
+		if result.Err != nil {
+			resp.Items[i].Reason = cart.FailureReason(result.Err)
+			resp.Items[i].Error = result.Err.Error()
 		}
-		http.Error(w, fmt.Sprintf("internal server error: %v", err), http.StatusInternalServerError)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// SetItemQuantity handles setting the quantity of an item in the cart
+func (h *Handler) SetItemQuantity(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+
+	skuID, err := strconv.ParseUint(r.PathValue("sku_id"), 10, 32)
+	if err != nil {
+		http.Error(w, apiErrors.ErrInvalidSKU.Error(), apiErrors.ErrInvalidSKU.Code)
+		return
+	}
+
+	// Validate sku_id
+	if skuID == 0 {
+		http.Error(w, apiErrors.ErrInvalidSKU.Error(), apiErrors.ErrInvalidSKU.Code)
+		return
+	}
+
+	var req dto.SetQuantityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request body
+	if err := req.Validate(); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	if err := h.service.SetItemQuantity(r.Context(), userID, uint32(skuID), *req.Count, expectedVersion); err != nil {
+		writeItemError(w, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
 }
 
-// RemoveItem handles removing an item from the cart
-func (h *Handler) RemoveItem(w http.ResponseWriter, r *http.Request) {
-	userID, err := strconv.ParseInt(r.PathValue("user_id"), 10, 64)
+// ChangeItemQuantity handles incrementing or decrementing the quantity of an item in the cart
+func (h *Handler) ChangeItemQuantity(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+
+	skuID, err := strconv.ParseUint(r.PathValue("sku_id"), 10, 32)
 	if err != nil {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
+		http.Error(w, apiErrors.ErrInvalidSKU.Error(), apiErrors.ErrInvalidSKU.Code)
 		return
 	}
 
-	// Validate user_id
-	if userID <= 0 {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
+	// Validate sku_id
+	if skuID == 0 {
+		http.Error(w, apiErrors.ErrInvalidSKU.Error(), apiErrors.ErrInvalidSKU.Code)
+		return
+	}
+
+	var req dto.ChangeQuantityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request body
+	if err := req.Validate(); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	if err := h.service.ChangeItemQuantity(r.Context(), userID, uint32(skuID), req.Delta, expectedVersion); err != nil {
+		writeItemError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeItemError writes the response for a failed change to the quantity of an item
+func writeItemError(w http.ResponseWriter, err error) {
+	if errors.Is(err, models.ErrVersionConflict) {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+	if errors.Is(err, models.ErrProductNotFound) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	if errors.Is(err, cart.ErrInsufficientStock) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	if errors.Is(err, models.ErrLimitExceeded) {
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		return
+	}
+	if errors.Is(err, models.ErrMoneyOverflow) {
+		http.Error(w, "cart total is too large", http.StatusPreconditionFailed)
+		return
+	}
+	if writeUnavailable(w, err) {
+		return
+	}
+	if apiErr, ok := apiErrors.IsAPIError(err); ok {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+	log.Printf("Failed to change cart item: %v", err)
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
+// RemoveItem handles removing an item from the cart
+func (h *Handler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
 		return
 	}
 
@@ -111,7 +324,17 @@ func (h *Handler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.RemoveItem(userID, uint32(skuID)); err != nil {
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	if err := h.service.RemoveItem(r.Context(), userID, uint32(skuID), expectedVersion); err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+			return
+		}
 		if apiErr, ok := apiErrors.IsAPIError(err); ok {
 			http.Error(w, apiErr.Error(), apiErr.Code)
 			return
@@ -125,19 +348,23 @@ func (h *Handler) RemoveItem(w http.ResponseWriter, r *http.Request) {
 
 // ClearCart handles clearing the cart
 func (h *Handler) ClearCart(w http.ResponseWriter, r *http.Request) {
-	userID, err := strconv.ParseInt(r.PathValue("user_id"), 10, 64)
-	if err != nil {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
 		return
 	}
 
-	// Validate user_id
-	if userID <= 0 {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
 		return
 	}
 
-	if err := h.service.ClearCart(userID); err != nil {
+	if err := h.service.ClearCart(r.Context(), userID, expectedVersion); err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+			return
+		}
 		if apiErr, ok := apiErrors.IsAPIError(err); ok {
 			http.Error(w, apiErr.Error(), apiErr.Code)
 			return
@@ -151,47 +378,97 @@ func (h *Handler) ClearCart(w http.ResponseWriter, r *http.Request) {
 
 // GetCart handles getting the cart contents
 func (h *Handler) GetCart(w http.ResponseWriter, r *http.Request) {
-	userID, err := strconv.ParseInt(r.PathValue("user_id"), 10, 64)
-	if err != nil {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
 		return
 	}
 
-	// Validate user_id
-	if userID <= 0 {
-		http.Error(w, apiErrors.ErrInvalidUserID.Error(), apiErrors.ErrInvalidUserID.Code)
+	currency := r.URL.Query().Get("currency")
+	if currency != "" && !models.IsCurrencyCode(currency) {
+		http.Error(w, apiErrors.ErrInvalidCurrency.Error(), apiErrors.ErrInvalidCurrency.Code)
 		return
 	}
 
-	cart, err := h.service.GetCart(userID)
+	cart, err := h.service.GetCart(r.Context(), userID, currency)
 	if err != nil {
 		if errors.Is(err, models.ErrCartNotFound) {
 			http.Error(w, "cart not found", http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, ports.ErrExchangeRateNotFound) {
+			http.Error(w, "unsupported currency", http.StatusBadRequest)
+			return
+		}
+		if writeUnavailable(w, err) {
+			return
+		}
 		if apiErr, ok := apiErrors.IsAPIError(err); ok {
 			http.Error(w, apiErr.Error(), apiErr.Code)
 			return
 		}
+		log.Printf("GetCart error for user %d: %v", userID, err)
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
+	if cart.UnknownRegion != "" {
+		log.Printf("Cart of user %d has region %s without tax rates, taxed at the default ones", userID, cart.UnknownRegion)
+	}
 
 	items := make([]dto.CartItem, len(cart.Items))
 	for i, item := range cart.Items {
 		items[i] = dto.CartItem{
-			SKU:      item.SKU,
-			Quantity: item.Quantity,
-			Price:    item.Price,
+			SKU:          item.SKU,
+			Name:         item.Name,
+			Quantity:     item.Quantity,
+			Price:        toMoneyDTO(item.Price),
+			PriceChanged: item.PriceChanged(),
+			Unavailable:  item.Unavailable,
+		}
+		if item.PriceChanged() {
+			previousPrice := toMoneyDTO(item.PreviousPrice)
+			items[i].PreviousPrice = &previousPrice
+		}
+		if !item.Discount.IsZero() {
+			discount := toMoneyDTO(item.Discount)
+			items[i].Discount = &discount
+		}
+	}
+
+	promotions := make([]dto.AppliedPromotion, len(cart.Promotions))
+	for i, promotion := range cart.Promotions {
+		promotions[i] = dto.AppliedPromotion{
+			ID:          promotion.ID,
+			Description: promotion.Description,
+			Discount:    toMoneyDTO(promotion.Discount),
+		}
+	}
+
+	taxes := make([]dto.TaxLine, len(cart.Taxes))
+	for i, tax := range cart.Taxes {
+		taxes[i] = dto.TaxLine{
+			Category: tax.Category,
+			Rate:     tax.RatePercent(),
+			Taxable:  toMoneyDTO(tax.Taxable),
+			Amount:   toMoneyDTO(tax.Amount),
 		}
 	}
 
 	resp := dto.GetCartResponse{
-		Items:      items,
-		TotalPrice: cart.TotalPrice,
+		Items:           items,
+		TotalPrice:      toMoneyDTO(cart.TotalPrice),
+		Coupon:          cart.Coupon,
+		Promotions:      promotions,
+		Discount:        toMoneyDTO(cart.Discount),
+		DiscountedTotal: toMoneyDTO(cart.DiscountedTotal),
+		Region:          cart.Region,
+		Subtotal:        toMoneyDTO(cart.Subtotal),
+		Taxes:           taxes,
+		GrandTotal:      toMoneyDTO(cart.GrandTotal),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", formatETag(cart.Version))
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
@@ -212,25 +489,308 @@ func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orderID, err := h.service.Checkout(r.Context(), userID)
+	expectedVersion, err := parseIfMatch(r)
 	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	// Prices that changed since the client last saw the cart must be confirmed
+	var confirmPriceChanges bool
+	if value := r.URL.Query().Get("confirm_price_changes"); value != "" {
+		confirmPriceChanges, err = strconv.ParseBool(value)
+		if err != nil {
+			http.Error(w, "invalid confirm_price_changes parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		http.Error(w, "invalid Idempotency-Key header", http.StatusBadRequest)
+		return
+	}
+
+	var scopedKey string
+	if idempotencyKey != "" && h.idempotency != nil {
+		// Keys are scoped by user so that clients cannot replay each other's orders
+		scopedKey = fmt.Sprintf("%d:%s", userID, idempotencyKey)
+
+		orderID, replay, err := h.idempotency.Begin(scopedKey)
+		if err != nil {
+			if errors.Is(err, ports.ErrIdempotencyKeyInProgress) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		if replay {
+			w.Header().Set("Idempotent-Replayed", "true")
+			writeCheckoutResponse(w, orderID)
+			return
+		}
+	}
+
+	orderID, ok := h.checkout(w, r, userID, expectedVersion, confirmPriceChanges)
+	if scopedKey != "" {
+		if !ok {
+			// Failed checkouts are not remembered so that the client can retry them
+			h.idempotency.Abort(scopedKey)
+		} else {
+			h.idempotency.Complete(scopedKey, orderID)
+		}
+	}
+	if !ok {
+		return
+	}
+
+	writeCheckoutResponse(w, orderID)
+}
+
+// checkout runs the checkout and writes an error response on failure
+func (h *Handler) checkout(w http.ResponseWriter, r *http.Request, userID int64, expectedVersion uint64, confirmPriceChanges bool) (int64, bool) {
+	orderID, err := h.service.Checkout(r.Context(), userID, expectedVersion, confirmPriceChanges)
+	if err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+			return 0, false
+		}
 		if errors.Is(err, models.ErrCartNotFound) {
 			http.Error(w, "cart not found", http.StatusNotFound)
-			return
+			return 0, false
 		}
 		if errors.Is(err, cart.ErrCartEmpty) {
 			http.Error(w, "cart is empty", http.StatusBadRequest)
+			return 0, false
+		}
+		if errors.Is(err, cart.ErrPriceChanged) {
+			http.Error(w, "prices have changed, review the cart and confirm", http.StatusConflict)
+			return 0, false
+		}
+		if errors.Is(err, models.ErrProductGone) {
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+			return 0, false
+		}
+		if writeUnavailable(w, err) {
+			return 0, false
+		}
+		if apiErr, ok := apiErrors.IsAPIError(err); ok {
+			http.Error(w, apiErr.Error(), apiErr.Code)
+			return 0, false
+		}
+		log.Printf("Checkout error for user %d: %v", userID, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return 0, false
+	}
+
+	return orderID, true
+}
+
+// ApplyCoupon handles applying a coupon to the cart
+func (h *Handler) ApplyCoupon(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+
+	var req dto.ApplyCouponRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	if err := h.service.ApplyCoupon(r.Context(), userID, req.Code, expectedVersion); err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+			return
+		}
+		if errors.Is(err, models.ErrCouponNotFound) {
+			http.Error(w, apiErrors.ErrCouponNotFound.Error(), apiErrors.ErrCouponNotFound.Code)
+			return
+		}
+		if errors.Is(err, models.ErrCartNotFound) {
+			http.Error(w, apiErrors.ErrCartNotFound.Error(), apiErrors.ErrCartNotFound.Code)
 			return
 		}
 		if apiErr, ok := apiErrors.IsAPIError(err); ok {
 			http.Error(w, apiErr.Error(), apiErr.Code)
 			return
 		}
-		log.Printf("Checkout error for user %d: %v", userID, err)
-		http.Error(w, fmt.Sprintf("internal server error: %v", err), http.StatusInternalServerError)
+		log.Printf("ApplyCoupon error for user %d: %v", userID, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// RemoveCoupon handles removing the coupon from the cart
+func (h *Handler) RemoveCoupon(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
 		return
 	}
 
+	if err := h.service.RemoveCoupon(r.Context(), userID, expectedVersion); err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+			return
+		}
+		if apiErr, ok := apiErrors.IsAPIError(err); ok {
+			http.Error(w, apiErr.Error(), apiErr.Code)
+			return
+		}
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// SetRegion handles setting the region the cart is delivered to
+func (h *Handler) SetRegion(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+
+	var req dto.SetRegionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	if err := h.service.SetRegion(r.Context(), userID, req.Region, expectedVersion); err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+			return
+		}
+		if errors.Is(err, ports.ErrRegionNotFound) {
+			http.Error(w, "unsupported region", http.StatusBadRequest)
+			return
+		}
+		if apiErr, ok := apiErrors.IsAPIError(err); ok {
+			http.Error(w, apiErr.Error(), apiErr.Code)
+			return
+		}
+		log.Printf("SetRegion error for user %d: %v", userID, err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// toMoneyDTO converts a domain amount of money to its API representation
+func toMoneyDTO(m models.Money) dto.Money {
+	return dto.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+// CreateGuestCart handles issuing a token for a new guest cart
+func (h *Handler) CreateGuestCart(w http.ResponseWriter, r *http.Request) {
+	// The cart itself is created by the first change to it
+	token, _, err := h.guests.Issue()
+	if err != nil {
+		log.Printf("CreateGuestCart error: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	resp := dto.CreateGuestCartResponse{
+		Token: token,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// MergeGuestCart handles merging a guest cart into the user's cart on login
+func (h *Handler) MergeGuestCart(w http.ResponseWriter, r *http.Request) {
+	userID, apiErr := h.cartOwner(r)
+	if apiErr != nil {
+		http.Error(w, apiErr.Error(), apiErr.Code)
+		return
+	}
+
+	var req dto.MergeGuestCartRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Validate request body
+	if err := req.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	guestID, err := h.guests.Resolve(req.GuestToken)
+	if err != nil {
+		http.Error(w, apiErrors.ErrInvalidGuestToken.Error(), apiErrors.ErrInvalidGuestToken.Code)
+		return
+	}
+
+	policy := h.mergePolicy
+	if req.Policy != "" {
+		policy = models.MergePolicy(req.Policy)
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		http.Error(w, apiErrors.ErrPreconditionFailed.Error(), apiErrors.ErrPreconditionFailed.Code)
+		return
+	}
+
+	results, err := h.service.MergeGuestCart(r.Context(), guestID, userID, policy, expectedVersion)
+	if err != nil {
+		writeItemError(w, err)
+		return
+	}
+
+	writeBatchResults(w, results)
+}
+
+// writeCheckoutResponse writes a successful checkout response
+func writeCheckoutResponse(w http.ResponseWriter, orderID int64) {
 	resp := dto.CheckoutResponse{
 		OrderID: orderID,
 	}
@@ -0,0 +1,556 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+	"route256/cart/internal/infrastructure/api/dto"
+	"route256/cart/internal/infrastructure/guest"
+	"route256/cart/internal/infrastructure/idempotency"
+	"route256/cart/internal/usecase/cart"
+)
+
+// checkoutService is a ports.CartService that only supports Checkout
+type checkoutService struct {
+	ports.CartService
+
+	calls   atomic.Int64
+	release chan struct{}
+}
+
+func (s *checkoutService) Checkout(_ context.Context, _ int64, _ uint64, _ bool) (int64, error) {
+	orderID := s.calls.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+	return orderID, nil
+}
+
+func TestHandler_CheckoutIdempotencyKey(t *testing.T) {
+	service := &checkoutService{release: make(chan struct{})}
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(service, idempotency.NewStore(time.Hour)))
+
+	checkout := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/user/1/checkout", nil)
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := make(chan *httptest.ResponseRecorder)
+	go func() { first <- checkout("key") }()
+
+	// Wait for the first request to reach the service
+	assert.Eventually(t, func() bool { return service.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	duplicate := checkout("key")
+	assert.Equal(t, http.StatusConflict, duplicate.Code)
+
+	close(service.release)
+	original := <-first
+	assert.Equal(t, http.StatusOK, original.Code)
+	assert.JSONEq(t, `{"order_id":1}`, original.Body.String())
+
+	replay := checkout("key")
+	assert.Equal(t, http.StatusOK, replay.Code)
+	assert.JSONEq(t, `{"order_id":1}`, replay.Body.String())
+	assert.Equal(t, "true", replay.Header().Get("Idempotent-Replayed"))
+
+	other := checkout("other-key")
+	assert.Equal(t, http.StatusOK, other.Code)
+	assert.JSONEq(t, `{"order_id":2}`, other.Body.String())
+	assert.Equal(t, int64(2), service.calls.Load())
+}
+
+// unavailableService is a ports.CartService whose dependencies are down
+type unavailableService struct {
+	ports.CartService
+}
+
+func (s *unavailableService) AddItem(_ context.Context, _ int64, _ uint32, _ uint16, _ uint64) error {
+	return &models.DependencyUnavailableError{Dependency: "product", RetryAfter: 2500 * time.Millisecond}
+}
+
+func TestHandler_AddItemDependencyUnavailable(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(&unavailableService{}, idempotency.NewStore(time.Hour)))
+
+	req := httptest.NewRequest(http.MethodPost, "/user/1/cart/123", strings.NewReader(`{"count":1}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "3", rec.Header().Get("Retry-After"))
+}
+
+// limitedService is a ports.CartService whose carts are full
+type limitedService struct {
+	ports.CartService
+}
+
+func (s *limitedService) AddItem(_ context.Context, _ int64, sku uint32, _ uint16, _ uint64) error {
+	return &models.LimitExceededError{Limit: models.LimitSKUQuantity, Max: 2, SKU: sku}
+}
+
+func TestHandler_AddItemLimitExceeded(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(&limitedService{}, idempotency.NewStore(time.Hour)))
+
+	req := httptest.NewRequest(http.MethodPost, "/user/1/cart/123", strings.NewReader(`{"count":3}`))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, "cart limit exceeded: at most 2 items of sku 123 per cart\n", rec.Body.String())
+}
+
+// batchService is a ports.CartService that knows every product but 404 and
+// has a stock of 10 for each
+type batchService struct {
+	ports.CartService
+}
+
+func (s *batchService) AddItems(_ context.Context, _ int64, items []models.BatchItem, _ uint64) ([]models.BatchItemResult, error) {
+	results := make([]models.BatchItemResult, len(items))
+	for i, item := range items {
+		results[i] = models.BatchItemResult{SKU: item.SKU, Count: item.Count}
+		switch {
+		case item.SKU == 404:
+			results[i].Err = models.ErrProductNotFound
+		case item.Count > 10:
+			results[i].Err = cart.ErrInsufficientStock
+		}
+	}
+	return results, nil
+}
+
+func TestHandler_AddItems(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(&batchService{}, idempotency.NewStore(time.Hour)))
+
+	body := `{"items":[{"sku":1,"count":2},{"sku":404,"count":1},{"sku":2,"count":11}]}`
+	req := httptest.NewRequest(http.MethodPost, "/user/1/cart/items", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"items":[
+		{"sku":1,"count":2,"added":true},
+		{"sku":404,"count":1,"added":false,"reason":"product_not_found","error":"product not found"},
+		{"sku":2,"count":11,"added":false,"reason":"insufficient_stock","error":"not enough items in stock"}
+	]}`, rec.Body.String())
+
+	for _, body := range []string{
+		`{"items":[]}`,
+		`{"items":[{"sku":0,"count":1}]}`,
+		`{"items":[{"sku":1,"count":0}]}`,
+		`{"items":[{"sku":1,"count":65536}]}`,
+		`{`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/user/1/cart/items", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+// guestService is a ports.CartService recording the carts it is asked for
+// and the merges it makes
+type guestService struct {
+	ports.CartService
+
+	carts  []int64
+	merged []models.MergePolicy
+}
+
+func (s *guestService) GetCart(_ context.Context, userID int64, _ string) (*models.Cart, error) {
+	s.carts = append(s.carts, userID)
+	return models.NewCart(userID), nil
+}
+
+func (s *guestService) MergeGuestCart(_ context.Context, guestID, userID int64, policy models.MergePolicy, _ uint64) ([]models.BatchItemResult, error) {
+	s.carts = append(s.carts, guestID, userID)
+	s.merged = append(s.merged, policy)
+	return []models.BatchItemResult{{SKU: 1, Count: 2}}, nil
+}
+
+func TestHandler_GuestCart(t *testing.T) {
+	tokens, err := guest.NewTokens([]byte("secret"))
+	require.NoError(t, err)
+
+	service := &guestService{}
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(service, idempotency.NewStore(time.Hour)).WithGuests(tokens, models.MergeMax))
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := serve(http.MethodPost, "/guest/cart", "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	var created dto.CreateGuestCartResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	guestID, err := tokens.Resolve(created.Token)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/guest/"+created.Token+"/cart", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/guest/forged/cart", "").Code)
+	assert.Equal(t, []int64{guestID}, service.carts)
+
+	// Guests check out as users only
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/guest/"+created.Token+"/checkout", "").Code)
+
+	rec = serve(http.MethodPost, "/user/7/cart/merge", `{"guest_token":"`+created.Token+`"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"items":[{"sku":1,"count":2,"added":true}]}`, rec.Body.String())
+	rec = serve(http.MethodPost, "/user/7/cart/merge", `{"guest_token":"`+created.Token+`","policy":"guest"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []int64{guestID, guestID, 7, guestID, 7}, service.carts)
+	assert.Equal(t, []models.MergePolicy{models.MergeMax, models.MergePreferGuest}, service.merged)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/user/7/cart/merge", `{"guest_token":"forged"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPost, "/user/7/cart/merge", `{"guest_token":"`+created.Token+`","policy":"min"}`).Code)
+	assert.Len(t, service.merged, 2)
+}
+
+func TestHandler_GuestCartDisabled(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(&guestService{}, idempotency.NewStore(time.Hour)))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/guest/cart", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// quantityService is a ports.CartService recording quantity changes, with a
+// stock of 10 for every product
+type quantityService struct {
+	ports.CartService
+
+	quantities []uint16
+	deltas     []int32
+}
+
+func (s *quantityService) SetItemQuantity(_ context.Context, _ int64, _ uint32, quantity uint16, _ uint64) error {
+	if quantity > 10 {
+		return cart.ErrInsufficientStock
+	}
+	s.quantities = append(s.quantities, quantity)
+	return nil
+}
+
+func (s *quantityService) ChangeItemQuantity(_ context.Context, _ int64, _ uint32, delta int32, _ uint64) error {
+	if delta > 10 {
+		return cart.ErrInsufficientStock
+	}
+	s.deltas = append(s.deltas, delta)
+	return nil
+}
+
+func TestHandler_ItemQuantity(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		wantCode     int
+		wantQuantity []uint16
+		wantDelta    []int32
+	}{
+		{name: "set", method: http.MethodPut, body: `{"count":3}`, wantCode: http.StatusOK, wantQuantity: []uint16{3}},
+		{name: "set zero", method: http.MethodPut, body: `{"count":0}`, wantCode: http.StatusOK, wantQuantity: []uint16{0}},
+		{name: "set without count", method: http.MethodPut, body: `{}`, wantCode: http.StatusBadRequest},
+		{name: "set over stock", method: http.MethodPut, body: `{"count":11}`, wantCode: http.StatusPreconditionFailed},
+		{name: "set out of range", method: http.MethodPut, body: `{"count":65536}`, wantCode: http.StatusBadRequest},
+		{name: "decrement", method: http.MethodPatch, body: `{"delta":-2}`, wantCode: http.StatusOK, wantDelta: []int32{-2}},
+		{name: "increment", method: http.MethodPatch, body: `{"delta":2}`, wantCode: http.StatusOK, wantDelta: []int32{2}},
+		{name: "zero delta", method: http.MethodPatch, body: `{"delta":0}`, wantCode: http.StatusBadRequest},
+		{name: "increment over stock", method: http.MethodPatch, body: `{"delta":11}`, wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &quantityService{}
+			mux := http.NewServeMux()
+			RegisterRoutes(mux, NewHandler(service, idempotency.NewStore(time.Hour)))
+
+			req := httptest.NewRequest(tt.method, "/user/1/cart/123", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantQuantity, service.quantities)
+			assert.Equal(t, tt.wantDelta, service.deltas)
+		})
+	}
+}
+
+// couponService is a ports.CartService that only knows the coupon SAVE
+type couponService struct {
+	ports.CartService
+}
+
+func (s *couponService) ApplyCoupon(_ context.Context, _ int64, code string, _ uint64) error {
+	if code != "SAVE" {
+		return models.ErrCouponNotFound
+	}
+	return nil
+}
+
+func TestHandler_ApplyCoupon(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(&couponService{}, idempotency.NewStore(time.Hour)))
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{name: "applied", body: `{"code":"SAVE"}`, wantCode: http.StatusOK},
+		{name: "unknown coupon", body: `{"code":"FREE"}`, wantCode: http.StatusNotFound},
+		{name: "empty code", body: `{"code":"  "}`, wantCode: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/user/1/cart/coupon", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
+
+// versionedService is a ports.CartService whose cart is at version 3
+type versionedService struct {
+	ports.CartService
+
+	removed int
+}
+
+func (s *versionedService) GetCart(_ context.Context, userID int64, _ string) (*models.Cart, error) {
+	return &models.Cart{
+		UserID:  userID,
+		Version: 3,
+		Items:   models.ItemList{{SKU: 123, Quantity: 1, Price: models.NewMoney(100, models.DefaultCurrency)}},
+	}, nil
+}
+
+func (s *versionedService) RemoveItem(_ context.Context, userID int64, _ uint32, expectedVersion uint64) error {
+	cart := &models.Cart{UserID: userID, Version: 3}
+	if err := cart.CheckVersion(expectedVersion); err != nil {
+		return err
+	}
+	s.removed++
+	return nil
+}
+
+func TestHandler_ETag(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(&versionedService{}, idempotency.NewStore(time.Hour)))
+
+	req := httptest.NewRequest(http.MethodGet, "/user/1/cart", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"3"`, rec.Header().Get("ETag"))
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantCode    int
+		wantRemoved int
+	}{
+		{name: "no precondition", wantCode: http.StatusOK, wantRemoved: 1},
+		{name: "any version", ifMatch: "*", wantCode: http.StatusOK, wantRemoved: 1},
+		{name: "current version", ifMatch: `"3"`, wantCode: http.StatusOK, wantRemoved: 1},
+		{name: "stale version", ifMatch: `"2"`, wantCode: http.StatusPreconditionFailed},
+		{name: "weak tag", ifMatch: `W/"3"`, wantCode: http.StatusPreconditionFailed},
+		{name: "unquoted tag", ifMatch: `3`, wantCode: http.StatusPreconditionFailed},
+		{name: "list of tags", ifMatch: `"2", "3"`, wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &versionedService{}
+			mux := http.NewServeMux()
+			RegisterRoutes(mux, NewHandler(service, idempotency.NewStore(time.Hour)))
+
+			req := httptest.NewRequest(http.MethodDelete, "/user/1/cart/123", nil)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantRemoved, service.removed)
+		})
+	}
+}
+
+// currencyService is a ports.CartService with exchange rates to USD only
+type currencyService struct {
+	ports.CartService
+
+	currencies []string
+}
+
+func (s *currencyService) GetCart(_ context.Context, userID int64, currency string) (*models.Cart, error) {
+	s.currencies = append(s.currencies, currency)
+	if currency != "" && currency != "USD" {
+		return nil, fmt.Errorf("%w: RUB to %s", ports.ErrExchangeRateNotFound, currency)
+	}
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
+	price := models.NewMoney(100, currency)
+	return &models.Cart{
+		UserID:     userID,
+		Items:      models.ItemList{{SKU: 123, Quantity: 1, Price: price}},
+		TotalPrice: price,
+	}, nil
+}
+
+func TestHandler_GetCartCurrency(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		wantCode       int
+		wantCurrencies []string
+		wantCurrency   string
+	}{
+		{name: "cart currency", wantCode: http.StatusOK, wantCurrencies: []string{""}, wantCurrency: models.DefaultCurrency},
+		{name: "converted", query: "?currency=USD", wantCode: http.StatusOK, wantCurrencies: []string{"USD"}, wantCurrency: "USD"},
+		{name: "unknown currency", query: "?currency=XYZ", wantCode: http.StatusBadRequest, wantCurrencies: []string{"XYZ"}},
+		{name: "lowercase code", query: "?currency=usd", wantCode: http.StatusBadRequest},
+		{name: "invalid code", query: "?currency=DOLLAR", wantCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &currencyService{}
+			mux := http.NewServeMux()
+			RegisterRoutes(mux, NewHandler(service, idempotency.NewStore(time.Hour)))
+
+			req := httptest.NewRequest(http.MethodGet, "/user/1/cart"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantCurrencies, service.currencies)
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var resp dto.GetCartResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.wantCurrency, resp.TotalPrice.Currency)
+			assert.Equal(t, tt.wantCurrency, resp.Items[0].Price.Currency)
+		})
+	}
+}
+
+// stubBreaker is a CircuitBreaker in a fixed state
+type stubBreaker struct {
+	name, state string
+}
+
+func (b stubBreaker) Name() string  { return b.name }
+func (b stubBreaker) State() string { return b.state }
+
+func TestHealthHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		breakers []CircuitBreaker
+		want     dto.HealthResponse
+	}{
+		{
+			name:     "all closed",
+			breakers: []CircuitBreaker{stubBreaker{"product", BreakerClosed}, stubBreaker{"loms", BreakerClosed}},
+			want:     dto.HealthResponse{Status: "ok", Dependencies: map[string]string{"product": "closed", "loms": "closed"}},
+		},
+		{
+			name:     "one open",
+			breakers: []CircuitBreaker{stubBreaker{"product", BreakerClosed}, stubBreaker{"loms", "open"}},
+			want:     dto.HealthResponse{Status: "degraded", Dependencies: map[string]string{"product": "closed", "loms": "open"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			RegisterHealthRoute(mux, NewHealthHandler(tt.breakers...))
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			var resp dto.HealthResponse
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+// brokenService is a ports.CartService failing with internal errors
+type brokenService struct {
+	ports.CartService
+}
+
+var errBroken = errors.New("dial tcp 10.0.0.1:50051: connection refused")
+
+func (s *brokenService) AddItem(_ context.Context, _ int64, _ uint32, _ uint16, _ uint64) error {
+	return errBroken
+}
+
+func (s *brokenService) Checkout(_ context.Context, _ int64, _ uint64, _ bool) (int64, error) {
+	return 0, errBroken
+}
+
+func TestHandler_InternalErrorsAreNotExposed(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterRoutes(mux, NewHandler(&brokenService{}, idempotency.NewStore(time.Hour)))
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{name: "add item", method: http.MethodPost, target: "/user/1/cart/123", body: `{"count":1}`},
+		{name: "checkout", method: http.MethodPost, target: "/user/1/checkout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.Equal(t, "internal server error\n", rec.Body.String())
+		})
+	}
+}
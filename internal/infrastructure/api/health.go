@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/infrastructure/api/dto"
+)
+
+// BreakerClosed is the state of a circuit breaker letting all calls through
+const BreakerClosed = "closed"
+
+// CircuitBreaker exposes the state of a breaker guarding a dependency:
+// BreakerClosed, or any other state reported as is
+type CircuitBreaker interface {
+	Name() string
+	State() string
+}
+
+// HealthHandler reports service health
+type HealthHandler struct {
+	breakers []CircuitBreaker
+}
+
+// NewHealthHandler creates a health handler reporting the given breakers
+func NewHealthHandler(breakers ...CircuitBreaker) *HealthHandler {
+	return &HealthHandler{
+		breakers: breakers,
+	}
+}
+
+// ServeHTTP handles health requests. The service is "degraded" while any
+// dependency breaker is not closed; it still answers 200 since carts can be read.
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	resp := dto.HealthResponse{
+		Status:       "ok",
+		Dependencies: make(map[string]string, len(h.breakers)),
+	}
+
+	for _, b := range h.breakers {
+		state := b.State()
+		if state != BreakerClosed {
+			resp.Status = "degraded"
+		}
+		resp.Dependencies[b.Name()] = state
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeUnavailable writes a 503 with Retry-After if err reports an unavailable dependency
+func writeUnavailable(w http.ResponseWriter, err error) bool {
+	var unavailable *models.DependencyUnavailableError
+	if !errors.As(err, &unavailable) {
+		return false
+	}
+
+	retryAfter := int(math.Ceil(unavailable.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	http.Error(w, unavailable.Error(), http.StatusServiceUnavailable)
+	return true
+}
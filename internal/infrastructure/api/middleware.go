@@ -4,6 +4,12 @@ import (
 	"log"
 	"net/http"
 	"time"
+
+	"route256/cart/internal/infrastructure/metrics"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // LoggingMiddleware logs information about each request
@@ -31,6 +37,41 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// MetricsMiddleware records request counts and latencies per route pattern.
+// It must wrap the ServeMux directly so that the matched pattern is known.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		metrics.HTTPRequestStarted()
+
+		rw := &responseWriter{
+			ResponseWriter: w,
+			statusCode:     http.StatusOK,
+		}
+
+		defer func() {
+			metrics.HTTPRequestFinished(r.Method, r.Pattern, rw.statusCode, time.Since(start))
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// TracingMiddleware starts a server span for each request, continuing the
+// trace from an incoming W3C traceparent header. Once the ServeMux has matched
+// the request, the span is renamed after the route pattern.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+
+		if r.Pattern != "" {
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Pattern)
+			span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+		}
+	}), "http.server")
+}
+
 // responseWriter is a custom response writer that captures the status code
 type responseWriter struct {
 	http.ResponseWriter
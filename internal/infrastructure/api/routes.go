@@ -6,8 +6,47 @@ import "net/http"
 func RegisterRoutes(mux *http.ServeMux, handler *Handler) {
 	// Cart operations
 	mux.HandleFunc("POST /user/{user_id}/cart/{sku_id}", handler.AddItem)
+	mux.HandleFunc("POST /user/{user_id}/cart/items", handler.AddItems)
 	mux.HandleFunc("DELETE /user/{user_id}/cart/{sku_id}", handler.RemoveItem)
+	mux.HandleFunc("PUT /user/{user_id}/cart/{sku_id}", handler.SetItemQuantity)
+	mux.HandleFunc("PATCH /user/{user_id}/cart/{sku_id}", handler.ChangeItemQuantity)
 	mux.HandleFunc("DELETE /user/{user_id}/cart", handler.ClearCart)
 	mux.HandleFunc("GET /user/{user_id}/cart", handler.GetCart)
 	mux.HandleFunc("POST /user/{user_id}/checkout", handler.Checkout)
+
+	// Coupons
+	mux.HandleFunc("PUT /user/{user_id}/cart/coupon", handler.ApplyCoupon)
+	mux.HandleFunc("DELETE /user/{user_id}/cart/coupon", handler.RemoveCoupon)
+
+	// Delivery region, which decides taxes
+	mux.HandleFunc("PUT /user/{user_id}/cart/region", handler.SetRegion)
+
+	// Guest carts, if enabled
+	if handler.guests != nil {
+		registerGuestRoutes(mux, handler)
+	}
+}
+
+// registerGuestRoutes registers the cart operations of guests, which know
+// their cart by a token instead of a user_id. Guests cannot check out: they
+// merge their cart into a user's on login first.
+func registerGuestRoutes(mux *http.ServeMux, handler *Handler) {
+	mux.HandleFunc("POST /guest/cart", handler.CreateGuestCart)
+	mux.HandleFunc("POST /user/{user_id}/cart/merge", handler.MergeGuestCart)
+
+	mux.HandleFunc("POST /guest/{token}/cart/{sku_id}", handler.AddItem)
+	mux.HandleFunc("POST /guest/{token}/cart/items", handler.AddItems)
+	mux.HandleFunc("DELETE /guest/{token}/cart/{sku_id}", handler.RemoveItem)
+	mux.HandleFunc("PUT /guest/{token}/cart/{sku_id}", handler.SetItemQuantity)
+	mux.HandleFunc("PATCH /guest/{token}/cart/{sku_id}", handler.ChangeItemQuantity)
+	mux.HandleFunc("DELETE /guest/{token}/cart", handler.ClearCart)
+	mux.HandleFunc("GET /guest/{token}/cart", handler.GetCart)
+	mux.HandleFunc("PUT /guest/{token}/cart/coupon", handler.ApplyCoupon)
+	mux.HandleFunc("DELETE /guest/{token}/cart/coupon", handler.RemoveCoupon)
+	mux.HandleFunc("PUT /guest/{token}/cart/region", handler.SetRegion)
+}
+
+// RegisterHealthRoute registers the health endpoint
+func RegisterHealthRoute(mux *http.ServeMux, handler *HealthHandler) {
+	mux.Handle("GET /health", handler)
 }
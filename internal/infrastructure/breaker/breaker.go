@@ -0,0 +1,197 @@
+package breaker
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"route256/cart/internal/domain/models"
+)
+
+// State is the state of a circuit breaker
+type State int
+
+const (
+	// StateClosed lets all calls through while counting consecutive failures
+	StateClosed State = iota
+	// StateOpen rejects all calls until the open timeout elapses
+	StateOpen
+	// StateHalfOpen lets a limited number of probe calls through
+	StateHalfOpen
+)
+
+// String returns the state name
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// Settings configures a circuit breaker
+type Settings struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker
+	FailureThreshold int
+
+	// OpenTimeout is how long the breaker stays open before probing the dependency
+	OpenTimeout time.Duration
+
+	// HalfOpenMaxCalls is the number of successful probes needed to close the breaker
+	HalfOpenMaxCalls int
+}
+
+// Breaker is a circuit breaker guarding calls to a single dependency
+type Breaker struct {
+	name      string
+	settings  Settings
+	isFailure func(err error) bool
+
+	mu                sync.Mutex
+	state             State
+	failures          int
+	openedAt          time.Time
+	halfOpenInFlight  int
+	halfOpenSuccesses int
+
+	now func() time.Time
+}
+
+// New creates a closed circuit breaker for the named dependency.
+// isFailure decides which errors count against the dependency; errors such as
+// "not found" or a cancelled caller context say nothing about its health.
+func New(name string, settings Settings, isFailure func(err error) bool) *Breaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = 5
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = 10 * time.Second
+	}
+	if settings.HalfOpenMaxCalls <= 0 {
+		settings.HalfOpenMaxCalls = 1
+	}
+
+	return &Breaker{
+		name:      name,
+		settings:  settings,
+		isFailure: isFailure,
+		now:       time.Now,
+	}
+}
+
+// Name returns the name of the guarded dependency
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh()
+	return b.state
+}
+
+// Execute runs fn unless the breaker rejects the call with a
+// models.DependencyUnavailableError, and records the outcome
+func (b *Breaker) Execute(fn func() error) error {
+	if err := b.allow(); err != nil {
+		return err
+	}
+
+	err := fn()
+	b.record(err != nil && b.isFailure(err))
+	return err
+}
+
+// allow admits or rejects a call
+func (b *Breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refresh()
+
+	switch b.state {
+	case StateOpen:
+		return b.unavailable(b.openedAt.Add(b.settings.OpenTimeout).Sub(b.now()))
+	case StateHalfOpen:
+		if b.halfOpenInFlight+b.halfOpenSuccesses >= b.settings.HalfOpenMaxCalls {
+			return b.unavailable(time.Second)
+		}
+		b.halfOpenInFlight++
+	}
+
+	return nil
+}
+
+// record updates the breaker with the outcome of an admitted call
+func (b *Breaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+
+		b.failures++
+		if b.failures >= b.settings.FailureThreshold {
+			b.setState(StateOpen)
+		}
+	case StateHalfOpen:
+		b.halfOpenInFlight--
+		if failed {
+			b.setState(StateOpen)
+			return
+		}
+
+		b.halfOpenSuccesses++
+		if b.halfOpenSuccesses >= b.settings.HalfOpenMaxCalls {
+			b.setState(StateClosed)
+		}
+	case StateOpen:
+		// A call admitted before the breaker opened; its outcome no longer matters
+	}
+}
+
+// refresh moves an open breaker to half-open once the open timeout has elapsed.
+// The caller must hold b.mu.
+func (b *Breaker) refresh() {
+	if b.state == StateOpen && !b.now().Before(b.openedAt.Add(b.settings.OpenTimeout)) {
+		b.setState(StateHalfOpen)
+	}
+}
+
+// setState switches the breaker to state and resets its counters.
+// The caller must hold b.mu.
+func (b *Breaker) setState(state State) {
+	log.Printf("Circuit breaker %s: %s -> %s", b.name, b.state, state)
+
+	b.state = state
+	b.failures = 0
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccesses = 0
+	if state == StateOpen {
+		b.openedAt = b.now()
+	}
+}
+
+// unavailable builds the error returned for rejected calls
+func (b *Breaker) unavailable(retryAfter time.Duration) error {
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+
+	return &models.DependencyUnavailableError{
+		Dependency: b.name,
+		RetryAfter: retryAfter,
+	}
+}
@@ -0,0 +1,153 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+var errBoom = errors.New("boom")
+
+// newTestBreaker creates a breaker driven by a fake clock
+func newTestBreaker(settings Settings) (*Breaker, *time.Time) {
+	now := time.Unix(0, 0)
+	b := New("test", settings, IsProductFailure)
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func fail() error    { return errBoom }
+func succeed() error { return nil }
+
+func TestBreaker_Transitions(t *testing.T) {
+	b, now := newTestBreaker(Settings{FailureThreshold: 2, OpenTimeout: 10 * time.Second, HalfOpenMaxCalls: 1})
+
+	// A success resets the consecutive failure count
+	assert.ErrorIs(t, b.Execute(fail), errBoom)
+	assert.NoError(t, b.Execute(succeed))
+	assert.ErrorIs(t, b.Execute(fail), errBoom)
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.ErrorIs(t, b.Execute(fail), errBoom)
+	assert.Equal(t, StateOpen, b.State())
+
+	// Open breakers reject calls without running them
+	*now = now.Add(3 * time.Second)
+	called := false
+	err := b.Execute(func() error { called = true; return nil })
+	assert.False(t, called)
+
+	var unavailable *models.DependencyUnavailableError
+	require.ErrorAs(t, err, &unavailable)
+	assert.ErrorIs(t, err, models.ErrDependencyUnavailable)
+	assert.Equal(t, "test", unavailable.Dependency)
+	assert.Equal(t, 7*time.Second, unavailable.RetryAfter)
+
+	// A failed probe reopens the breaker
+	*now = now.Add(7 * time.Second)
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.ErrorIs(t, b.Execute(fail), errBoom)
+	assert.Equal(t, StateOpen, b.State())
+
+	// A successful probe closes it
+	*now = now.Add(10 * time.Second)
+	assert.NoError(t, b.Execute(succeed))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_HalfOpenLimitsProbes(t *testing.T) {
+	b, now := newTestBreaker(Settings{FailureThreshold: 1, OpenTimeout: time.Second, HalfOpenMaxCalls: 1})
+
+	assert.ErrorIs(t, b.Execute(fail), errBoom)
+	*now = now.Add(time.Second)
+
+	probing := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- b.Execute(func() error {
+			close(probing)
+			<-release
+			return nil
+		})
+	}()
+	<-probing
+
+	// Only one probe may be in flight
+	assert.ErrorIs(t, b.Execute(succeed), models.ErrDependencyUnavailable)
+
+	close(release)
+	assert.NoError(t, <-done)
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestBreaker_IgnoresNonFailures(t *testing.T) {
+	b, _ := newTestBreaker(Settings{FailureThreshold: 1})
+
+	assert.Error(t, b.Execute(func() error { return models.ErrProductNotFound }))
+	assert.Error(t, b.Execute(func() error { return context.Canceled }))
+	assert.Equal(t, StateClosed, b.State())
+}
+
+func TestIsLOMSFailure(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "unavailable", err: status.Error(codes.Unavailable, "down"), want: true},
+		{name: "deadline", err: status.Error(codes.DeadlineExceeded, "slow"), want: true},
+		{name: "internal", err: status.Error(codes.Internal, "bug"), want: true},
+		{name: "plain error", err: errBoom, want: true},
+		{name: "insufficient stocks", err: status.Error(codes.FailedPrecondition, "stocks"), want: false},
+		{name: "unknown order", err: status.Error(codes.NotFound, "order"), want: false},
+		{name: "cancelled", err: context.Canceled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsLOMSFailure(tt.err))
+		})
+	}
+}
+
+// failingLOMS fails every call with errBoom and records the cancelled orders
+type failingLOMS struct {
+	ports.LOMSClient
+	cancelled []int64
+}
+
+func (f *failingLOMS) GetStocksInfo(context.Context, uint32) (uint64, error) {
+	return 0, errBoom
+}
+
+func (f *failingLOMS) CancelOrder(_ context.Context, orderID int64) error {
+	f.cancelled = append(f.cancelled, orderID)
+	return nil
+}
+
+func TestLOMSClient_CancelOrderWhileOpen(t *testing.T) {
+	b, _ := newTestBreaker(Settings{FailureThreshold: 1})
+	loms := &failingLOMS{}
+	client := NewLOMSClient(loms, b)
+
+	_, err := client.GetStocksInfo(context.Background(), 1)
+	assert.ErrorIs(t, err, errBoom)
+	require.Equal(t, StateOpen, b.State())
+
+	_, err = client.GetStocksInfo(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrDependencyUnavailable)
+
+	// Compensations still reach LOMS
+	require.NoError(t, client.CancelOrder(context.Background(), 42))
+	assert.Equal(t, []int64{42}, loms.cancelled)
+}
@@ -0,0 +1,104 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// IsProductFailure reports whether a product service error says the service is unhealthy.
+// A missing product or a cancelled caller does not.
+func IsProductFailure(err error) bool {
+	return !errors.Is(err, models.ErrProductNotFound) && !errors.Is(err, context.Canceled)
+}
+
+// IsLOMSFailure reports whether a LOMS error says the service is unhealthy.
+// Business rejections such as insufficient stocks or an unknown order do not.
+func IsLOMSFailure(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// productService decorates ports.ProductService with a circuit breaker
+type productService struct {
+	next    ports.ProductService
+	breaker *Breaker
+}
+
+// NewProductService guards service with breaker
+func NewProductService(service ports.ProductService, breaker *Breaker) ports.ProductService {
+	return &productService{
+		next:    service,
+		breaker: breaker,
+	}
+}
+
+// GetProduct implements ports.ProductService
+func (s *productService) GetProduct(ctx context.Context, sku uint32) (product *models.Product, err error) {
+	err = s.breaker.Execute(func() error {
+		product, err = s.next.GetProduct(ctx, sku)
+		return err
+	})
+	return product, err
+}
+
+// lomsClient decorates ports.LOMSClient with a circuit breaker
+type lomsClient struct {
+	next    ports.LOMSClient
+	breaker *Breaker
+}
+
+// NewLOMSClient guards client with breaker
+func NewLOMSClient(client ports.LOMSClient, breaker *Breaker) ports.LOMSClient {
+	return &lomsClient{
+		next:    client,
+		breaker: breaker,
+	}
+}
+
+// CreateOrder implements ports.LOMSClient
+func (c *lomsClient) CreateOrder(ctx context.Context, userID int64, items []ports.Item) (orderID int64, err error) {
+	err = c.breaker.Execute(func() error {
+		orderID, err = c.next.CreateOrder(ctx, userID, items)
+		return err
+	})
+	return orderID, err
+}
+
+// GetStocksInfo implements ports.LOMSClient
+func (c *lomsClient) GetStocksInfo(ctx context.Context, sku uint32) (count uint64, err error) {
+	err = c.breaker.Execute(func() error {
+		count, err = c.next.GetStocksInfo(ctx, sku)
+		return err
+	})
+	return count, err
+}
+
+// GetOrderInfo implements ports.LOMSClient
+func (c *lomsClient) GetOrderInfo(ctx context.Context, orderID int64) (info *ports.OrderInfo, err error) {
+	err = c.breaker.Execute(func() error {
+		info, err = c.next.GetOrderInfo(ctx, orderID)
+		return err
+	})
+	return info, err
+}
+
+// CancelOrder implements ports.LOMSClient. It bypasses the breaker: orders
+// are cancelled to compensate failed checkouts, which is mostly while LOMS
+// is failing, and rejecting the call would leave them dangling.
+func (c *lomsClient) CancelOrder(ctx context.Context, orderID int64) error {
+	return c.next.CancelOrder(ctx, orderID)
+}
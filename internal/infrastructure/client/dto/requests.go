@@ -10,6 +10,8 @@ type GetProductRequest struct {
 type GetProductResponse struct {
 	Name  string `json:"name"`
 	Price uint32 `json:"price"`
+	// Currency is the ISO 4217 code of the price; prices without one are in models.DefaultCurrency
+	Currency string `json:"currency,omitempty"`
 }
 
 // ErrorResponse represents an error response from the product service
@@ -0,0 +1,211 @@
+package client
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// cacheEntry is a cached product lookup result
+type cacheEntry struct {
+	sku       uint32
+	product   *models.Product // nil for a cached "product not found"
+	expiresAt time.Time
+}
+
+// flight is an upstream lookup shared by concurrent misses for the same SKU
+type flight struct {
+	done    chan struct{}
+	product *models.Product
+	err     error
+
+	// waiters is the number of callers waiting for the lookup; the last one
+	// to give up cancels it. Guarded by ProductCache.flightsMu.
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// ProductCache implements ports.ProductService interface
+// by caching lookups of another ports.ProductService
+type ProductCache struct {
+	next ports.ProductService
+
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	mu      sync.Mutex
+	entries map[uint32]*list.Element
+	lru     *list.List // front is the most recently used entry
+
+	flightsMu sync.Mutex
+	flights   map[uint32]*flight
+
+	now func() time.Time
+}
+
+// NewProductCache creates a cache holding up to size products for ttl.
+// Unknown SKUs are remembered for negativeTTL; zero disables negative caching.
+func NewProductCache(next ports.ProductService, size int, ttl, negativeTTL time.Duration) *ProductCache {
+	return &ProductCache{
+		next:        next,
+		size:        size,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		entries:     make(map[uint32]*list.Element),
+		lru:         list.New(),
+		flights:     make(map[uint32]*flight),
+		now:         time.Now,
+	}
+}
+
+// GetProduct implements ports.ProductService.
+// Concurrent misses for the same SKU share a single upstream call; each caller
+// still stops waiting as soon as its own context is done, and the call is
+// cancelled once no caller waits for it anymore.
+func (c *ProductCache) GetProduct(ctx context.Context, sku uint32) (*models.Product, error) {
+	if product, ok := c.get(sku); ok {
+		if product == nil {
+			return nil, models.ErrProductNotFound
+		}
+		return product, nil
+	}
+
+	f := c.join(ctx, sku)
+	select {
+	case <-ctx.Done():
+		c.leave(sku, f)
+		return nil, ctx.Err()
+	case <-f.done:
+		c.leave(sku, f)
+		if f.err != nil {
+			return nil, f.err
+		}
+		return copyProduct(f.product), nil
+	}
+}
+
+// join returns the lookup of sku in flight, starting one if there is none.
+// A new lookup carries the values of ctx, such as the trace, but not its
+// cancellation: it belongs to all of its callers.
+func (c *ProductCache) join(ctx context.Context, sku uint32) *flight {
+	c.flightsMu.Lock()
+	defer c.flightsMu.Unlock()
+
+	f, exists := c.flights[sku]
+	if !exists {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		c.flights[sku] = f
+		go c.fly(flightCtx, sku, f)
+	}
+
+	f.waiters++
+	return f
+}
+
+// leave stops waiting for a lookup, cancelling it if nobody else waits
+func (c *ProductCache) leave(sku uint32, f *flight) {
+	c.flightsMu.Lock()
+	defer c.flightsMu.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	f.cancel()
+	if c.flights[sku] == f {
+		delete(c.flights, sku)
+	}
+}
+
+// fly looks sku up upstream and caches the result
+func (c *ProductCache) fly(ctx context.Context, sku uint32, f *flight) {
+	product, err := c.next.GetProduct(ctx, sku)
+	switch {
+	case err == nil:
+		c.put(sku, product, c.ttl)
+	case errors.Is(err, models.ErrProductNotFound) && c.negativeTTL > 0:
+		c.put(sku, nil, c.negativeTTL)
+	}
+
+	c.flightsMu.Lock()
+	if c.flights[sku] == f {
+		delete(c.flights, sku)
+	}
+	c.flightsMu.Unlock()
+
+	f.product, f.err = product, err
+	close(f.done)
+	f.cancel()
+}
+
+// get returns a fresh cached result for sku; a nil product means it is known not to exist
+func (c *ProductCache) get(sku uint32) (*models.Product, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, exists := c.entries[sku]
+	if !exists {
+		return nil, false
+	}
+
+	entry := elem.Value.(*cacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.lru.Remove(elem)
+		delete(c.entries, sku)
+		return nil, false
+	}
+
+	c.lru.MoveToFront(elem)
+	if entry.product == nil {
+		return nil, true
+	}
+	return copyProduct(entry.product), true
+}
+
+// put stores a lookup result, evicting the least recently used entry when full
+func (c *ProductCache) put(sku uint32, product *models.Product, ttl time.Duration) {
+	if c.size <= 0 || ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{
+		sku:       sku,
+		expiresAt: c.now().Add(ttl),
+	}
+	if product != nil {
+		entry.product = copyProduct(product)
+	}
+
+	if elem, exists := c.entries[sku]; exists {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[sku] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).sku)
+	}
+}
+
+// copyProduct keeps cached products isolated from callers
+func copyProduct(product *models.Product) *models.Product {
+	clone := *product
+	return &clone
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+)
+
+// rub returns an amount in the default currency
+func rub(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
+// countingProductService counts upstream lookups and knows only SKUs below 1000
+type countingProductService struct {
+	calls   atomic.Int64
+	release chan struct{}
+}
+
+func (s *countingProductService) GetProduct(ctx context.Context, sku uint32) (*models.Product, error) {
+	s.calls.Add(1)
+	if s.release != nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.release:
+		}
+	}
+	if sku >= 1000 {
+		return nil, models.ErrProductNotFound
+	}
+	return &models.Product{SKU: sku, Name: "product", Price: rub(int64(sku) * 10)}, nil
+}
+
+func TestProductCache_GetProduct(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		lookups   []uint32
+		advance   time.Duration // clock advance before the last lookup
+		wantCalls int64
+		wantErr   error
+	}{
+		{
+			name:      "hit",
+			size:      10,
+			lookups:   []uint32{1, 1, 1},
+			wantCalls: 1,
+		},
+		{
+			name:      "expired",
+			size:      10,
+			lookups:   []uint32{1, 1},
+			advance:   time.Minute,
+			wantCalls: 2,
+		},
+		{
+			name:      "least recently used is evicted",
+			size:      2,
+			lookups:   []uint32{1, 2, 1, 3, 2},
+			wantCalls: 4,
+		},
+		{
+			name:      "recently used survives eviction",
+			size:      2,
+			lookups:   []uint32{1, 2, 1, 3, 1},
+			wantCalls: 3,
+		},
+		{
+			name:      "not found is cached",
+			size:      10,
+			lookups:   []uint32{1000, 1000},
+			wantCalls: 1,
+			wantErr:   models.ErrProductNotFound,
+		},
+		{
+			name:      "not found expires sooner",
+			size:      10,
+			lookups:   []uint32{1000, 1000},
+			advance:   10 * time.Second,
+			wantCalls: 2,
+			wantErr:   models.ErrProductNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := &countingProductService{}
+			cache := NewProductCache(upstream, tt.size, time.Minute, 10*time.Second)
+			clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			cache.now = func() time.Time { return clock }
+
+			var err error
+			for i, sku := range tt.lookups {
+				if i == len(tt.lookups)-1 {
+					clock = clock.Add(tt.advance)
+				}
+				_, err = cache.GetProduct(context.Background(), sku)
+			}
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.wantCalls, upstream.calls.Load())
+		})
+	}
+}
+
+func TestProductCache_CollapsesConcurrentMisses(t *testing.T) {
+	const callers = 100
+
+	upstream := &countingProductService{release: make(chan struct{})}
+	cache := NewProductCache(upstream, 10, time.Minute, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			product, err := cache.GetProduct(context.Background(), 1)
+			assert.NoError(t, err)
+			assert.Equal(t, rub(10), product.Price)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return upstream.calls.Load() == 1 }, time.Second, time.Millisecond)
+	close(upstream.release)
+	wg.Wait()
+
+	assert.Equal(t, int64(1), upstream.calls.Load())
+}
+
+func TestProductCache_CallerCancellation(t *testing.T) {
+	upstream := &countingProductService{release: make(chan struct{})}
+	cache := NewProductCache(upstream, 10, time.Minute, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := cache.GetProduct(ctx, 1)
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool { return upstream.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// Another caller joins the lookup and keeps it going after the first gives up
+	other := make(chan error)
+	go func() {
+		_, err := cache.GetProduct(context.Background(), 1)
+		other <- err
+	}()
+	assert.Eventually(t, func() bool {
+		cache.flightsMu.Lock()
+		defer cache.flightsMu.Unlock()
+		return cache.flights[1] != nil && cache.flights[1].waiters == 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	close(upstream.release)
+	require.NoError(t, <-other)
+	assert.Equal(t, int64(1), upstream.calls.Load())
+}
+
+func TestProductCache_LastCallerCancellation(t *testing.T) {
+	upstream := &countingProductService{release: make(chan struct{})}
+	cache := NewProductCache(upstream, 10, time.Minute, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := cache.GetProduct(ctx, 1)
+		done <- err
+	}()
+
+	assert.Eventually(t, func() bool { return upstream.calls.Load() == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// Nobody waits for the lookup anymore, so it is cancelled and not reused
+	assert.Eventually(t, func() bool {
+		cache.flightsMu.Lock()
+		defer cache.flightsMu.Unlock()
+		return len(cache.flights) == 0
+	}, time.Second, time.Millisecond)
+
+	close(upstream.release)
+	product, err := cache.GetProduct(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), product.SKU)
+	assert.Equal(t, int64(2), upstream.calls.Load())
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -28,7 +29,7 @@ func NewProductClient(baseURL string, token string, httpClient *http.Client) por
 }
 
 // GetProduct implements ports.ProductService
-func (c *ProductClient) GetProduct(sku uint32) (*models.Product, error) {
+func (c *ProductClient) GetProduct(ctx context.Context, sku uint32) (*models.Product, error) {
 	reqBody := dto.GetProductRequest{
 		Token: c.token,
 		SKU:   sku,
@@ -39,12 +40,15 @@ func (c *ProductClient) GetProduct(sku uint32) (*models.Product, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/get_product", bytes.NewBuffer(jsonBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/get_product", bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	// get_product only reads, so it is safe to retry. A nil header marks the
+	// request as idempotent without sending the header.
+	req.Header["Idempotency-Key"] = nil
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -52,6 +56,10 @@ func (c *ProductClient) GetProduct(sku uint32) (*models.Product, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: sku %d", models.ErrProductNotFound, sku)
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		var errResp dto.ErrorResponse
 		if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
@@ -65,9 +73,14 @@ func (c *ProductClient) GetProduct(sku uint32) (*models.Product, error) {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	currency := productResp.Currency
+	if currency == "" {
+		currency = models.DefaultCurrency
+	}
+
 	return &models.Product{
 		SKU:   sku,
 		Name:  productResp.Name,
-		Price: productResp.Price,
+		Price: models.NewMoney(int64(productResp.Price), currency),
 	}, nil
 }
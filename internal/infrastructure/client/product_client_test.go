@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductClient_GetProduct(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"Кроссовки","price":2202}`))
+	}))
+	defer server.Close()
+
+	client := NewProductClient(server.URL, "token", server.Client())
+
+	product, err := client.GetProduct(context.Background(), 773297411)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(773297411), product.SKU)
+	assert.Equal(t, "Кроссовки", product.Name)
+	assert.Equal(t, rub(2202), product.Price)
+}
+
+func TestProductClient_GetProductCancelled(t *testing.T) {
+	var hits atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		// Hang until the client gives up
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewProductClient(server.URL, "token", server.Client())
+
+	t.Run("cancelled before the call", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := client.GetProduct(ctx, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Zero(t, hits.Load())
+	})
+
+	t.Run("cancelled during the call", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := client.GetProduct(ctx, 1)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitMiddleware wraps an http.RoundTripper with a token bucket limiter
+// so that requests stay under the quota of the remote service
+type RateLimitMiddleware struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+	onWait  func(req *http.Request, wait time.Duration)
+}
+
+// NewRateLimitMiddleware creates a middleware letting through rps requests per
+// second on average and up to burst requests at once
+func NewRateLimitMiddleware(next http.RoundTripper, rps float64, burst int) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		next:    next,
+		limiter: rate.NewLimiter(rate.Limit(rps), max(burst, 1)),
+	}
+}
+
+// OnWait registers a callback invoked with the time every request waited for a token
+func (m *RateLimitMiddleware) OnWait(fn func(req *http.Request, wait time.Duration)) *RateLimitMiddleware {
+	m.onWait = fn
+	return m
+}
+
+// RoundTrip implements http.RoundTripper.
+// Requests wait for a token rather than fail, unless the request context is
+// done first or its deadline would pass before the token is available.
+func (m *RateLimitMiddleware) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	reservation := m.limiter.Reserve()
+	delay := reservation.Delay()
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		reservation.Cancel()
+		closeBody(req)
+		return nil, context.DeadlineExceeded
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			reservation.Cancel()
+			closeBody(req)
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if m.onWait != nil {
+		m.onWait(req, delay)
+	}
+
+	return m.next.RoundTrip(req)
+}
+
+// closeBody closes the body of a request that will not be sent,
+// as the http.RoundTripper contract requires
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+}
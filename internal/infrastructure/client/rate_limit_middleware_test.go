@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware_RoundTrip(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	var waited atomic.Int64
+	client := &http.Client{Transport: NewRateLimitMiddleware(http.DefaultTransport, 20, 2).
+		OnWait(func(_ *http.Request, wait time.Duration) {
+			if wait > 0 {
+				waited.Add(1)
+			}
+		})}
+
+	// The burst goes through at once, the rest waits for tokens instead of failing
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Equal(t, int64(4), hits.Load())
+	assert.Equal(t, int64(2), waited.Load())
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimitMiddleware_Context(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewRateLimitMiddleware(http.DefaultTransport, 1, 1)}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	t.Run("deadline before the next token", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = client.Do(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		_, err = client.Do(req)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	assert.Equal(t, int64(1), hits.Load())
+}
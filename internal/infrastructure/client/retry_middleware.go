@@ -1,50 +1,225 @@
 package client
 
 import (
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
 	"net/http"
+	"strconv"
+	"syscall"
 	"time"
 )
 
+// RetryPolicy configures RetryMiddleware
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+
+	// BaseBackoff is the backoff before the first retry; it doubles with every retry
+	BaseBackoff time.Duration
+
+	// MaxBackoff caps a single backoff; zero means no cap. A response asking
+	// to wait longer with Retry-After is not retried.
+	MaxBackoff time.Duration
+
+	// MaxRetryTime caps the total time from the first attempt until the last retry
+	// is started; zero means no cap
+	MaxRetryTime time.Duration
+
+	// RetryServerErrors also retries 502, 503 and 504 responses and transient
+	// network errors, but only for idempotent requests
+	RetryServerErrors bool
+}
+
 // RetryMiddleware wraps an http.RoundTripper with retry logic
 type RetryMiddleware struct {
-	next       http.RoundTripper
-	maxRetries int
-	backoff    time.Duration
+	next    http.RoundTripper
+	policy  RetryPolicy
+	onRetry func(req *http.Request, statusCode int)
 }
 
 // NewRetryMiddleware creates a new retry middleware
-func NewRetryMiddleware(next http.RoundTripper, maxRetries int, backoff time.Duration) *RetryMiddleware {
+func NewRetryMiddleware(next http.RoundTripper, policy RetryPolicy) *RetryMiddleware {
 	return &RetryMiddleware{
-		next:       next,
-		maxRetries: maxRetries,
-		backoff:    backoff,
+		next:   next,
+		policy: policy,
 	}
 }
 
-// RoundTrip implements http.RoundTripper
+// OnRetry registers a callback invoked before every retry with the status code
+// that caused it, or 0 if it was caused by a network error
+func (m *RetryMiddleware) OnRetry(fn func(req *http.Request, statusCode int)) *RetryMiddleware {
+	m.onRetry = fn
+	return m
+}
+
+// RoundTrip implements http.RoundTripper.
+// Retries wait out a Retry-After header if the response has one and an
+// exponential backoff with full jitter otherwise, and stop as soon as the
+// request context is done or Retry-After exceeds MaxBackoff.
 func (m *RetryMiddleware) RoundTrip(req *http.Request) (*http.Response, error) {
-	var resp *http.Response
-	var err error
+	ctx := req.Context()
+	start := time.Now()
+	attemptReq := req
+
+	for attempt := 0; ; attempt++ {
+		resp, err := m.next.RoundTrip(attemptReq)
+		if !m.shouldRetry(req, resp, err) || attempt == m.policy.MaxRetries {
+			return resp, err
+		}
+
+		wait := m.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				// Retrying earlier than asked to would only be rejected again
+				if m.policy.MaxBackoff > 0 && retryAfter > m.policy.MaxBackoff {
+					return resp, err
+				}
+				wait = retryAfter
+			}
+		}
+
+		// Give up if the retry would start after the deadline
+		if m.policy.MaxRetryTime > 0 && time.Since(start)+wait > m.policy.MaxRetryTime {
+			return resp, err
+		}
 
-	for i := 0; i <= m.maxRetries; i++ {
-		resp, err = m.next.RoundTrip(req)
-		if err != nil {
-			return nil, err
+		// The body of the next attempt must be a fresh copy of the original one
+		nextReq, ok := rewind(req)
+		if !ok {
+			return resp, err
 		}
 
-		// Only retry on 420 or 429 status codes
-		if resp.StatusCode != 420 && resp.StatusCode != 429 {
-			return resp, nil
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+			drainAndClose(resp.Body)
 		}
 
-		// If this was the last retry, return the response
-		if i == m.maxRetries {
-			return resp, nil
+		if m.onRetry != nil {
+			m.onRetry(req, statusCode)
 		}
 
-		// Wait before retrying
-		time.Sleep(m.backoff)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		attemptReq = nextReq
+	}
+}
+
+// shouldRetry decides whether the outcome of an attempt is worth retrying
+func (m *RetryMiddleware) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return m.policy.RetryServerErrors && isIdempotent(req) && isTransient(err)
+	}
+
+	switch resp.StatusCode {
+	case 420, http.StatusTooManyRequests:
+		// The request was rejected before being processed
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return m.policy.RetryServerErrors && isIdempotent(req)
+	default:
+		return false
+	}
+}
+
+// backoff returns the full jitter backoff before retry number attempt+1
+func (m *RetryMiddleware) backoff(attempt int) time.Duration {
+	if m.policy.BaseBackoff <= 0 {
+		return 0
+	}
+
+	ceiling := m.policy.BaseBackoff << min(attempt, 30)
+	if ceiling < m.policy.BaseBackoff {
+		// The shift overflowed
+		ceiling = math.MaxInt64
 	}
+	if m.policy.MaxBackoff > 0 {
+		ceiling = min(ceiling, m.policy.MaxBackoff)
+	}
+
+	return rand.N(ceiling)
+}
+
+// isIdempotent reports whether a request may be sent again after it possibly
+// reached the server. Like http.Transport, it treats requests carrying an
+// Idempotency-Key or X-Idempotency-Key header (even a nil one) as idempotent.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	_, ok := req.Header["X-Idempotency-Key"]
+	return ok
+}
+
+// isTransient reports whether a transport error is likely to go away on retry
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// rewind returns a copy of req with a fresh body, or false if the body cannot be replayed
+func rewind(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+
+	next := req.Clone(req.Context())
+	next.Body = body
+	return next, true
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
 
-	return resp, nil
+// drainAndClose discards a bit of a response body so the connection can be reused
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, 4<<10)
+	_ = body.Close()
 }
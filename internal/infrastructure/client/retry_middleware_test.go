@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusServer answers with the given status codes in turn and records the request bodies
+func statusServer(t *testing.T, header http.Header, codes ...int) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var (
+		calls  atomic.Int64
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		i := int(calls.Add(1)) - 1
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(codes[min(i, len(codes)-1)])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &bodies
+}
+
+func TestRetryMiddleware_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		method string
+		header http.Header
+		codes  []int
+		want   int
+		calls  int
+	}{
+		{
+			name:   "retries rate limiting",
+			policy: RetryPolicy{MaxRetries: 3, BaseBackoff: time.Millisecond},
+			method: http.MethodPost,
+			codes:  []int{http.StatusTooManyRequests, 420, http.StatusOK},
+			want:   http.StatusOK,
+			calls:  3,
+		},
+		{
+			name:   "gives up after max retries",
+			policy: RetryPolicy{MaxRetries: 2, BaseBackoff: time.Millisecond},
+			method: http.MethodGet,
+			codes:  []int{http.StatusTooManyRequests},
+			want:   http.StatusTooManyRequests,
+			calls:  3,
+		},
+		{
+			name:   "server errors are not retried by default",
+			policy: RetryPolicy{MaxRetries: 3, BaseBackoff: time.Millisecond},
+			method: http.MethodGet,
+			codes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:   http.StatusServiceUnavailable,
+			calls:  1,
+		},
+		{
+			name:   "retries server errors on idempotent requests",
+			policy: RetryPolicy{MaxRetries: 3, BaseBackoff: time.Millisecond, RetryServerErrors: true},
+			method: http.MethodGet,
+			codes:  []int{http.StatusBadGateway, http.StatusGatewayTimeout, http.StatusOK},
+			want:   http.StatusOK,
+			calls:  3,
+		},
+		{
+			name:   "does not retry server errors on other requests",
+			policy: RetryPolicy{MaxRetries: 3, BaseBackoff: time.Millisecond, RetryServerErrors: true},
+			method: http.MethodPost,
+			codes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			want:   http.StatusServiceUnavailable,
+			calls:  1,
+		},
+		{
+			name:   "Retry-After beyond the total retry time",
+			policy: RetryPolicy{MaxRetries: 3, BaseBackoff: time.Millisecond, MaxRetryTime: time.Second},
+			method: http.MethodGet,
+			header: http.Header{"Retry-After": {"5"}},
+			codes:  []int{http.StatusTooManyRequests, http.StatusOK},
+			want:   http.StatusTooManyRequests,
+			calls:  1,
+		},
+		{
+			name:   "Retry-After beyond the maximum backoff",
+			policy: RetryPolicy{MaxRetries: 3, BaseBackoff: time.Millisecond, MaxBackoff: time.Second},
+			method: http.MethodGet,
+			header: http.Header{"Retry-After": {"5"}},
+			codes:  []int{http.StatusTooManyRequests, http.StatusOK},
+			want:   http.StatusTooManyRequests,
+			calls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, bodies := statusServer(t, tt.header, tt.codes...)
+			client := &http.Client{Transport: NewRetryMiddleware(http.DefaultTransport, tt.policy)}
+
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader("payload"))
+			require.NoError(t, err)
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.want, resp.StatusCode)
+			require.Len(t, *bodies, tt.calls)
+			for _, body := range *bodies {
+				assert.Equal(t, "payload", body)
+			}
+		})
+	}
+}
+
+func TestRetryMiddleware_RetryAfter(t *testing.T) {
+	server, bodies := statusServer(t, http.Header{"Retry-After": {"1"}}, http.StatusTooManyRequests, http.StatusOK)
+	client := &http.Client{Transport: NewRetryMiddleware(http.DefaultTransport, RetryPolicy{
+		MaxRetries:  1,
+		BaseBackoff: time.Millisecond,
+	})}
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, *bodies, 2)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRetryMiddleware_ContextCancelled(t *testing.T) {
+	server, bodies := statusServer(t, nil, http.StatusTooManyRequests)
+	client := &http.Client{Transport: NewRetryMiddleware(http.DefaultTransport, RetryPolicy{
+		MaxRetries:  3,
+		BaseBackoff: time.Hour,
+	})}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestRetryMiddleware_NetworkError(t *testing.T) {
+	server, _ := statusServer(t, nil, http.StatusOK)
+	url := server.URL
+	server.Close()
+
+	var retries atomic.Int64
+	client := &http.Client{Transport: NewRetryMiddleware(http.DefaultTransport, RetryPolicy{
+		MaxRetries:        2,
+		BaseBackoff:       time.Millisecond,
+		RetryServerErrors: true,
+	}).OnRetry(func(_ *http.Request, statusCode int) {
+		assert.Zero(t, statusCode)
+		retries.Add(1)
+	})}
+
+	_, err := client.Get(url)
+	assert.Error(t, err)
+	assert.Equal(t, int64(2), retries.Load())
+}
+
+func TestRetryMiddleware_Backoff(t *testing.T) {
+	m := NewRetryMiddleware(http.DefaultTransport, RetryPolicy{
+		BaseBackoff: 10 * time.Millisecond,
+		MaxBackoff:  50 * time.Millisecond,
+	})
+
+	for attempt := 0; attempt < 100; attempt++ {
+		ceiling := min(10*time.Millisecond<<min(attempt, 30), 50*time.Millisecond)
+		backoff := m.backoff(attempt)
+		assert.GreaterOrEqual(t, backoff, time.Duration(0))
+		assert.Less(t, backoff, ceiling)
+	}
+}
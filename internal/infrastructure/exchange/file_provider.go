@@ -0,0 +1,81 @@
+package exchange
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"route256/cart/internal/domain/ports"
+)
+
+// rateFile is the layout of an exchange rate file
+type rateFile struct {
+	// Base is the currency all rates are quoted in
+	Base string `yaml:"base"`
+
+	// Rates maps currency codes to the value of one unit in the base currency.
+	// Values are decimal strings so that they are read exactly.
+	Rates map[string]string `yaml:"rates"`
+}
+
+// FileProvider implements ports.ExchangeRateProvider with rates read from a YAML file
+type FileProvider struct {
+	// rates holds the value of one unit of each currency in the base currency
+	rates map[string]*big.Rat
+}
+
+// NewFileProvider loads exchange rates from a YAML file such as:
+//
+//	base: RUB
+//	rates:
+//	  USD: "92.50"
+//	  EUR: "100.25"
+func NewFileProvider(path string) (*FileProvider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+
+	var file rateFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+
+	if file.Base == "" {
+		return nil, errors.New("exchange rates have no base currency")
+	}
+
+	rates := map[string]*big.Rat{
+		file.Base: big.NewRat(1, 1),
+	}
+	for currency, value := range file.Rates {
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s: %q", currency, value)
+		}
+		rates[currency] = rate
+	}
+
+	return &FileProvider{
+		rates: rates,
+	}, nil
+}
+
+// Rate implements ports.ExchangeRateProvider
+func (p *FileProvider) Rate(_ context.Context, from, to string) (*big.Rat, error) {
+	fromRate, ok := p.rates[from]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrExchangeRateNotFound, from)
+	}
+
+	toRate, ok := p.rates[to]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrExchangeRateNotFound, to)
+	}
+
+	return new(big.Rat).Quo(fromRate, toRate), nil
+}
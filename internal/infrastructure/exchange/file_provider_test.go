@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/ports"
+)
+
+// writeRates writes an exchange rate file and returns its path
+func writeRates(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rates.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileProvider_Rate(t *testing.T) {
+	provider, err := NewFileProvider(writeRates(t, `
+base: RUB
+rates:
+  USD: "92.50"
+  EUR: "100"
+`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     *big.Rat
+		wantErr  error
+	}{
+		{name: "to base", from: "USD", to: "RUB", want: big.NewRat(185, 2)},
+		{name: "from base", from: "RUB", to: "EUR", want: big.NewRat(1, 100)},
+		{name: "cross rate", from: "EUR", to: "USD", want: big.NewRat(40, 37)},
+		{name: "same currency", from: "USD", to: "USD", want: big.NewRat(1, 1)},
+		{name: "unknown currency", from: "GBP", to: "RUB", wantErr: ports.ErrExchangeRateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := provider.Rate(context.Background(), tt.from, tt.to)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Zero(t, tt.want.Cmp(rate), "got %s", rate)
+		})
+	}
+}
+
+func TestNewFileProvider_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "no base", content: "rates:\n  USD: \"92.5\"\n"},
+		{name: "malformed rate", content: "base: RUB\nrates:\n  USD: \"abc\"\n"},
+		{name: "negative rate", content: "base: RUB\nrates:\n  USD: \"-1\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileProvider(writeRates(t, tt.content))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+	apiErrors "route256/cart/internal/infrastructure/api/errors"
+	"route256/cart/internal/usecase/cart"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps service errors to gRPC statuses the same way
+// api.Handler maps them to HTTP status codes
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, models.ErrVersionConflict),
+		errors.Is(err, models.ErrProductNotFound),
+		errors.Is(err, models.ErrProductGone),
+		errors.Is(err, cart.ErrInsufficientStock),
+		errors.Is(err, models.ErrLimitExceeded),
+		errors.Is(err, models.ErrMoneyOverflow),
+		errors.Is(err, ports.ErrRegionNotFound):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, models.ErrCartNotFound):
+		return status.Error(codes.NotFound, "cart not found")
+	case errors.Is(err, models.ErrCouponNotFound):
+		return status.Error(codes.NotFound, "coupon not found")
+	case errors.Is(err, ports.ErrExchangeRateNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, cart.ErrPriceChanged):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, cart.ErrCartEmpty):
+		return status.Error(codes.InvalidArgument, "cart is empty")
+	case errors.Is(err, models.ErrDependencyUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request cancelled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	if apiErr, ok := apiErrors.IsAPIError(err); ok {
+		return status.Error(httpToCode(apiErr.Code), apiErr.Error())
+	}
+
+	log.Printf("gRPC request failed: %v", err)
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// httpToCode converts an HTTP status code to the closest gRPC code
+func httpToCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,26 @@
+package grpcserver
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// LoggingInterceptor logs information about each unary call
+func LoggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	log.Printf(
+		"method=%s code=%s duration=%s",
+		info.FullMethod,
+		status.Code(err),
+		time.Since(start),
+	)
+
+	return resp, err
+}
@@ -0,0 +1,309 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+
+	cartpb "route256/cart/api/protos/gen/cart"
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+	"route256/cart/internal/usecase/cart"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the cart gRPC API over ports.CartService
+type Server struct {
+	cartpb.UnimplementedCartServer
+
+	service ports.CartService
+}
+
+// NewServer creates a new cart gRPC server
+func NewServer(service ports.CartService) *Server {
+	return &Server{
+		service: service,
+	}
+}
+
+// AddItem implements cartpb.CartServer
+func (s *Server) AddItem(ctx context.Context, req *cartpb.AddItemRequest) (*cartpb.AddItemResponse, error) {
+	if err := validateUserAndSKU(req.GetUser(), req.GetSku()); err != nil {
+		return nil, err
+	}
+
+	if req.GetCount() == 0 || req.GetCount() > math.MaxUint16 {
+		return nil, status.Error(codes.InvalidArgument, "invalid count")
+	}
+
+	if err := s.service.AddItem(ctx, req.GetUser(), req.GetSku(), uint16(req.GetCount()), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.AddItemResponse{}, nil
+}
+
+// AddItems implements cartpb.CartServer
+func (s *Server) AddItems(ctx context.Context, req *cartpb.AddItemsRequest) (*cartpb.AddItemsResponse, error) {
+	if req.GetUser() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	if len(req.GetItems()) == 0 || len(req.GetItems()) > models.MaxBatchItems {
+		return nil, status.Errorf(codes.InvalidArgument, "between 1 and %d items are required", models.MaxBatchItems)
+	}
+
+	items := make([]models.BatchItem, len(req.GetItems()))
+	for i, item := range req.GetItems() {
+		if item.GetSku() == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "item %d: invalid sku", i)
+		}
+		if item.GetCount() == 0 || item.GetCount() > math.MaxUint16 {
+			return nil, status.Errorf(codes.InvalidArgument, "item %d: invalid count", i)
+		}
+		items[i] = models.BatchItem{SKU: item.GetSku(), Count: uint16(item.GetCount())}
+	}
+
+	results, err := s.service.AddItems(ctx, req.GetUser(), items, req.GetExpectedVersion())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &cartpb.AddItemsResponse{
+		Results: make([]*cartpb.BatchItemResult, len(results)),
+	}
+	for i, result := range results {
+		resp.Results[i] = &cartpb.BatchItemResult{
+			Sku:   result.SKU,
+			Count: uint32(result.Count),
+			Added: result.Err == nil,
+		}
+		if result.Err != nil {
+			resp.Results[i].Reason = cart.FailureReason(result.Err)
+			resp.Results[i].Error = result.Err.Error()
+		}
+	}
+
+	return resp, nil
+}
+
+// RemoveItem implements cartpb.CartServer
+func (s *Server) RemoveItem(ctx context.Context, req *cartpb.RemoveItemRequest) (*cartpb.RemoveItemResponse, error) {
+	if err := validateUserAndSKU(req.GetUser(), req.GetSku()); err != nil {
+		return nil, err
+	}
+
+	if err := s.service.RemoveItem(ctx, req.GetUser(), req.GetSku(), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.RemoveItemResponse{}, nil
+}
+
+// SetItemQuantity implements cartpb.CartServer
+func (s *Server) SetItemQuantity(ctx context.Context, req *cartpb.SetItemQuantityRequest) (*cartpb.SetItemQuantityResponse, error) {
+	if err := validateUserAndSKU(req.GetUser(), req.GetSku()); err != nil {
+		return nil, err
+	}
+
+	if req.GetCount() > math.MaxUint16 {
+		return nil, status.Error(codes.InvalidArgument, "invalid count")
+	}
+
+	if err := s.service.SetItemQuantity(ctx, req.GetUser(), req.GetSku(), uint16(req.GetCount()), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.SetItemQuantityResponse{}, nil
+}
+
+// ChangeItemQuantity implements cartpb.CartServer
+func (s *Server) ChangeItemQuantity(ctx context.Context, req *cartpb.ChangeItemQuantityRequest) (*cartpb.ChangeItemQuantityResponse, error) {
+	if err := validateUserAndSKU(req.GetUser(), req.GetSku()); err != nil {
+		return nil, err
+	}
+
+	if req.GetDelta() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid delta")
+	}
+
+	if err := s.service.ChangeItemQuantity(ctx, req.GetUser(), req.GetSku(), req.GetDelta(), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.ChangeItemQuantityResponse{}, nil
+}
+
+// ClearCart implements cartpb.CartServer
+func (s *Server) ClearCart(ctx context.Context, req *cartpb.ClearCartRequest) (*cartpb.ClearCartResponse, error) {
+	if req.GetUser() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	if err := s.service.ClearCart(ctx, req.GetUser(), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.ClearCartResponse{}, nil
+}
+
+// GetCart implements cartpb.CartServer
+func (s *Server) GetCart(ctx context.Context, req *cartpb.GetCartRequest) (*cartpb.GetCartResponse, error) {
+	if req.GetUser() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	if currency := req.GetCurrency(); currency != "" && !models.IsCurrencyCode(currency) {
+		return nil, status.Error(codes.InvalidArgument, "invalid currency")
+	}
+
+	cart, err := s.service.GetCart(ctx, req.GetUser(), req.GetCurrency())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	if cart.UnknownRegion != "" {
+		log.Printf("Cart of user %d has region %s without tax rates, taxed at the default ones", req.GetUser(), cart.UnknownRegion)
+	}
+
+	items := make([]*cartpb.CartItem, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = &cartpb.CartItem{
+			Sku:          item.SKU,
+			Count:        uint32(item.Quantity),
+			Price:        toMoney(item.Price),
+			Name:         item.Name,
+			PriceChanged: item.PriceChanged(),
+			Unavailable:  item.Unavailable,
+		}
+		if item.PriceChanged() {
+			items[i].PreviousPrice = toMoney(item.PreviousPrice)
+		}
+		if !item.Discount.IsZero() {
+			items[i].Discount = toMoney(item.Discount)
+		}
+	}
+
+	promotions := make([]*cartpb.AppliedPromotion, len(cart.Promotions))
+	for i, promotion := range cart.Promotions {
+		promotions[i] = &cartpb.AppliedPromotion{
+			Id:          promotion.ID,
+			Description: promotion.Description,
+			Discount:    toMoney(promotion.Discount),
+		}
+	}
+
+	taxes := make([]*cartpb.TaxLine, len(cart.Taxes))
+	for i, tax := range cart.Taxes {
+		taxes[i] = &cartpb.TaxLine{
+			Category: tax.Category,
+			Rate:     tax.RatePercent(),
+			Taxable:  toMoney(tax.Taxable),
+			Amount:   toMoney(tax.Amount),
+		}
+	}
+
+	return &cartpb.GetCartResponse{
+		Items:           items,
+		TotalPrice:      toMoney(cart.TotalPrice),
+		Version:         cart.Version,
+		Coupon:          cart.Coupon,
+		Promotions:      promotions,
+		Discount:        toMoney(cart.Discount),
+		DiscountedTotal: toMoney(cart.DiscountedTotal),
+		Region:          cart.Region,
+		Subtotal:        toMoney(cart.Subtotal),
+		Taxes:           taxes,
+		GrandTotal:      toMoney(cart.GrandTotal),
+	}, nil
+}
+
+// toMoney converts a domain amount of money to its protobuf representation
+func toMoney(m models.Money) *cartpb.Money {
+	return &cartpb.Money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
+
+// Checkout implements cartpb.CartServer
+func (s *Server) Checkout(ctx context.Context, req *cartpb.CheckoutRequest) (*cartpb.CheckoutResponse, error) {
+	if req.GetUser() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	orderID, err := s.service.Checkout(ctx, req.GetUser(), req.GetExpectedVersion(), req.GetConfirmPriceChanges())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.CheckoutResponse{
+		OrderID: orderID,
+	}, nil
+}
+
+// ApplyCoupon implements cartpb.CartServer
+func (s *Server) ApplyCoupon(ctx context.Context, req *cartpb.ApplyCouponRequest) (*cartpb.ApplyCouponResponse, error) {
+	if req.GetUser() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	if strings.TrimSpace(req.GetCode()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid coupon code")
+	}
+
+	if err := s.service.ApplyCoupon(ctx, req.GetUser(), req.GetCode(), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.ApplyCouponResponse{}, nil
+}
+
+// RemoveCoupon implements cartpb.CartServer
+func (s *Server) RemoveCoupon(ctx context.Context, req *cartpb.RemoveCouponRequest) (*cartpb.RemoveCouponResponse, error) {
+	if req.GetUser() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	if err := s.service.RemoveCoupon(ctx, req.GetUser(), req.GetExpectedVersion()); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.RemoveCouponResponse{}, nil
+}
+
+// SetRegion implements cartpb.CartServer
+func (s *Server) SetRegion(ctx context.Context, req *cartpb.SetRegionRequest) (*cartpb.SetRegionResponse, error) {
+	if req.GetUser() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	if strings.TrimSpace(req.GetRegion()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "invalid region")
+	}
+
+	if err := s.service.SetRegion(ctx, req.GetUser(), req.GetRegion(), req.GetExpectedVersion()); err != nil {
+		if errors.Is(err, ports.ErrRegionNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "unsupported region")
+		}
+		return nil, toStatus(err)
+	}
+
+	return &cartpb.SetRegionResponse{}, nil
+}
+
+// validateUserAndSKU checks the identifiers shared by item requests
+func validateUserAndSKU(userID int64, sku uint32) error {
+	if userID <= 0 {
+		return status.Error(codes.InvalidArgument, "invalid user")
+	}
+
+	if sku == 0 {
+		return status.Error(codes.InvalidArgument, "invalid sku")
+	}
+
+	return nil
+}
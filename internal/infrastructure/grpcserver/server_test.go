@@ -0,0 +1,184 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cartpb "route256/cart/api/protos/gen/cart"
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+	"route256/cart/internal/usecase/cart"
+)
+
+// rub returns an amount in the default currency
+func rub(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
+// stubService is a ports.CartService returning a fixed result
+type stubService struct {
+	ports.CartService
+
+	cart *models.Cart
+	err  error
+}
+
+func (s *stubService) AddItem(_ context.Context, _ int64, _ uint32, _ uint16, _ uint64) error {
+	return s.err
+}
+
+func (s *stubService) GetCart(_ context.Context, _ int64, _ string) (*models.Cart, error) {
+	return s.cart, s.err
+}
+
+func TestServer_AddItemStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *cartpb.AddItemRequest
+		err      error
+		wantCode codes.Code
+	}{
+		{
+			name:     "success",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "invalid user",
+			req:      &cartpb.AddItemRequest{User: 0, Sku: 123, Count: 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid sku",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 0, Count: 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "count out of range",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1 << 16},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "product not found",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1},
+			err:      models.ErrProductNotFound,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "not enough stock",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1},
+			err:      cart.ErrInsufficientStock,
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "limit exceeded",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1},
+			err:      &models.LimitExceededError{Limit: models.LimitTotalItems, Max: 10},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "version conflict",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1, ExpectedVersion: 1},
+			err:      &models.VersionConflictError{UserID: 1, Expected: 1, Actual: 2},
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "cancelled",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1},
+			err:      fmt.Errorf("get product 123: %w", context.Canceled),
+			wantCode: codes.Canceled,
+		},
+		{
+			name:     "deadline exceeded",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1},
+			err:      fmt.Errorf("get product 123: %w", context.DeadlineExceeded),
+			wantCode: codes.DeadlineExceeded,
+		},
+		{
+			name:     "internal error",
+			req:      &cartpb.AddItemRequest{User: 1, Sku: 123, Count: 1},
+			err:      fmt.Errorf("storage: %w", errors.New("disk full")),
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewServer(&stubService{err: tt.err})
+
+			_, err := server.AddItem(context.Background(), tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+// batchService is a ports.CartService adding every product but 404
+type batchService struct {
+	ports.CartService
+}
+
+func (s *batchService) AddItems(_ context.Context, _ int64, items []models.BatchItem, _ uint64) ([]models.BatchItemResult, error) {
+	results := make([]models.BatchItemResult, len(items))
+	for i, item := range items {
+		results[i] = models.BatchItemResult{SKU: item.SKU, Count: item.Count}
+		if item.SKU == 404 {
+			results[i].Err = models.ErrProductNotFound
+		}
+	}
+	return results, nil
+}
+
+func TestServer_AddItems(t *testing.T) {
+	server := NewServer(&batchService{})
+
+	resp, err := server.AddItems(context.Background(), &cartpb.AddItemsRequest{
+		User:  1,
+		Items: []*cartpb.BatchItem{{Sku: 1, Count: 2}, {Sku: 404, Count: 1}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.GetResults(), 2)
+	assert.True(t, resp.GetResults()[0].GetAdded())
+	assert.Empty(t, resp.GetResults()[0].GetReason())
+	assert.False(t, resp.GetResults()[1].GetAdded())
+	assert.Equal(t, "product_not_found", resp.GetResults()[1].GetReason())
+
+	for _, req := range []*cartpb.AddItemsRequest{
+		{User: 0, Items: []*cartpb.BatchItem{{Sku: 1, Count: 1}}},
+		{User: 1},
+		{User: 1, Items: []*cartpb.BatchItem{{Sku: 0, Count: 1}}},
+		{User: 1, Items: []*cartpb.BatchItem{{Sku: 1, Count: 1 << 16}}},
+	} {
+		_, err := server.AddItems(context.Background(), req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestServer_GetCart(t *testing.T) {
+	server := NewServer(&stubService{cart: &models.Cart{
+		UserID:     1,
+		Items:      models.ItemList{{SKU: 123, Quantity: 2, Price: rub(1000)}},
+		TotalPrice: rub(2000),
+		Version:    3,
+	}})
+
+	resp, err := server.GetCart(context.Background(), &cartpb.GetCartRequest{User: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2000), resp.GetTotalPrice().GetAmount())
+	assert.Equal(t, models.DefaultCurrency, resp.GetTotalPrice().GetCurrency())
+	assert.Equal(t, uint64(3), resp.GetVersion())
+	require.Len(t, resp.GetItems(), 1)
+	assert.Equal(t, uint32(123), resp.GetItems()[0].GetSku())
+	assert.Equal(t, uint32(2), resp.GetItems()[0].GetCount())
+
+	_, err = NewServer(&stubService{err: models.ErrCartNotFound}).
+		GetCart(context.Background(), &cartpb.GetCartRequest{User: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -0,0 +1,85 @@
+package guest
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"route256/cart/internal/domain/ports"
+)
+
+const (
+	// idSize is the size of the encoded guest number
+	idSize = 8
+
+	// macSize is the size of the truncated signature of the guest number
+	macSize = 16
+
+	// maxGuest bounds guest numbers so that their negation fits an int64
+	maxGuest = 1 << 62
+)
+
+// Tokens implements ports.GuestTokens with signed tokens: a token is a random
+// guest number and its HMAC-SHA256, so resolving one needs no storage
+type Tokens struct {
+	secret []byte
+}
+
+// NewTokens creates tokens signed with secret. An empty secret is replaced
+// with a random one, so tokens are only valid until the process exits.
+func NewTokens(secret []byte) (*Tokens, error) {
+	if len(secret) == 0 {
+		secret = make([]byte, sha256.Size)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("generate guest token secret: %w", err)
+		}
+	}
+
+	return &Tokens{
+		secret: secret,
+	}, nil
+}
+
+// Issue implements ports.GuestTokens
+func (t *Tokens) Issue() (string, int64, error) {
+	var buf [idSize]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", 0, fmt.Errorf("generate guest token: %w", err)
+	}
+	guest := binary.BigEndian.Uint64(buf[:])%maxGuest + 1
+
+	token := make([]byte, idSize, idSize+macSize)
+	binary.BigEndian.PutUint64(token, guest)
+	token = append(token, t.sign(token)...)
+
+	return base64.RawURLEncoding.EncodeToString(token), -int64(guest), nil
+}
+
+// Resolve implements ports.GuestTokens
+func (t *Tokens) Resolve(token string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != idSize+macSize {
+		return 0, ports.ErrInvalidGuestToken
+	}
+
+	if !hmac.Equal(data[idSize:], t.sign(data[:idSize])) {
+		return 0, ports.ErrInvalidGuestToken
+	}
+
+	guest := binary.BigEndian.Uint64(data[:idSize])
+	if guest == 0 || guest > maxGuest {
+		return 0, ports.ErrInvalidGuestToken
+	}
+
+	return -int64(guest), nil
+}
+
+// sign returns the truncated signature of a guest number
+func (t *Tokens) sign(guest []byte) []byte {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write(guest)
+	return mac.Sum(nil)[:macSize]
+}
@@ -0,0 +1,63 @@
+package guest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+func TestTokens(t *testing.T) {
+	tokens, err := NewTokens([]byte("secret"))
+	require.NoError(t, err)
+
+	token, guestID, err := tokens.Issue()
+	require.NoError(t, err)
+	assert.True(t, models.IsGuestCart(guestID))
+
+	resolved, err := tokens.Resolve(token)
+	require.NoError(t, err)
+	assert.Equal(t, guestID, resolved)
+
+	other, otherID, err := tokens.Issue()
+	require.NoError(t, err)
+	assert.NotEqual(t, token, other)
+	assert.NotEqual(t, guestID, otherID)
+
+	// Tokens survive a restart with the same secret only
+	restarted, err := NewTokens([]byte("secret"))
+	require.NoError(t, err)
+	resolved, err = restarted.Resolve(token)
+	require.NoError(t, err)
+	assert.Equal(t, guestID, resolved)
+
+	random, err := NewTokens(nil)
+	require.NoError(t, err)
+	_, err = random.Resolve(token)
+	assert.ErrorIs(t, err, ports.ErrInvalidGuestToken)
+}
+
+func TestTokens_ResolveInvalid(t *testing.T) {
+	tokens, err := NewTokens([]byte("secret"))
+	require.NoError(t, err)
+
+	token, _, err := tokens.Issue()
+	require.NoError(t, err)
+
+	tampered := []byte(token)
+	tampered[0] ^= 1
+
+	for _, token := range []string{
+		"",
+		"not a token",
+		token[:len(token)-1],
+		token + "A",
+		string(tampered),
+	} {
+		_, err := tokens.Resolve(token)
+		assert.ErrorIs(t, err, ports.ErrInvalidGuestToken, token)
+	}
+}
@@ -0,0 +1,97 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+
+	"route256/cart/internal/domain/ports"
+)
+
+// DefaultTTL is how long completed keys are remembered when no TTL is configured
+const DefaultTTL = 24 * time.Hour
+
+// entry is the state of a single idempotency key
+type entry struct {
+	orderID   int64
+	completed bool
+	expiresAt time.Time
+}
+
+// Store implements ports.IdempotencyStore interface
+// using in-memory storage
+type Store struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[string]*entry
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+// NewStore creates a new in-memory idempotency store that remembers keys for ttl
+func NewStore(ttl time.Duration) *Store {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Store{
+		ttl:     ttl,
+		entries: make(map[string]*entry),
+		now:     time.Now,
+	}
+}
+
+// Begin implements ports.IdempotencyStore
+func (s *Store) Begin(key string) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.purge(now)
+
+	if e, exists := s.entries[key]; exists && now.Before(e.expiresAt) {
+		if !e.completed {
+			return 0, false, ports.ErrIdempotencyKeyInProgress
+		}
+		return e.orderID, true, nil
+	}
+
+	s.entries[key] = &entry{expiresAt: now.Add(s.ttl)}
+	return 0, false, nil
+}
+
+// Complete implements ports.IdempotencyStore
+func (s *Store) Complete(key string, orderID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &entry{
+		orderID:   orderID,
+		completed: true,
+		expiresAt: s.now().Add(s.ttl),
+	}
+}
+
+// Abort implements ports.IdempotencyStore
+func (s *Store) Abort(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, exists := s.entries[key]; exists && !e.completed {
+		delete(s.entries, key)
+	}
+}
+
+// purge drops expired keys at most once per TTL.
+// The caller must hold s.mu.
+func (s *Store) purge(now time.Time) {
+	if now.Sub(s.lastPurge) < s.ttl {
+		return
+	}
+
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastPurge = now
+}
@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"route256/cart/internal/domain/ports"
+)
+
+func TestStore_Begin(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(s *Store, clock *time.Time)
+		wantOrderID int64
+		wantReplay  bool
+		wantErr     error
+	}{
+		{
+			name:  "new key",
+			setup: func(s *Store, clock *time.Time) {},
+		},
+		{
+			name: "key in flight",
+			setup: func(s *Store, clock *time.Time) {
+				_, _, _ = s.Begin("key")
+			},
+			wantErr: ports.ErrIdempotencyKeyInProgress,
+		},
+		{
+			name: "completed key is replayed",
+			setup: func(s *Store, clock *time.Time) {
+				_, _, _ = s.Begin("key")
+				s.Complete("key", 42)
+			},
+			wantOrderID: 42,
+			wantReplay:  true,
+		},
+		{
+			name: "aborted key can be retried",
+			setup: func(s *Store, clock *time.Time) {
+				_, _, _ = s.Begin("key")
+				s.Abort("key")
+			},
+		},
+		{
+			name: "completed key expires",
+			setup: func(s *Store, clock *time.Time) {
+				_, _, _ = s.Begin("key")
+				s.Complete("key", 42)
+				*clock = clock.Add(time.Hour)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			s := NewStore(time.Hour)
+			s.now = func() time.Time { return clock }
+			tt.setup(s, &clock)
+
+			orderID, replay, err := s.Begin("key")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOrderID, orderID)
+			assert.Equal(t, tt.wantReplay, replay)
+		})
+	}
+}
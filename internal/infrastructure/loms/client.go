@@ -5,8 +5,10 @@ import (
 	"log"
 
 	loms "route256/cart/api/protos/gen/loms"
+	"route256/cart/internal/domain/models"
 	"route256/cart/internal/domain/ports"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -18,7 +20,12 @@ type client struct {
 // NewClient creates a new LOMS client
 func NewClient(address string) (ports.LOMSClient, error) {
 	log.Printf("Connecting to LOMS service at %s", address)
-	conn, err := grpc.DialContext(context.Background(), address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.DialContext(
+		context.Background(),
+		address,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -33,8 +40,10 @@ func (c *client) CreateOrder(ctx context.Context, userID int64, items []ports.It
 	reqItems := make([]*loms.Item, len(items))
 	for i, item := range items {
 		reqItems[i] = &loms.Item{
-			Sku:   item.SKU,
-			Count: uint32(item.Count),
+			Sku:      item.SKU,
+			Count:    uint32(item.Count),
+			Price:    item.Price.Amount,
+			Currency: item.Price.Currency,
 		}
 	}
 
@@ -80,6 +89,7 @@ func (c *client) GetOrderInfo(ctx context.Context, orderID int64) (*ports.OrderI
 		items[i] = ports.Item{
 			SKU:   item.Sku,
 			Count: uint16(item.Count),
+			Price: models.NewMoney(item.Price, item.Currency),
 		}
 	}
 
@@ -89,3 +99,16 @@ func (c *client) GetOrderInfo(ctx context.Context, orderID int64) (*ports.OrderI
 		Items:  items,
 	}, nil
 }
+
+func (c *client) CancelOrder(ctx context.Context, orderID int64) error {
+	log.Printf("Cancelling order %d", orderID)
+	_, err := c.lomsClient.OrderCancel(ctx, &loms.OrderCancelRequest{
+		OrderID: orderID,
+	})
+	if err != nil {
+		log.Printf("Failed to cancel order: %v", err)
+		return err
+	}
+
+	return nil
+}
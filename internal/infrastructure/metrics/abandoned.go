@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"context"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// abandonedCartNotifier decorates ports.AbandonedCartNotifier with delivery metrics
+type abandonedCartNotifier struct {
+	next ports.AbandonedCartNotifier
+}
+
+// NewAbandonedCartNotifier wraps notifier so that every notification is counted
+func NewAbandonedCartNotifier(notifier ports.AbandonedCartNotifier) ports.AbandonedCartNotifier {
+	return &abandonedCartNotifier{
+		next: notifier,
+	}
+}
+
+// NotifyAbandonedCart implements ports.AbandonedCartNotifier
+func (n *abandonedCartNotifier) NotifyAbandonedCart(ctx context.Context, event models.AbandonedCart) error {
+	err := n.next.NotifyAbandonedCart(ctx, event)
+	abandonedCartNotifications.WithLabelValues(result(err)).Inc()
+	return err
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+const (
+	productServiceName = "product"
+	lomsServiceName    = "loms"
+)
+
+// productService decorates ports.ProductService with call metrics
+type productService struct {
+	next ports.ProductService
+}
+
+// NewProductService wraps service so that every call is measured
+func NewProductService(service ports.ProductService) ports.ProductService {
+	return &productService{
+		next: service,
+	}
+}
+
+// GetProduct implements ports.ProductService
+func (s *productService) GetProduct(ctx context.Context, sku uint32) (product *models.Product, err error) {
+	defer func(start time.Time) { observeClientCall(productServiceName, "get_product", start, err) }(time.Now())
+	return s.next.GetProduct(ctx, sku)
+}
+
+// lomsClient decorates ports.LOMSClient with call metrics
+type lomsClient struct {
+	next ports.LOMSClient
+}
+
+// NewLOMSClient wraps client so that every call is measured
+func NewLOMSClient(client ports.LOMSClient) ports.LOMSClient {
+	return &lomsClient{
+		next: client,
+	}
+}
+
+// CreateOrder implements ports.LOMSClient
+func (c *lomsClient) CreateOrder(ctx context.Context, userID int64, items []ports.Item) (orderID int64, err error) {
+	defer func(start time.Time) { observeClientCall(lomsServiceName, "create_order", start, err) }(time.Now())
+	return c.next.CreateOrder(ctx, userID, items)
+}
+
+// GetStocksInfo implements ports.LOMSClient
+func (c *lomsClient) GetStocksInfo(ctx context.Context, sku uint32) (count uint64, err error) {
+	defer func(start time.Time) { observeClientCall(lomsServiceName, "get_stocks_info", start, err) }(time.Now())
+	return c.next.GetStocksInfo(ctx, sku)
+}
+
+// GetOrderInfo implements ports.LOMSClient
+func (c *lomsClient) GetOrderInfo(ctx context.Context, orderID int64) (info *ports.OrderInfo, err error) {
+	defer func(start time.Time) { observeClientCall(lomsServiceName, "get_order_info", start, err) }(time.Now())
+	return c.next.GetOrderInfo(ctx, orderID)
+}
+
+// CancelOrder implements ports.LOMSClient
+func (c *lomsClient) CancelOrder(ctx context.Context, orderID int64) (err error) {
+	defer func(start time.Time) { observeClientCall(lomsServiceName, "cancel_order", start, err) }(time.Now())
+	return c.next.CancelOrder(ctx, orderID)
+}
@@ -0,0 +1,157 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cart"
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route pattern and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpRequestsInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	})
+
+	repositoryOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "operation_duration_seconds",
+		Help:      "Cart repository operation latency by operation and result.",
+		Buckets:   []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1},
+	}, []string{"operation", "result"})
+
+	repositoryExpiredCarts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "expired_carts_total",
+		Help:      "Number of carts deleted for being unchanged longer than their TTL.",
+	})
+
+	abandonedCartNotifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "abandoned_carts",
+		Name:      "notifications_total",
+		Help:      "Number of abandoned cart notifications by result.",
+	}, []string{"result"})
+
+	clientRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "client",
+		Name:      "requests_total",
+		Help:      "Number of calls to external services by service and method.",
+	}, []string{"service", "method"})
+
+	clientErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "client",
+		Name:      "errors_total",
+		Help:      "Number of failed calls to external services by service and method.",
+	}, []string{"service", "method"})
+
+	clientRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "client",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of calls to external services by service and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	clientRetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "client",
+		Name:      "retries_total",
+		Help:      "Number of HTTP retries by service and the status code that caused them.",
+	}, []string{"service", "status"})
+
+	clientRateLimitWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "client",
+		Name:      "rate_limit_wait_seconds",
+		Help:      "Time HTTP requests waited for the client-side rate limiter by service.",
+		Buckets:   []float64{0, .001, .005, .01, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"service"})
+)
+
+// Handler returns the HTTP handler exposing metrics in Prometheus format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// HTTPRequestStarted tracks a request entering the server
+func HTTPRequestStarted() {
+	httpRequestsInFlight.Inc()
+}
+
+// HTTPRequestFinished records a served request. Route is the matched
+// ServeMux pattern so that path parameters do not explode label cardinality.
+func HTTPRequestFinished(method, route string, statusCode int, duration time.Duration) {
+	httpRequestsInFlight.Dec()
+
+	if route == "" {
+		route = "unmatched"
+	}
+	status := strconv.Itoa(statusCode)
+
+	httpRequestsTotal.WithLabelValues(method, route, status).Inc()
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(duration.Seconds())
+}
+
+// RetryObserver returns a callback counting HTTP retries to service
+func RetryObserver(service string) func(req *http.Request, statusCode int) {
+	return func(_ *http.Request, statusCode int) {
+		clientRetriesTotal.WithLabelValues(service, strconv.Itoa(statusCode)).Inc()
+	}
+}
+
+// RateLimitWaitObserver returns a callback recording how long requests to service
+// waited for the rate limiter
+func RateLimitWaitObserver(service string) func(req *http.Request, wait time.Duration) {
+	return func(_ *http.Request, wait time.Duration) {
+		clientRateLimitWait.WithLabelValues(service).Observe(wait.Seconds())
+	}
+}
+
+// observeRepositoryOperation records the latency of a repository call
+func observeRepositoryOperation(operation string, start time.Time, err error) {
+	repositoryOperationDuration.WithLabelValues(operation, result(err)).Observe(time.Since(start).Seconds())
+}
+
+// observeClientCall records a call to an external service
+func observeClientCall(service, method string, start time.Time, err error) {
+	clientRequestsTotal.WithLabelValues(service, method).Inc()
+	clientRequestDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		clientErrorsTotal.WithLabelValues(service, method).Inc()
+	}
+}
+
+// result converts an error into a low-cardinality label value
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CartCounter is implemented by repositories that can report how many carts they hold
+type CartCounter interface {
+	Count() int
+}
+
+// RegisterCartCount exposes the number of carts held by counter as a gauge
+func RegisterCartCount(counter CartCounter) error {
+	return prometheus.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "carts",
+		Help:      "Number of carts stored in the repository.",
+	}, func() float64 {
+		return float64(counter.Count())
+	}))
+}
+
+// cartRepository decorates ports.CartRepository with latency metrics
+type cartRepository struct {
+	next ports.CartRepository
+}
+
+// NewCartRepository wraps repo so that every operation is measured
+func NewCartRepository(repo ports.CartRepository) ports.CartRepository {
+	return &cartRepository{
+		next: repo,
+	}
+}
+
+// GetCart implements ports.CartRepository
+func (r *cartRepository) GetCart(ctx context.Context, userID int64) (cart *models.Cart, err error) {
+	defer func(start time.Time) { observeRepositoryOperation("get_cart", start, err) }(time.Now())
+	return r.next.GetCart(ctx, userID)
+}
+
+// SaveCart implements ports.CartRepository
+func (r *cartRepository) SaveCart(ctx context.Context, cart *models.Cart) (err error) {
+	defer func(start time.Time) { observeRepositoryOperation("save_cart", start, err) }(time.Now())
+	return r.next.SaveCart(ctx, cart)
+}
+
+// CreateCart implements ports.CartRepository
+func (r *cartRepository) CreateCart(ctx context.Context, cart *models.Cart) (err error) {
+	defer func(start time.Time) { observeRepositoryOperation("create_cart", start, err) }(time.Now())
+	return r.next.CreateCart(ctx, cart)
+}
+
+// UpdateCart implements ports.CartRepository
+func (r *cartRepository) UpdateCart(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error) {
+	defer func(start time.Time) { observeRepositoryOperation("update_cart", start, err) }(time.Now())
+	return r.next.UpdateCart(ctx, userID, update)
+}
+
+// RefreshCart implements ports.CartRepository
+func (r *cartRepository) RefreshCart(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error) {
+	defer func(start time.Time) { observeRepositoryOperation("refresh_cart", start, err) }(time.Now())
+	return r.next.RefreshCart(ctx, userID, update)
+}
+
+// DeleteExpired implements ports.CartRepository
+func (r *cartRepository) DeleteExpired(ctx context.Context, cutoff time.Time, limit int) (deleted int, err error) {
+	defer func(start time.Time) { observeRepositoryOperation("delete_expired", start, err) }(time.Now())
+	deleted, err = r.next.DeleteExpired(ctx, cutoff, limit)
+	repositoryExpiredCarts.Add(float64(deleted))
+	return deleted, err
+}
+
+// ListIdle implements ports.CartRepository
+func (r *cartRepository) ListIdle(ctx context.Context, cutoff time.Time, after int64, limit int) (carts []*models.Cart, err error) {
+	defer func(start time.Time) { observeRepositoryOperation("list_idle", start, err) }(time.Now())
+	return r.next.ListIdle(ctx, cutoff, after, limit)
+}
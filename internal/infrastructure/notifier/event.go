@@ -0,0 +1,86 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"route256/cart/internal/domain/models"
+)
+
+// eventType identifies abandoned cart events to their consumers
+const eventType = "cart.abandoned"
+
+// event is the JSON representation of models.AbandonedCart
+type event struct {
+	// ID is the same for every delivery of an event, so consumers can
+	// drop duplicates
+	ID   string `json:"id"`
+	Type string `json:"type"`
+
+	UserID           int64     `json:"user_id"`
+	Stage            int       `json:"stage"`
+	ThresholdSeconds int64     `json:"threshold_seconds"`
+	UpdatedAt        time.Time `json:"updated_at"`
+	DetectedAt       time.Time `json:"detected_at"`
+
+	Items      []item `json:"items"`
+	TotalPrice money  `json:"total_price"`
+	Coupon     string `json:"coupon,omitempty"`
+}
+
+// item is the JSON representation of a cart item
+type item struct {
+	SKU      uint32 `json:"sku"`
+	Quantity uint16 `json:"quantity"`
+	Price    money  `json:"price"`
+}
+
+// money is the JSON representation of an amount in minor currency units
+type money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// newEvent converts an abandoned cart event to its JSON representation
+func newEvent(e models.AbandonedCart) event {
+	cart := e.Cart
+
+	items := make([]item, len(cart.Items))
+	for i, it := range cart.Items {
+		items[i] = item{
+			SKU:      it.SKU,
+			Quantity: it.Quantity,
+			Price:    toMoney(it.Price),
+		}
+	}
+
+	return event{
+		ID:               fmt.Sprintf("%d-%d-%d", cart.UserID, cart.UpdatedAt.UnixNano(), e.Stage),
+		Type:             eventType,
+		UserID:           cart.UserID,
+		Stage:            e.Stage,
+		ThresholdSeconds: int64(e.Threshold / time.Second),
+		UpdatedAt:        cart.UpdatedAt,
+		DetectedAt:       e.DetectedAt,
+		Items:            items,
+		TotalPrice:       toMoney(cart.TotalPrice),
+		Coupon:           cart.Coupon,
+	}
+}
+
+// marshal encodes the event as JSON
+func (e event) marshal() ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal abandoned cart event: %w", err)
+	}
+	return data, nil
+}
+
+func toMoney(m models.Money) money {
+	return money{
+		Amount:   m.Amount,
+		Currency: m.Currency,
+	}
+}
@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"route256/cart/internal/domain/models"
+)
+
+// File implements ports.AbandonedCartNotifier by appending events to a file,
+// one JSON object per line
+type File struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFile creates a notifier appending abandoned cart events to the file at path
+func NewFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open abandoned cart events file: %w", err)
+	}
+
+	return &File{file: f}, nil
+}
+
+// NotifyAbandonedCart implements ports.AbandonedCartNotifier
+func (n *File) NotifyAbandonedCart(_ context.Context, e models.AbandonedCart) error {
+	data, err := newEvent(e).marshal()
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.file == nil {
+		return os.ErrClosed
+	}
+	if _, err := n.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write abandoned cart event: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying file
+func (n *File) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.file == nil {
+		return nil
+	}
+
+	err := n.file.Close()
+	n.file = nil
+	return err
+}
@@ -0,0 +1,30 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_NotifyAbandonedCart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+
+	file, err := NewFile(path)
+	require.NoError(t, err)
+	require.NoError(t, file.NotifyAbandonedCart(context.Background(), testEvent()))
+	require.NoError(t, file.NotifyAbandonedCart(context.Background(), testEvent()))
+	require.NoError(t, file.Close())
+
+	assert.ErrorIs(t, file.NotifyAbandonedCart(context.Background(), testEvent()), os.ErrClosed)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), `"type":"cart.abandoned"`)
+}
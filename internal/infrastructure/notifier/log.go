@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"route256/cart/internal/domain/models"
+)
+
+// Log implements ports.AbandonedCartNotifier by writing events to the
+// service log, which is handy for local testing
+type Log struct{}
+
+// NewLog creates a notifier logging abandoned cart events
+func NewLog() *Log {
+	return &Log{}
+}
+
+// NotifyAbandonedCart implements ports.AbandonedCartNotifier
+func (n *Log) NotifyAbandonedCart(_ context.Context, e models.AbandonedCart) error {
+	data, err := newEvent(e).marshal()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Abandoned cart: %s", data)
+	return nil
+}
@@ -0,0 +1,202 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"route256/cart/internal/domain/models"
+)
+
+// Notices implements ports.AbandonedCartLog in memory, optionally backed by
+// a file so that notices survive restarts
+type Notices struct {
+	mu      sync.RWMutex
+	notices map[int64]models.AbandonedCartNotice
+	path    string
+	file    *os.File
+}
+
+// NewNotices creates a log of abandoned cart notices. If path is not empty,
+// notices are appended to the file at path, one JSON object per line, and
+// read back from it; the file is compacted to the last notice of every user
+// on startup and whenever notices are forgotten.
+func NewNotices(path string) (*Notices, error) {
+	n := &Notices{
+		notices: make(map[int64]models.AbandonedCartNotice),
+		path:    path,
+	}
+	if path == "" {
+		return n, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create notices dir: %w", err)
+	}
+	if err := n.load(path); err != nil {
+		return nil, err
+	}
+	if err := n.compact(path); err != nil {
+		return nil, err
+	}
+
+	f, err := n.open()
+	if err != nil {
+		return nil, err
+	}
+	n.file = f
+
+	return n, nil
+}
+
+// LastNotice implements ports.AbandonedCartLog
+func (n *Notices) LastNotice(_ context.Context, userID int64) (models.AbandonedCartNotice, bool, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	notice, ok := n.notices[userID]
+	return notice, ok, nil
+}
+
+// Record implements ports.AbandonedCartLog
+func (n *Notices) Record(_ context.Context, notice models.AbandonedCartNotice) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.file != nil {
+		data, err := json.Marshal(notice)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notice: %w", err)
+		}
+		if _, err := n.file.Write(append(data, '\n')); err != nil {
+			return fmt.Errorf("failed to write notice: %w", err)
+		}
+	}
+
+	n.notices[notice.UserID] = notice
+	return nil
+}
+
+// Retain implements ports.AbandonedCartLog. The file, if any, is compacted
+// when notices are forgotten.
+func (n *Notices) Retain(_ context.Context, userIDs map[int64]struct{}) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	forgotten := false
+	for userID := range n.notices {
+		if _, ok := userIDs[userID]; !ok {
+			delete(n.notices, userID)
+			forgotten = true
+		}
+	}
+	if !forgotten || n.file == nil {
+		return nil
+	}
+
+	if err := n.compact(n.path); err != nil {
+		return err
+	}
+
+	// The compacted file replaced the one open for appending
+	f, err := n.open()
+	if err != nil {
+		return err
+	}
+	err = n.file.Close()
+	n.file = f
+	if err != nil {
+		return fmt.Errorf("failed to close notices file: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying file, if any
+func (n *Notices) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.file == nil {
+		return nil
+	}
+
+	err := n.file.Close()
+	n.file = nil
+	return err
+}
+
+// open opens the notices file for appending
+func (n *Notices) open() (*os.File, error) {
+	f, err := os.OpenFile(n.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notices file: %w", err)
+	}
+	return f, nil
+}
+
+// load reads the notices stored at path, if the file exists. Reading stops
+// at the first malformed line, which is what a crash mid-write leaves behind.
+func (n *Notices) load(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open notices file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var notice models.AbandonedCartNotice
+		if err := json.Unmarshal(scanner.Bytes(), &notice); err != nil {
+			break
+		}
+		n.notices[notice.UserID] = notice
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read notices file: %w", err)
+	}
+
+	return nil
+}
+
+// compact atomically replaces the file at path with the loaded notices
+func (n *Notices) compact(path string) error {
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create notices file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, notice := range n.notices {
+		if err := enc.Encode(notice); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write notices file: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write notices file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync notices file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close notices file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to install notices file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package notifier
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+)
+
+func TestNotices_Record(t *testing.T) {
+	notices, err := NewNotices("")
+	require.NoError(t, err)
+
+	_, found, err := notices.LastNotice(context.Background(), 1)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	notice := models.AbandonedCartNotice{UserID: 1, CartUpdatedAt: time.Now(), Stage: 1, SentAt: time.Now()}
+	require.NoError(t, notices.Record(context.Background(), notice))
+
+	got, found, err := notices.LastNotice(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, notice, got)
+}
+
+func TestNotices_Restore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notices.jsonl")
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	notices, err := NewNotices(path)
+	require.NoError(t, err)
+	for stage := 1; stage <= 3; stage++ {
+		require.NoError(t, notices.Record(context.Background(), models.AbandonedCartNotice{
+			UserID:        1,
+			CartUpdatedAt: updatedAt,
+			Stage:         stage,
+			SentAt:        updatedAt.Add(time.Duration(stage) * time.Hour),
+		}))
+	}
+	require.NoError(t, notices.Record(context.Background(), models.AbandonedCartNotice{UserID: 2, Stage: 1}))
+	require.NoError(t, notices.Close())
+
+	// Simulate a crash in the middle of appending the next notice
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"UserID":3,"Sta`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewNotices(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	got, found, err := reopened.LastNotice(context.Background(), 1)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, 3, got.Stage)
+	assert.True(t, updatedAt.Equal(got.CartUpdatedAt))
+
+	_, found, err = reopened.LastNotice(context.Background(), 2)
+	require.NoError(t, err)
+	assert.True(t, found)
+	_, found, err = reopened.LastNotice(context.Background(), 3)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// The file is compacted to the last notice of every user
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+}
+
+func TestNotices_Retain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "notices.jsonl")
+
+	notices, err := NewNotices(path)
+	require.NoError(t, err)
+	for userID := int64(1); userID <= 3; userID++ {
+		require.NoError(t, notices.Record(context.Background(), models.AbandonedCartNotice{UserID: userID, Stage: 1}))
+	}
+
+	require.NoError(t, notices.Retain(context.Background(), map[int64]struct{}{2: {}}))
+	_, found, err := notices.LastNotice(context.Background(), 1)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// Notices recorded after the compaction go to the new file
+	require.NoError(t, notices.Record(context.Background(), models.AbandonedCartNotice{UserID: 4, Stage: 1}))
+	require.NoError(t, notices.Close())
+
+	reopened, err := NewNotices(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	for userID, want := range map[int64]bool{1: false, 2: true, 3: false, 4: true} {
+		_, found, err := reopened.LastNotice(context.Background(), userID)
+		require.NoError(t, err)
+		assert.Equal(t, want, found, "user %d", userID)
+	}
+}
@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"route256/cart/internal/domain/models"
+)
+
+// Webhook implements ports.AbandonedCartNotifier by posting events as JSON
+// to an HTTP endpoint
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates a notifier posting abandoned cart events to url
+func NewWebhook(url string, client *http.Client) *Webhook {
+	return &Webhook{
+		url:    url,
+		client: client,
+	}
+}
+
+// NotifyAbandonedCart implements ports.AbandonedCartNotifier. The event ID is
+// also sent as the Idempotency-Key header; any 2xx response means delivered.
+func (n *Webhook) NotifyAbandonedCart(ctx context.Context, e models.AbandonedCart) error {
+	ev := newEvent(e)
+	data, err := ev.marshal()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", ev.ID)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.CopyN(io.Discard, resp.Body, 4<<10)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+)
+
+// testEvent returns an abandoned cart event about the cart of user 1
+func testEvent() models.AbandonedCart {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	return models.AbandonedCart{
+		Cart: &models.Cart{
+			UserID:     1,
+			Items:      models.ItemList{{SKU: 123, Quantity: 2, Price: models.NewMoney(1000, "RUB")}},
+			TotalPrice: models.NewMoney(2000, "RUB"),
+			UpdatedAt:  updatedAt,
+		},
+		Stage:      1,
+		Threshold:  time.Hour,
+		DetectedAt: updatedAt.Add(90 * time.Minute),
+	}
+}
+
+func TestWebhook_NotifyAbandonedCart(t *testing.T) {
+	var (
+		got     map[string]any
+		headers http.Header
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, server.Client())
+	require.NoError(t, webhook.NotifyAbandonedCart(context.Background(), testEvent()))
+
+	assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	assert.Equal(t, got["id"], headers.Get("Idempotency-Key"))
+	assert.Equal(t, map[string]any{
+		"id":                "1-1714564800000000000-1",
+		"type":              "cart.abandoned",
+		"user_id":           float64(1),
+		"stage":             float64(1),
+		"threshold_seconds": float64(3600),
+		"updated_at":        "2024-05-01T12:00:00Z",
+		"detected_at":       "2024-05-01T13:30:00Z",
+		"items": []any{map[string]any{
+			"sku":      float64(123),
+			"quantity": float64(2),
+			"price":    map[string]any{"amount": float64(1000), "currency": "RUB"},
+		}},
+		"total_price": map[string]any{"amount": float64(2000), "currency": "RUB"},
+	}, got)
+}
+
+func TestWebhook_NotifyAbandonedCartFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	webhook := NewWebhook(server.URL, server.Client())
+	assert.Error(t, webhook.NotifyAbandonedCart(context.Background(), testEvent()))
+}
@@ -0,0 +1,116 @@
+package promotions
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/usecase/promotion"
+)
+
+// Rule types of a promotion file
+const (
+	typePercentOff = "percent_off"
+	typeBuyXGetY   = "buy_x_get_y"
+	typeAmountOff  = "amount_off"
+)
+
+// promotionFile is the layout of a promotion file
+type promotionFile struct {
+	Promotions []promotionEntry `yaml:"promotions"`
+}
+
+// promotionEntry describes one promotion; which rule fields are used depends on Type
+type promotionEntry struct {
+	ID          string `yaml:"id"`
+	Description string `yaml:"description"`
+	Coupon      string `yaml:"coupon"`
+	Type        string `yaml:"type"`
+
+	// percent_off
+	Percent int64    `yaml:"percent"`
+	SKUs    []uint32 `yaml:"skus"`
+
+	// buy_x_get_y
+	SKU  uint32 `yaml:"sku"`
+	Buy  uint16 `yaml:"buy"`
+	Free uint16 `yaml:"free"`
+
+	// amount_off
+	Threshold moneyEntry `yaml:"threshold"`
+	Amount    moneyEntry `yaml:"amount"`
+}
+
+// moneyEntry is an amount in minor units; the currency defaults to models.DefaultCurrency
+type moneyEntry struct {
+	Amount   int64  `yaml:"amount"`
+	Currency string `yaml:"currency"`
+}
+
+func (m moneyEntry) money() models.Money {
+	if m.Currency == "" {
+		return models.NewMoney(m.Amount, models.DefaultCurrency)
+	}
+	return models.NewMoney(m.Amount, m.Currency)
+}
+
+// LoadFile creates a promotion engine from a YAML file such as:
+//
+//	promotions:
+//	  - id: tea-3-for-2
+//	    description: Third pack of tea for free
+//	    type: buy_x_get_y
+//	    sku: 1148162
+//	    buy: 2
+//	    free: 1
+//	  - id: welcome
+//	    description: 10% off your first order
+//	    coupon: WELCOME10
+//	    type: percent_off
+//	    percent: 10
+//
+// Promotions apply in the order they are listed.
+func LoadFile(path string) (*promotion.Engine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read promotions: %w", err)
+	}
+
+	var file promotionFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse promotions: %w", err)
+	}
+
+	promotions := make([]promotion.Promotion, 0, len(file.Promotions))
+	for _, entry := range file.Promotions {
+		rule, err := entry.rule()
+		if err != nil {
+			return nil, fmt.Errorf("invalid promotion %q: %w", entry.ID, err)
+		}
+
+		promotions = append(promotions, promotion.Promotion{
+			ID:          entry.ID,
+			Description: entry.Description,
+			Coupon:      entry.Coupon,
+			Rule:        rule,
+		})
+	}
+
+	return promotion.NewEngine(promotions)
+}
+
+// rule creates the rule of the promotion
+func (e promotionEntry) rule() (promotion.Rule, error) {
+	switch e.Type {
+	case typePercentOff:
+		return promotion.NewPercentOff(e.Percent, e.SKUs...)
+	case typeBuyXGetY:
+		return promotion.NewBuyXGetY(e.SKU, e.Buy, e.Free)
+	case typeAmountOff:
+		return promotion.NewAmountOff(e.Threshold.money(), e.Amount.money())
+	default:
+		return nil, fmt.Errorf("unknown type %q", e.Type)
+	}
+}
@@ -0,0 +1,71 @@
+package promotions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+)
+
+// writePromotions writes a promotion file and returns its path
+func writePromotions(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "promotions.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	engine, err := LoadFile(writePromotions(t, `
+promotions:
+  - id: tea
+    type: buy_x_get_y
+    sku: 1
+    buy: 1
+    free: 1
+  - id: big-order
+    type: amount_off
+    threshold: {amount: 1000}
+    amount: {amount: 100, currency: RUB}
+  - id: welcome
+    coupon: welcome10
+    type: percent_off
+    percent: 10
+`))
+	require.NoError(t, err)
+	assert.True(t, engine.IsCoupon("WELCOME10"))
+
+	cart := &models.Cart{Coupon: "WELCOME10", Items: models.ItemList{
+		{SKU: 1, Quantity: 2, Price: models.NewMoney(1000, models.DefaultCurrency)},
+	}}
+	require.NoError(t, cart.CalculateTotalPrice(nil))
+	require.NoError(t, engine.Apply(cart, nil))
+
+	// 1000 off for the free item, 100 off over 1000, then 10% of 900
+	assert.Len(t, cart.Promotions, 3)
+	assert.Equal(t, models.NewMoney(810, models.DefaultCurrency), cart.DiscountedTotal)
+}
+
+func TestLoadFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown type", content: "promotions:\n  - id: p\n    type: free_lunch\n"},
+		{name: "invalid percent", content: "promotions:\n  - id: p\n    type: percent_off\n    percent: 120\n"},
+		{name: "missing id", content: "promotions:\n  - type: percent_off\n    percent: 10\n"},
+		{name: "malformed", content: "promotions: {"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadFile(writePromotions(t, tt.content))
+			assert.Error(t, err)
+		})
+	}
+}
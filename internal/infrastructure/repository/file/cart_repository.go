@@ -0,0 +1,403 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"route256/cart/internal/domain/models"
+)
+
+const (
+	logFileName      = "carts.wal"
+	snapshotFileName = "carts.snapshot"
+
+	// DefaultSnapshotEvery is the number of log records after which the log is compacted
+	DefaultSnapshotEvery = 1000
+)
+
+// logFile is the part of *os.File the log is written through
+type logFile interface {
+	io.Writer
+	Stat() (os.FileInfo, error)
+	Sync() error
+	Truncate(size int64) error
+	Close() error
+}
+
+// CartRepository implements domain.CartRepository interface
+// using an in-memory index backed by a write-ahead log and periodic snapshots on disk
+type CartRepository struct {
+	mu    sync.RWMutex
+	carts map[int64]*models.Cart
+
+	log           logFile
+	logPath       string
+	snapshotPath  string
+	snapshotEvery int
+	pending       int
+
+	// broken is set once a failed append could not be undone; the log may
+	// end in a torn record then, so all further writes are refused
+	broken error
+}
+
+// NewCartRepository opens a file-backed cart repository in dir.
+// The latest snapshot and the log written after it are replayed to restore carts.
+func NewCartRepository(dir string, snapshotEvery int) (*CartRepository, error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotEvery
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create data dir: %w", err)
+	}
+
+	r := &CartRepository{
+		carts:         make(map[int64]*models.Cart),
+		logPath:       filepath.Join(dir, logFileName),
+		snapshotPath:  filepath.Join(dir, snapshotFileName),
+		snapshotEvery: snapshotEvery,
+	}
+
+	if err := loadSnapshot(r.snapshotPath, r.carts); err != nil {
+		return nil, err
+	}
+
+	replayed, err := replayLog(r.logPath, r.carts)
+	if err != nil {
+		return nil, err
+	}
+	r.pending = replayed
+
+	// Carts stored before they had a timestamp expire a full TTL from now
+	now := time.Now()
+	for _, cart := range r.carts {
+		if cart.UpdatedAt.IsZero() {
+			cart.UpdatedAt = now
+		}
+	}
+
+	logFile, err := os.OpenFile(r.logPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log: %w", err)
+	}
+	r.log = logFile
+
+	return r, nil
+}
+
+// CreateCart implements domain.CartRepository
+func (r *CartRepository) CreateCart(_ context.Context, cart *models.Cart) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.carts[cart.UserID]; exists {
+		return models.ErrCartAlreadyExists
+	}
+
+	stored := cart.Clone()
+	stored.Version = 1
+	stored.UpdatedAt = time.Now()
+	if err := r.append(record{Op: opSave, UserID: stored.UserID, Cart: stored}); err != nil {
+		return err
+	}
+
+	r.carts[stored.UserID] = stored
+	cart.Version = stored.Version
+	cart.UpdatedAt = stored.UpdatedAt
+	r.maybeCompact()
+	return nil
+}
+
+// GetCart implements domain.CartRepository
+func (r *CartRepository) GetCart(_ context.Context, userID int64) (*models.Cart, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cart, exists := r.carts[userID]
+	if !exists {
+		return nil, models.ErrCartNotFound
+	}
+
+	return cart.Clone(), nil
+}
+
+// SaveCart implements domain.CartRepository
+func (r *CartRepository) SaveCart(_ context.Context, cart *models.Cart) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkVersion(cart); err != nil {
+		return err
+	}
+
+	stored := cart.Clone()
+	stored.Version++
+	stored.UpdatedAt = time.Now()
+	if err := r.append(record{Op: opSave, UserID: stored.UserID, Cart: stored}); err != nil {
+		return err
+	}
+
+	r.carts[stored.UserID] = stored
+	cart.Version = stored.Version
+	cart.UpdatedAt = stored.UpdatedAt
+	r.maybeCompact()
+	return nil
+}
+
+// UpdateCart implements domain.CartRepository
+func (r *CartRepository) UpdateCart(_ context.Context, userID int64, update func(cart *models.Cart) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cart, exists := r.carts[userID]
+	if exists {
+		cart = cart.Clone()
+	} else {
+		cart = models.NewCart(userID)
+	}
+
+	if err := update(cart); err != nil {
+		return err
+	}
+
+	cart.Version++
+	cart.UpdatedAt = time.Now()
+	if err := r.append(record{Op: opSave, UserID: userID, Cart: cart}); err != nil {
+		return err
+	}
+
+	r.carts[userID] = cart
+	r.maybeCompact()
+	return nil
+}
+
+// RefreshCart implements domain.CartRepository
+func (r *CartRepository) RefreshCart(_ context.Context, userID int64, update func(cart *models.Cart) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cart, exists := r.carts[userID]
+	if !exists {
+		return models.ErrCartNotFound
+	}
+
+	cart = cart.Clone()
+	if err := update(cart); err != nil {
+		return err
+	}
+
+	cart.Version++
+	if err := r.append(record{Op: opSave, UserID: userID, Cart: cart}); err != nil {
+		return err
+	}
+
+	r.carts[userID] = cart
+	r.maybeCompact()
+	return nil
+}
+
+// checkVersion rejects saving a cart based on a stale version.
+// The caller must hold r.mu.
+func (r *CartRepository) checkVersion(cart *models.Cart) error {
+	var current uint64
+	if stored, exists := r.carts[cart.UserID]; exists {
+		current = stored.Version
+	}
+
+	if cart.Version != current {
+		return &models.VersionConflictError{
+			UserID:   cart.UserID,
+			Expected: cart.Version,
+			Actual:   current,
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of stored carts
+func (r *CartRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.carts)
+}
+
+// DeleteCart implements domain.CartRepository
+func (r *CartRepository) DeleteCart(_ context.Context, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.carts[userID]; !exists {
+		return models.ErrCartNotFound
+	}
+
+	if err := r.append(record{Op: opDelete, UserID: userID}); err != nil {
+		return err
+	}
+
+	delete(r.carts, userID)
+	r.maybeCompact()
+	return nil
+}
+
+// DeleteExpired implements domain.CartRepository
+func (r *CartRepository) DeleteExpired(_ context.Context, cutoff time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		expired []int64
+		recs    []record
+	)
+	for userID, cart := range r.carts {
+		if len(expired) == limit {
+			break
+		}
+		if cart.UpdatedAt.Before(cutoff) {
+			expired = append(expired, userID)
+			recs = append(recs, record{Op: opDelete, UserID: userID})
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	if err := r.append(recs...); err != nil {
+		return 0, err
+	}
+
+	for _, userID := range expired {
+		delete(r.carts, userID)
+	}
+	r.maybeCompact()
+	return len(expired), nil
+}
+
+// ListIdle implements domain.CartRepository
+func (r *CartRepository) ListIdle(_ context.Context, cutoff time.Time, after int64, limit int) ([]*models.Cart, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var idle []int64
+	for userID, cart := range r.carts {
+		if userID > after && cart.UpdatedAt.Before(cutoff) {
+			idle = append(idle, userID)
+		}
+	}
+	slices.Sort(idle)
+	idle = idle[:min(len(idle), limit)]
+
+	carts := make([]*models.Cart, len(idle))
+	for i, userID := range idle {
+		carts[i] = r.carts[userID].Clone()
+	}
+	return carts, nil
+}
+
+// Close flushes the log and releases the underlying file
+func (r *CartRepository) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.log == nil {
+		return nil
+	}
+
+	if err := r.log.Sync(); err != nil {
+		return fmt.Errorf("failed to sync log: %w", err)
+	}
+
+	err := r.log.Close()
+	r.log = nil
+	return err
+}
+
+// append durably writes recs to the log with a single sync.
+// The caller must hold r.mu; records are committed once append returns nil.
+// A failed append is cut off the log, so that neither it nor a torn record
+// hides the records appended after it on replay.
+func (r *CartRepository) append(recs ...record) error {
+	if r.log == nil {
+		return os.ErrClosed
+	}
+	if r.broken != nil {
+		return r.broken
+	}
+
+	info, err := r.log.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat log: %w", err)
+	}
+
+	for _, rec := range recs {
+		if err := writeRecord(r.log, rec); err != nil {
+			return r.rollback(info.Size(), fmt.Errorf("failed to append log record: %w", err))
+		}
+	}
+
+	if err := r.log.Sync(); err != nil {
+		return r.rollback(info.Size(), fmt.Errorf("failed to sync log: %w", err))
+	}
+
+	r.pending += len(recs)
+	return nil
+}
+
+// rollback truncates the log back to size after a failed append and returns
+// err. The repository refuses further writes if the log cannot be restored.
+// The caller must hold r.mu.
+func (r *CartRepository) rollback(size int64, err error) error {
+	restoreErr := r.log.Truncate(size)
+	if restoreErr == nil {
+		restoreErr = r.log.Sync()
+	}
+	if restoreErr != nil {
+		r.broken = fmt.Errorf("cart log is broken: %w", errors.Join(err, restoreErr))
+		return r.broken
+	}
+
+	return err
+}
+
+// maybeCompact compacts the log once enough records have accumulated.
+// Records are already durable at this point, so a failed compaction
+// only postpones it until the next write.
+func (r *CartRepository) maybeCompact() {
+	if r.pending < r.snapshotEvery {
+		return
+	}
+
+	if err := r.compact(); err != nil {
+		log.Printf("Failed to compact cart log: %v", err)
+	}
+}
+
+// compact writes a snapshot of the current state and truncates the log.
+// Replaying the old log over the new snapshot yields the same state, so a
+// crash between the two steps is harmless.
+func (r *CartRepository) compact() error {
+	if err := writeSnapshot(r.snapshotPath, r.carts); err != nil {
+		return err
+	}
+
+	if err := r.log.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate log: %w", err)
+	}
+
+	if err := r.log.Sync(); err != nil {
+		return fmt.Errorf("failed to sync log: %w", err)
+	}
+
+	r.pending = 0
+	return nil
+}
@@ -0,0 +1,514 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+)
+
+// rub returns an amount in the default currency
+func rub(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
+func TestFileCartRepository_GetCart(t *testing.T) {
+	tests := []struct {
+		name    string
+		userID  int64
+		setup   func(repo *CartRepository)
+		want    *models.Cart
+		wantErr error
+	}{
+		{
+			name:   "cart exists",
+			userID: 1,
+			setup: func(repo *CartRepository) {
+				_ = repo.SaveCart(context.Background(), &models.Cart{
+					UserID: 1,
+					Items: models.ItemList{
+						{
+							SKU:      123,
+							Quantity: 2,
+							Price:    rub(1000),
+						},
+					},
+					TotalPrice: rub(2000),
+				})
+			},
+			want: &models.Cart{
+				UserID: 1,
+				Items: models.ItemList{
+					{
+						SKU:      123,
+						Quantity: 2,
+						Price:    rub(1000),
+					},
+				},
+				TotalPrice: rub(2000),
+				Version:    1,
+			},
+			wantErr: nil,
+		},
+		{
+			name:    "cart not found",
+			userID:  1,
+			setup:   func(repo *CartRepository) {},
+			want:    nil,
+			wantErr: models.ErrCartNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			tt.setup(repo)
+
+			got, err := repo.GetCart(context.Background(), tt.userID)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+			} else {
+				require.NoError(t, err)
+				assert.False(t, got.UpdatedAt.IsZero())
+				got.UpdatedAt = time.Time{}
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFileCartRepository_SaveCart(t *testing.T) {
+	tests := []struct {
+		name    string
+		cart    *models.Cart
+		setup   func(repo *CartRepository)
+		wantErr error
+	}{
+		{
+			name: "save new cart",
+			cart: &models.Cart{
+				UserID: 1,
+				Items: models.ItemList{
+					{
+						SKU:      123,
+						Quantity: 2,
+						Price:    rub(1000),
+					},
+				},
+				TotalPrice: rub(2000),
+			},
+			setup:   func(repo *CartRepository) {},
+			wantErr: nil,
+		},
+		{
+			name: "update existing cart",
+			cart: &models.Cart{
+				UserID: 1,
+				Items: models.ItemList{
+					{
+						SKU:      123,
+						Quantity: 3,
+						Price:    rub(1000),
+					},
+				},
+				TotalPrice: rub(3000),
+				Version:    1,
+			},
+			setup: func(repo *CartRepository) {
+				_ = repo.SaveCart(context.Background(), &models.Cart{
+					UserID: 1,
+					Items: models.ItemList{
+						{
+							SKU:      123,
+							Quantity: 2,
+							Price:    rub(1000),
+						},
+					},
+					TotalPrice: rub(2000),
+				})
+			},
+			wantErr: nil,
+		},
+		{
+			name: "stale version",
+			cart: &models.Cart{
+				UserID: 1,
+				Items: models.ItemList{
+					{
+						SKU:      123,
+						Quantity: 3,
+						Price:    rub(1000),
+					},
+				},
+				TotalPrice: rub(3000),
+				Version:    1,
+			},
+			setup: func(repo *CartRepository) {
+				_ = repo.SaveCart(context.Background(), &models.Cart{UserID: 1, Items: make(models.ItemList, 0)})
+				_ = repo.UpdateCart(context.Background(), 1, func(cart *models.Cart) error { return nil })
+			},
+			wantErr: models.ErrVersionConflict,
+		},
+		{
+			name: "deleted cart",
+			cart: &models.Cart{
+				UserID:  1,
+				Items:   make(models.ItemList, 0),
+				Version: 1,
+			},
+			setup:   func(repo *CartRepository) {},
+			wantErr: models.ErrVersionConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			tt.setup(repo)
+
+			err := repo.SaveCart(context.Background(), tt.cart)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				got, err := repo.GetCart(context.Background(), tt.cart.UserID)
+				require.NoError(t, err)
+				assert.Equal(t, tt.cart, got)
+			}
+		})
+	}
+}
+
+func TestFileCartRepository_CreateCart(t *testing.T) {
+	tests := []struct {
+		name    string
+		cart    *models.Cart
+		setup   func(repo *CartRepository)
+		wantErr error
+	}{
+		{
+			name: "create new cart",
+			cart: &models.Cart{
+				UserID:     1,
+				Items:      make(models.ItemList, 0),
+				TotalPrice: models.Money{},
+			},
+			setup:   func(repo *CartRepository) {},
+			wantErr: nil,
+		},
+		{
+			name: "cart already exists",
+			cart: &models.Cart{
+				UserID:     1,
+				Items:      make(models.ItemList, 0),
+				TotalPrice: models.Money{},
+			},
+			setup: func(repo *CartRepository) {
+				_ = repo.SaveCart(context.Background(), &models.Cart{
+					UserID:     1,
+					Items:      make(models.ItemList, 0),
+					TotalPrice: models.Money{},
+				})
+			},
+			wantErr: models.ErrCartAlreadyExists,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newTestRepository(t)
+			tt.setup(repo)
+
+			err := repo.CreateCart(context.Background(), tt.cart)
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				got, err := repo.GetCart(context.Background(), tt.cart.UserID)
+				require.NoError(t, err)
+				assert.Equal(t, tt.cart, got)
+			}
+		})
+	}
+}
+
+func newTestRepository(t *testing.T) *CartRepository {
+	t.Helper()
+
+	repo, err := NewCartRepository(t.TempDir(), DefaultSnapshotEvery)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = repo.Close() })
+
+	return repo
+}
+
+func TestFileCartRepository_Restore(t *testing.T) {
+	tests := []struct {
+		name          string
+		snapshotEvery int
+	}{
+		{
+			name:          "replay from log",
+			snapshotEvery: DefaultSnapshotEvery,
+		},
+		{
+			name:          "replay from snapshot and log",
+			snapshotEvery: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			repo, err := NewCartRepository(dir, tt.snapshotEvery)
+			require.NoError(t, err)
+
+			require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(1)))
+			require.NoError(t, repo.SaveCart(context.Background(), &models.Cart{
+				UserID:     1,
+				Items:      models.ItemList{{SKU: 123, Quantity: 2, Price: rub(1000)}},
+				TotalPrice: rub(2000),
+				Version:    1,
+			}))
+			require.NoError(t, repo.SaveCart(context.Background(), &models.Cart{
+				UserID:     2,
+				Items:      models.ItemList{{SKU: 456, Quantity: 1, Price: rub(500)}},
+				TotalPrice: rub(500),
+			}))
+			require.NoError(t, repo.DeleteCart(context.Background(), 2))
+			require.NoError(t, repo.Close())
+
+			reopened, err := NewCartRepository(dir, tt.snapshotEvery)
+			require.NoError(t, err)
+			defer reopened.Close()
+
+			got, err := reopened.GetCart(context.Background(), 1)
+			require.NoError(t, err)
+			assert.False(t, got.UpdatedAt.IsZero())
+			got.UpdatedAt = time.Time{}
+			assert.Equal(t, &models.Cart{
+				UserID:     1,
+				Items:      models.ItemList{{SKU: 123, Quantity: 2, Price: rub(1000)}},
+				TotalPrice: rub(2000),
+				Version:    2,
+			}, got)
+
+			_, err = reopened.GetCart(context.Background(), 2)
+			assert.ErrorIs(t, err, models.ErrCartNotFound)
+		})
+	}
+}
+
+func TestFileCartRepository_TornWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := NewCartRepository(dir, DefaultSnapshotEvery)
+	require.NoError(t, err)
+
+	committed := &models.Cart{
+		UserID:     1,
+		Items:      models.ItemList{{SKU: 123, Quantity: 2, Price: rub(1000)}},
+		TotalPrice: rub(2000),
+	}
+	require.NoError(t, repo.SaveCart(context.Background(), committed))
+	require.NoError(t, repo.Close())
+
+	// Simulate a crash in the middle of appending the next record
+	logPath := filepath.Join(dir, logFileName)
+	f, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, writeRecord(&buf, record{Op: opSave, UserID: 2, Cart: models.NewCart(2)}))
+	_, err = f.Write(buf.Bytes()[:buf.Len()/2])
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := NewCartRepository(dir, DefaultSnapshotEvery)
+	require.NoError(t, err)
+
+	got, err := reopened.GetCart(context.Background(), 1)
+	require.NoError(t, err)
+	assert.True(t, committed.UpdatedAt.Equal(got.UpdatedAt))
+	got.UpdatedAt = committed.UpdatedAt
+	assert.Equal(t, committed, got)
+
+	_, err = reopened.GetCart(context.Background(), 2)
+	assert.ErrorIs(t, err, models.ErrCartNotFound)
+
+	// Records written after recovery must not be hidden behind the torn tail
+	require.NoError(t, reopened.SaveCart(context.Background(), models.NewCart(3)))
+	require.NoError(t, reopened.Close())
+
+	again, err := NewCartRepository(dir, DefaultSnapshotEvery)
+	require.NoError(t, err)
+	defer again.Close()
+
+	_, err = again.GetCart(context.Background(), 3)
+	assert.NoError(t, err)
+}
+
+// failingLog writes only half of what it is given and fails, and fails
+// truncation with truncateErr
+type failingLog struct {
+	*os.File
+	truncateErr error
+}
+
+func (f *failingLog) Write(p []byte) (int, error) {
+	n, err := f.File.Write(p[:len(p)/2])
+	if err != nil {
+		return n, err
+	}
+	return n, errors.New("disk is full")
+}
+
+func (f *failingLog) Truncate(size int64) error {
+	if f.truncateErr != nil {
+		return f.truncateErr
+	}
+	return f.File.Truncate(size)
+}
+
+func TestFileCartRepository_FailedWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := NewCartRepository(dir, DefaultSnapshotEvery)
+	require.NoError(t, err)
+	require.NoError(t, repo.SaveCart(context.Background(), models.NewCart(1)))
+
+	file := repo.log.(*os.File)
+	repo.log = &failingLog{File: file}
+	assert.Error(t, repo.SaveCart(context.Background(), models.NewCart(2)))
+
+	// The torn record is cut off, so later records survive a restart
+	repo.log = file
+	require.NoError(t, repo.SaveCart(context.Background(), models.NewCart(3)))
+	require.NoError(t, repo.Close())
+
+	reopened, err := NewCartRepository(dir, DefaultSnapshotEvery)
+	require.NoError(t, err)
+
+	for userID, want := range map[int64]error{1: nil, 2: models.ErrCartNotFound, 3: nil} {
+		_, err := reopened.GetCart(context.Background(), userID)
+		assert.ErrorIs(t, err, want, "user %d", userID)
+	}
+
+	// A log that cannot be restored refuses further writes
+	file = reopened.log.(*os.File)
+	reopened.log = &failingLog{File: file, truncateErr: errors.New("read-only file system")}
+	assert.Error(t, reopened.SaveCart(context.Background(), models.NewCart(4)))
+
+	reopened.log = file
+	assert.Error(t, reopened.SaveCart(context.Background(), models.NewCart(5)))
+	require.NoError(t, reopened.Close())
+}
+
+func TestFileCartRepository_UpdateCartConcurrent(t *testing.T) {
+	const writers = 200
+
+	dir := t.TempDir()
+	repo, err := NewCartRepository(dir, 50)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.UpdateCart(context.Background(), 1, func(cart *models.Cart) error {
+				cart.AddItem(models.Item{SKU: 123, Quantity: 1, Price: rub(1000)})
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, repo.Close())
+
+	reopened, err := NewCartRepository(dir, 50)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	got, err := reopened.GetCart(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, uint16(writers), got.Items[0].Quantity)
+}
+
+func TestFileCartRepository_DeleteExpired(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := NewCartRepository(dir, DefaultSnapshotEvery)
+	require.NoError(t, err)
+	for userID := int64(1); userID <= 3; userID++ {
+		require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(userID)))
+	}
+
+	cutoff := time.Now().Add(-time.Hour)
+	repo.carts[1].UpdatedAt = cutoff.Add(-time.Minute)
+	repo.carts[2].UpdatedAt = cutoff.Add(-time.Second)
+
+	deleted, err := repo.DeleteExpired(context.Background(), cutoff, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	deleted, err = repo.DeleteExpired(context.Background(), cutoff, 10)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	require.NoError(t, repo.Close())
+
+	// Deletions survive a restart
+	reopened, err := NewCartRepository(dir, DefaultSnapshotEvery)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	for _, userID := range []int64{1, 2} {
+		_, err = reopened.GetCart(context.Background(), userID)
+		assert.ErrorIs(t, err, models.ErrCartNotFound)
+	}
+	_, err = reopened.GetCart(context.Background(), 3)
+	assert.NoError(t, err)
+}
+
+func TestFileCartRepository_ListIdle(t *testing.T) {
+	repo := newTestRepository(t)
+	for _, userID := range []int64{-1, 4, 2, 3, 1} {
+		require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(userID)))
+	}
+
+	cutoff := time.Now().Add(-time.Hour)
+	for _, userID := range []int64{-1, 1, 2, 4} {
+		repo.carts[userID].UpdatedAt = cutoff.Add(-time.Minute)
+	}
+
+	idle, err := repo.ListIdle(context.Background(), cutoff, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, idle, 2)
+	assert.Equal(t, int64(1), idle[0].UserID)
+	assert.Equal(t, int64(2), idle[1].UserID)
+
+	idle, err = repo.ListIdle(context.Background(), cutoff, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, idle, 1)
+	assert.Equal(t, int64(4), idle[0].UserID)
+
+	// Listed carts are copies
+	idle[0].Items = append(idle[0].Items, models.Item{SKU: 1})
+	got, err := repo.GetCart(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Empty(t, got.Items)
+}
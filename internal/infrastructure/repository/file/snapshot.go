@@ -0,0 +1,89 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"route256/cart/internal/domain/models"
+)
+
+// loadSnapshot reads the latest snapshot into carts, if one exists
+func loadSnapshot(path string, carts map[int64]*models.Cart) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot []*models.Cart
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+
+	for _, cart := range snapshot {
+		carts[cart.UserID] = cart
+	}
+
+	return nil
+}
+
+// writeSnapshot atomically replaces the snapshot at path with carts.
+// The data is written to a temporary file, synced and renamed over the old
+// snapshot, so a crash leaves either the old or the new snapshot intact.
+func writeSnapshot(path string, carts map[int64]*models.Cart) error {
+	snapshot := make([]*models.Cart, 0, len(carts))
+	for _, cart := range carts {
+		snapshot = append(snapshot, cart)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot: %w", err)
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync snapshot: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to install snapshot: %w", err)
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir flushes directory entries so that renames survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("failed to open data dir: %w", err)
+	}
+	defer d.Close()
+
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("failed to sync data dir: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package file
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+
+	"route256/cart/internal/domain/models"
+)
+
+// opType identifies the kind of mutation stored in a log record
+type opType uint8
+
+const (
+	opSave opType = iota + 1
+	opDelete
+)
+
+// recordHeaderSize is the size of the length and checksum prefix of a record
+const recordHeaderSize = 8
+
+// maxRecordSize guards replay against allocating garbage lengths from a torn header
+const maxRecordSize = 64 << 20
+
+var errCorruptRecord = errors.New("corrupt log record")
+
+// record is a single mutation persisted in the write-ahead log
+type record struct {
+	Op     opType       `json:"op"`
+	UserID int64        `json:"user_id"`
+	Cart   *models.Cart `json:"cart,omitempty"`
+}
+
+// writeRecord appends a framed record to w.
+// Frame layout: uint32 payload length, uint32 CRC32 of payload, payload.
+func writeRecord(w io.Writer, rec record) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log record: %w", err)
+	}
+
+	frame := make([]byte, recordHeaderSize+len(payload))
+	binary.LittleEndian.PutUint32(frame[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(payload))
+	copy(frame[recordHeaderSize:], payload)
+
+	_, err = w.Write(frame)
+	return err
+}
+
+// readRecord reads the next framed record from r.
+// It returns io.EOF on a clean end of log and errCorruptRecord on a torn or damaged tail.
+func readRecord(r *bufio.Reader) (record, int64, error) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return record{}, 0, io.EOF
+		}
+		return record{}, 0, errCorruptRecord
+	}
+
+	size := binary.LittleEndian.Uint32(header[0:4])
+	sum := binary.LittleEndian.Uint32(header[4:8])
+	if size == 0 || size > maxRecordSize {
+		return record{}, 0, errCorruptRecord
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return record{}, 0, errCorruptRecord
+	}
+
+	if crc32.ChecksumIEEE(payload) != sum {
+		return record{}, 0, errCorruptRecord
+	}
+
+	var rec record
+	if err := json.Unmarshal(payload, &rec); err != nil {
+		return record{}, 0, errCorruptRecord
+	}
+
+	return rec, int64(recordHeaderSize) + int64(size), nil
+}
+
+// replayLog applies every intact record in the log file to carts.
+// A torn tail left by a crash mid-write is truncated so that new records
+// are appended right after the last committed one.
+func replayLog(path string, carts map[int64]*models.Cart) (int, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open log: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	var offset int64
+	count := 0
+
+	for {
+		rec, n, err := readRecord(reader)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.Is(err, errCorruptRecord) {
+			if err := f.Truncate(offset); err != nil {
+				return 0, fmt.Errorf("failed to truncate torn log tail: %w", err)
+			}
+			if err := f.Sync(); err != nil {
+				return 0, fmt.Errorf("failed to sync log: %w", err)
+			}
+			break
+		}
+
+		apply(carts, rec)
+		offset += n
+		count++
+	}
+
+	return count, nil
+}
+
+// apply replays a single record onto carts
+func apply(carts map[int64]*models.Cart, rec record) {
+	switch rec.Op {
+	case opSave:
+		if rec.Cart != nil {
+			carts[rec.UserID] = rec.Cart
+		}
+	case opDelete:
+		delete(carts, rec.UserID)
+	}
+}
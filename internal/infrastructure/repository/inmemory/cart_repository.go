@@ -1,7 +1,10 @@
 package inmemory
 
 import (
+	"context"
+	"slices"
 	"sync"
+	"time"
 
 	"route256/cart/internal/domain/models"
 )
@@ -21,7 +24,7 @@ func NewCartRepository() *CartRepository {
 }
 
 // CreateCart implements domain.CartRepository
-func (r *CartRepository) CreateCart(cart *models.Cart) error {
+func (r *CartRepository) CreateCart(_ context.Context, cart *models.Cart) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -29,12 +32,14 @@ func (r *CartRepository) CreateCart(cart *models.Cart) error {
 		return models.ErrCartAlreadyExists
 	}
 
-	r.carts[cart.UserID] = cart
+	cart.Version = 1
+	cart.UpdatedAt = time.Now()
+	r.carts[cart.UserID] = cart.Clone()
 	return nil
 }
 
 // GetCart implements domain.CartRepository
-func (r *CartRepository) GetCart(userID int64) (*models.Cart, error) {
+func (r *CartRepository) GetCart(_ context.Context, userID int64) (*models.Cart, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -43,20 +48,95 @@ func (r *CartRepository) GetCart(userID int64) (*models.Cart, error) {
 		return nil, models.ErrCartNotFound
 	}
 
-	return cart, nil
+	return cart.Clone(), nil
 }
 
 // SaveCart implements domain.CartRepository
-func (r *CartRepository) SaveCart(cart *models.Cart) error {
+func (r *CartRepository) SaveCart(_ context.Context, cart *models.Cart) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.carts[cart.UserID] = cart
+	if err := r.checkVersion(cart); err != nil {
+		return err
+	}
+
+	cart.Version++
+	cart.UpdatedAt = time.Now()
+	r.carts[cart.UserID] = cart.Clone()
+	return nil
+}
+
+// UpdateCart implements domain.CartRepository
+func (r *CartRepository) UpdateCart(_ context.Context, userID int64, update func(cart *models.Cart) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cart, exists := r.carts[userID]
+	if exists {
+		cart = cart.Clone()
+	} else {
+		cart = models.NewCart(userID)
+	}
+
+	if err := update(cart); err != nil {
+		return err
+	}
+
+	cart.Version++
+	cart.UpdatedAt = time.Now()
+	r.carts[userID] = cart
 	return nil
 }
 
+// RefreshCart implements domain.CartRepository
+func (r *CartRepository) RefreshCart(_ context.Context, userID int64, update func(cart *models.Cart) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cart, exists := r.carts[userID]
+	if !exists {
+		return models.ErrCartNotFound
+	}
+
+	cart = cart.Clone()
+	if err := update(cart); err != nil {
+		return err
+	}
+
+	cart.Version++
+	r.carts[userID] = cart
+	return nil
+}
+
+// checkVersion rejects saving a cart based on a stale version.
+// The caller must hold r.mu.
+func (r *CartRepository) checkVersion(cart *models.Cart) error {
+	var current uint64
+	if stored, exists := r.carts[cart.UserID]; exists {
+		current = stored.Version
+	}
+
+	if cart.Version != current {
+		return &models.VersionConflictError{
+			UserID:   cart.UserID,
+			Expected: cart.Version,
+			Actual:   current,
+		}
+	}
+
+	return nil
+}
+
+// Count returns the number of stored carts
+func (r *CartRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.carts)
+}
+
 // DeleteCart implements domain.CartRepository
-func (r *CartRepository) DeleteCart(userID int64) error {
+func (r *CartRepository) DeleteCart(_ context.Context, userID int64) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -67,3 +147,43 @@ func (r *CartRepository) DeleteCart(userID int64) error {
 	delete(r.carts, userID)
 	return nil
 }
+
+// DeleteExpired implements domain.CartRepository
+func (r *CartRepository) DeleteExpired(_ context.Context, cutoff time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for userID, cart := range r.carts {
+		if deleted == limit {
+			break
+		}
+		if cart.UpdatedAt.Before(cutoff) {
+			delete(r.carts, userID)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// ListIdle implements domain.CartRepository
+func (r *CartRepository) ListIdle(_ context.Context, cutoff time.Time, after int64, limit int) ([]*models.Cart, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var idle []int64
+	for userID, cart := range r.carts {
+		if userID > after && cart.UpdatedAt.Before(cutoff) {
+			idle = append(idle, userID)
+		}
+	}
+	slices.Sort(idle)
+	idle = idle[:min(len(idle), limit)]
+
+	carts := make([]*models.Cart, len(idle))
+	for i, userID := range idle {
+		carts[i] = r.carts[userID].Clone()
+	}
+	return carts, nil
+}
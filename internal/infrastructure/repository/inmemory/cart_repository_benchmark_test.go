@@ -1,6 +1,7 @@
 package inmemory
 
 import (
+	"context"
 	"route256/cart/internal/domain/models"
 	"testing"
 )
@@ -10,9 +11,9 @@ func BenchmarkInMemoryCartRepository_AddItem(b *testing.B) {
 	cart := &models.Cart{
 		UserID:     1,
 		Items:      make(models.ItemList, 0),
-		TotalPrice: 0,
+		TotalPrice: models.Money{},
 	}
-	err := repo.CreateCart(cart)
+	err := repo.CreateCart(context.Background(), cart)
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -22,10 +23,10 @@ func BenchmarkInMemoryCartRepository_AddItem(b *testing.B) {
 		cart.Items = append(cart.Items, models.Item{
 			SKU:      uint32(i % (1 << 32)),
 			Quantity: 1,
-			Price:    1000,
+			Price:    rub(1000),
 		})
-		cart.TotalPrice += 1000
-		err := repo.SaveCart(cart)
+		cart.TotalPrice, _ = cart.TotalPrice.Add(rub(1000))
+		err := repo.SaveCart(context.Background(), cart)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -40,19 +41,19 @@ func BenchmarkInMemoryCartRepository_GetCart(b *testing.B) {
 			{
 				SKU:      123,
 				Quantity: 2,
-				Price:    1000,
+				Price:    rub(1000),
 			},
 		},
-		TotalPrice: 2000,
+		TotalPrice: rub(2000),
 	}
-	err := repo.SaveCart(cart)
+	err := repo.SaveCart(context.Background(), cart)
 	if err != nil {
 		b.Fatal(err)
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		_, err := repo.GetCart(1)
+		_, err := repo.GetCart(context.Background(), 1)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -67,12 +68,12 @@ func BenchmarkInMemoryCartRepository_RemoveItem(b *testing.B) {
 			{
 				SKU:      123,
 				Quantity: 2,
-				Price:    1000,
+				Price:    rub(1000),
 			},
 		},
-		TotalPrice: 2000,
+		TotalPrice: rub(2000),
 	}
-	err := repo.SaveCart(cart)
+	err := repo.SaveCart(context.Background(), cart)
 	if err != nil {
 		b.Fatal(err)
 	}
@@ -80,8 +81,8 @@ func BenchmarkInMemoryCartRepository_RemoveItem(b *testing.B) {
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		cart.Items = make(models.ItemList, 0)
-		cart.TotalPrice = 0
-		err := repo.SaveCart(cart)
+		cart.TotalPrice = models.Money{}
+		err := repo.SaveCart(context.Background(), cart)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -1,7 +1,10 @@
 package inmemory
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -9,6 +12,11 @@ import (
 	"route256/cart/internal/domain/models"
 )
 
+// rub returns an amount in the default currency
+func rub(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
 func TestInMemoryCartRepository_GetCart(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -21,16 +29,16 @@ func TestInMemoryCartRepository_GetCart(t *testing.T) {
 			name:   "cart exists",
 			userID: 1,
 			setup: func(repo *CartRepository) {
-				_ = repo.SaveCart(&models.Cart{
+				_ = repo.SaveCart(context.Background(), &models.Cart{
 					UserID: 1,
 					Items: models.ItemList{
 						{
 							SKU:      123,
 							Quantity: 2,
-							Price:    1000,
+							Price:    rub(1000),
 						},
 					},
-					TotalPrice: 2000,
+					TotalPrice: rub(2000),
 				})
 			},
 			want: &models.Cart{
@@ -39,10 +47,11 @@ func TestInMemoryCartRepository_GetCart(t *testing.T) {
 					{
 						SKU:      123,
 						Quantity: 2,
-						Price:    1000,
+						Price:    rub(1000),
 					},
 				},
-				TotalPrice: 2000,
+				TotalPrice: rub(2000),
+				Version:    1,
 			},
 			wantErr: nil,
 		},
@@ -60,13 +69,15 @@ func TestInMemoryCartRepository_GetCart(t *testing.T) {
 			repo := NewCartRepository()
 			tt.setup(repo)
 
-			got, err := repo.GetCart(tt.userID)
+			got, err := repo.GetCart(context.Background(), tt.userID)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.wantErr)
 				assert.Nil(t, got)
 			} else {
-				assert.NoError(t, err)
+				require.NoError(t, err)
+				assert.False(t, got.UpdatedAt.IsZero())
+				got.UpdatedAt = time.Time{}
 				assert.Equal(t, tt.want, got)
 			}
 		})
@@ -88,10 +99,10 @@ func TestInMemoryCartRepository_SaveCart(t *testing.T) {
 					{
 						SKU:      123,
 						Quantity: 2,
-						Price:    1000,
+						Price:    rub(1000),
 					},
 				},
-				TotalPrice: 2000,
+				TotalPrice: rub(2000),
 			},
 			setup:   func(repo *CartRepository) {},
 			wantErr: nil,
@@ -104,26 +115,57 @@ func TestInMemoryCartRepository_SaveCart(t *testing.T) {
 					{
 						SKU:      123,
 						Quantity: 3,
-						Price:    1000,
+						Price:    rub(1000),
 					},
 				},
-				TotalPrice: 3000,
+				TotalPrice: rub(3000),
+				Version:    1,
 			},
 			setup: func(repo *CartRepository) {
-				_ = repo.SaveCart(&models.Cart{
+				_ = repo.SaveCart(context.Background(), &models.Cart{
 					UserID: 1,
 					Items: models.ItemList{
 						{
 							SKU:      123,
 							Quantity: 2,
-							Price:    1000,
+							Price:    rub(1000),
 						},
 					},
-					TotalPrice: 2000,
+					TotalPrice: rub(2000),
 				})
 			},
 			wantErr: nil,
 		},
+		{
+			name: "stale version",
+			cart: &models.Cart{
+				UserID: 1,
+				Items: models.ItemList{
+					{
+						SKU:      123,
+						Quantity: 3,
+						Price:    rub(1000),
+					},
+				},
+				TotalPrice: rub(3000),
+				Version:    1,
+			},
+			setup: func(repo *CartRepository) {
+				_ = repo.SaveCart(context.Background(), &models.Cart{UserID: 1, Items: make(models.ItemList, 0)})
+				_ = repo.UpdateCart(context.Background(), 1, func(cart *models.Cart) error { return nil })
+			},
+			wantErr: models.ErrVersionConflict,
+		},
+		{
+			name: "deleted cart",
+			cart: &models.Cart{
+				UserID:  1,
+				Items:   make(models.ItemList, 0),
+				Version: 1,
+			},
+			setup:   func(repo *CartRepository) {},
+			wantErr: models.ErrVersionConflict,
+		},
 	}
 
 	for _, tt := range tests {
@@ -131,13 +173,13 @@ func TestInMemoryCartRepository_SaveCart(t *testing.T) {
 			repo := NewCartRepository()
 			tt.setup(repo)
 
-			err := repo.SaveCart(tt.cart)
+			err := repo.SaveCart(context.Background(), tt.cart)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
-				got, err := repo.GetCart(tt.cart.UserID)
+				got, err := repo.GetCart(context.Background(), tt.cart.UserID)
 				require.NoError(t, err)
 				assert.Equal(t, tt.cart, got)
 			}
@@ -157,7 +199,7 @@ func TestInMemoryCartRepository_CreateCart(t *testing.T) {
 			cart: &models.Cart{
 				UserID:     1,
 				Items:      make(models.ItemList, 0),
-				TotalPrice: 0,
+				TotalPrice: models.Money{},
 			},
 			setup:   func(repo *CartRepository) {},
 			wantErr: nil,
@@ -167,13 +209,13 @@ func TestInMemoryCartRepository_CreateCart(t *testing.T) {
 			cart: &models.Cart{
 				UserID:     1,
 				Items:      make(models.ItemList, 0),
-				TotalPrice: 0,
+				TotalPrice: models.Money{},
 			},
 			setup: func(repo *CartRepository) {
-				_ = repo.SaveCart(&models.Cart{
+				_ = repo.SaveCart(context.Background(), &models.Cart{
 					UserID:     1,
 					Items:      make(models.ItemList, 0),
-					TotalPrice: 0,
+					TotalPrice: models.Money{},
 				})
 			},
 			wantErr: models.ErrCartAlreadyExists,
@@ -185,16 +227,212 @@ func TestInMemoryCartRepository_CreateCart(t *testing.T) {
 			repo := NewCartRepository()
 			tt.setup(repo)
 
-			err := repo.CreateCart(tt.cart)
+			err := repo.CreateCart(context.Background(), tt.cart)
 			if tt.wantErr != nil {
 				assert.Error(t, err)
 				assert.ErrorIs(t, err, tt.wantErr)
 			} else {
 				assert.NoError(t, err)
-				got, err := repo.GetCart(tt.cart.UserID)
+				got, err := repo.GetCart(context.Background(), tt.cart.UserID)
 				require.NoError(t, err)
 				assert.Equal(t, tt.cart, got)
 			}
 		})
 	}
 }
+
+func TestInMemoryCartRepository_GetCartReturnsCopy(t *testing.T) {
+	repo := NewCartRepository()
+	require.NoError(t, repo.SaveCart(context.Background(), &models.Cart{
+		UserID: 1,
+		Items:  models.ItemList{{SKU: 123, Quantity: 2, Price: rub(1000)}},
+	}))
+
+	got, err := repo.GetCart(context.Background(), 1)
+	require.NoError(t, err)
+	got.Items[0].Quantity = 100
+	got.AddItem(models.Item{SKU: 456, Quantity: 1, Price: rub(500)})
+
+	stored, err := repo.GetCart(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemList{{SKU: 123, Quantity: 2, Price: rub(1000)}}, stored.Items)
+}
+
+func TestInMemoryCartRepository_UpdateCart(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(repo *CartRepository)
+		update  func(cart *models.Cart) error
+		want    *models.Cart
+		wantErr error
+	}{
+		{
+			name:  "create missing cart",
+			setup: func(repo *CartRepository) {},
+			update: func(cart *models.Cart) error {
+				cart.AddItem(models.Item{SKU: 123, Quantity: 1, Price: rub(1000)})
+				return nil
+			},
+			want: &models.Cart{
+				UserID:  1,
+				Items:   models.ItemList{{SKU: 123, Quantity: 1, Price: rub(1000)}},
+				Version: 1,
+			},
+		},
+		{
+			name: "update existing cart",
+			setup: func(repo *CartRepository) {
+				_ = repo.SaveCart(context.Background(), &models.Cart{
+					UserID: 1,
+					Items:  models.ItemList{{SKU: 123, Quantity: 1, Price: rub(1000)}},
+				})
+			},
+			update: func(cart *models.Cart) error {
+				cart.AddItem(models.Item{SKU: 123, Quantity: 2, Price: rub(1000)})
+				return nil
+			},
+			want: &models.Cart{
+				UserID:  1,
+				Items:   models.ItemList{{SKU: 123, Quantity: 3, Price: rub(1000)}},
+				Version: 2,
+			},
+		},
+		{
+			name: "failed update is discarded",
+			setup: func(repo *CartRepository) {
+				_ = repo.SaveCart(context.Background(), &models.Cart{
+					UserID: 1,
+					Items:  models.ItemList{{SKU: 123, Quantity: 1, Price: rub(1000)}},
+				})
+			},
+			update: func(cart *models.Cart) error {
+				cart.Clear()
+				return assert.AnError
+			},
+			want: &models.Cart{
+				UserID:  1,
+				Items:   models.ItemList{{SKU: 123, Quantity: 1, Price: rub(1000)}},
+				Version: 1,
+			},
+			wantErr: assert.AnError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := NewCartRepository()
+			tt.setup(repo)
+
+			err := repo.UpdateCart(context.Background(), 1, tt.update)
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			got, err := repo.GetCart(context.Background(), 1)
+			require.NoError(t, err)
+			assert.False(t, got.UpdatedAt.IsZero())
+			got.UpdatedAt = time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInMemoryCartRepository_UpdateCartConcurrent(t *testing.T) {
+	const writers = 500
+
+	repo := NewCartRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.UpdateCart(context.Background(), 1, func(cart *models.Cart) error {
+				cart.AddItem(models.Item{SKU: 123, Quantity: 1, Price: rub(1000)})
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	got, err := repo.GetCart(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, uint16(writers), got.Items[0].Quantity)
+}
+
+func TestInMemoryCartRepository_RefreshCart(t *testing.T) {
+	repo := NewCartRepository()
+
+	err := repo.RefreshCart(context.Background(), 1, func(*models.Cart) error { return nil })
+	assert.ErrorIs(t, err, models.ErrCartNotFound)
+
+	require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(1)))
+	updatedAt := time.Now().Add(-time.Hour)
+	repo.carts[1].UpdatedAt = updatedAt
+
+	require.NoError(t, repo.RefreshCart(context.Background(), 1, func(cart *models.Cart) error {
+		cart.Region = "DE"
+		return nil
+	}))
+
+	cart, err := repo.GetCart(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, "DE", cart.Region)
+	assert.Equal(t, uint64(2), cart.Version)
+	assert.True(t, updatedAt.Equal(cart.UpdatedAt))
+}
+
+func TestInMemoryCartRepository_DeleteExpired(t *testing.T) {
+	repo := NewCartRepository()
+	for userID := int64(1); userID <= 3; userID++ {
+		require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(userID)))
+	}
+
+	cutoff := time.Now().Add(-time.Hour)
+	repo.carts[1].UpdatedAt = cutoff.Add(-time.Minute)
+	repo.carts[2].UpdatedAt = cutoff.Add(-time.Second)
+
+	deleted, err := repo.DeleteExpired(context.Background(), cutoff, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = repo.DeleteExpired(context.Background(), cutoff, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	for _, userID := range []int64{1, 2} {
+		_, err = repo.GetCart(context.Background(), userID)
+		assert.ErrorIs(t, err, models.ErrCartNotFound)
+	}
+	_, err = repo.GetCart(context.Background(), 3)
+	assert.NoError(t, err)
+}
+
+func TestInMemoryCartRepository_ListIdle(t *testing.T) {
+	repo := NewCartRepository()
+	for _, userID := range []int64{-1, 4, 2, 3, 1} {
+		require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(userID)))
+	}
+
+	cutoff := time.Now().Add(-time.Hour)
+	for _, userID := range []int64{-1, 1, 2, 4} {
+		repo.carts[userID].UpdatedAt = cutoff.Add(-time.Minute)
+	}
+
+	idle, err := repo.ListIdle(context.Background(), cutoff, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, idle, 2)
+	assert.Equal(t, int64(1), idle[0].UserID)
+	assert.Equal(t, int64(2), idle[1].UserID)
+
+	idle, err = repo.ListIdle(context.Background(), cutoff, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, idle, 1)
+	assert.Equal(t, int64(4), idle[0].UserID)
+
+	// Listed carts are copies
+	idle[0].Items = append(idle[0].Items, models.Item{SKU: 1})
+	got, err := repo.GetCart(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Empty(t, got.Items)
+}
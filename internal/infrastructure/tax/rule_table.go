@@ -0,0 +1,187 @@
+package tax
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// ruleFile is the layout of a tax rule file
+type ruleFile struct {
+	// DefaultRegion applies to carts without a region; empty means no taxes for them
+	DefaultRegion string `yaml:"default_region"`
+
+	// DefaultCategory is the category of SKUs not listed in SKUs
+	DefaultCategory string `yaml:"default_category"`
+
+	// SKUs maps SKUs to their tax category
+	SKUs map[uint32]string `yaml:"skus"`
+
+	// Regions maps regions to the tax rate of each category, in percent.
+	// Values are decimal strings so that they are read exactly.
+	Regions map[string]map[string]string `yaml:"regions"`
+}
+
+// RuleTable implements ports.TaxCalculator with rules read from a YAML file
+type RuleTable struct {
+	defaultRegion   string
+	defaultCategory string
+	categories      map[uint32]string
+
+	// rates holds the fraction charged for each category by region
+	rates map[string]map[string]*big.Rat
+}
+
+// NewRuleTable loads tax rules from a YAML file such as:
+//
+//	default_region: RU
+//	default_category: standard
+//	skus:
+//	  1076963: food
+//	regions:
+//	  RU:
+//	    standard: "20"
+//	    food: "10"
+//
+// Every region must have a rate for every category.
+func NewRuleTable(path string) (*RuleTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tax rules: %w", err)
+	}
+
+	var file ruleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse tax rules: %w", err)
+	}
+
+	if file.DefaultCategory == "" {
+		return nil, errors.New("tax rules have no default category")
+	}
+
+	categories := []string{file.DefaultCategory}
+	for _, category := range file.SKUs {
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+
+	rates := make(map[string]map[string]*big.Rat, len(file.Regions))
+	for region, regionRates := range file.Regions {
+		region = normalizeRegion(region)
+		rates[region] = make(map[string]*big.Rat, len(regionRates))
+
+		for _, category := range categories {
+			value, ok := regionRates[category]
+			if !ok {
+				return nil, fmt.Errorf("no %s tax rate for region %s", category, region)
+			}
+
+			percent, ok := new(big.Rat).SetString(value)
+			if !ok || percent.Sign() < 0 {
+				return nil, fmt.Errorf("invalid %s tax rate for region %s: %q", category, region, value)
+			}
+			rates[region][category] = percent.Quo(percent, big.NewRat(100, 1))
+		}
+	}
+
+	defaultRegion := normalizeRegion(file.DefaultRegion)
+	if _, ok := rates[defaultRegion]; defaultRegion != "" && !ok {
+		return nil, fmt.Errorf("no tax rates for default region %s", defaultRegion)
+	}
+
+	return &RuleTable{
+		defaultRegion:   defaultRegion,
+		defaultCategory: file.DefaultCategory,
+		categories:      file.SKUs,
+		rates:           rates,
+	}, nil
+}
+
+// Taxes implements ports.TaxCalculator. Prices include taxes, so the tax of a
+// category is the part rate/(1+rate) of the price of its items, rounded to the
+// minor unit halves away from zero.
+func (t *RuleTable) Taxes(_ context.Context, region string, items []models.Item) ([]models.TaxLine, error) {
+	region = normalizeRegion(region)
+	if region == "" {
+		region = t.defaultRegion
+	}
+	if region == "" {
+		return nil, nil
+	}
+
+	rates, ok := t.rates[region]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ports.ErrRegionNotFound, region)
+	}
+
+	gross := make(map[string]models.Money)
+	for _, item := range items {
+		price, err := item.FinalPrice()
+		if err != nil {
+			return nil, err
+		}
+
+		category := t.category(item.SKU)
+		if gross[category], err = gross[category].Add(price); err != nil {
+			return nil, err
+		}
+	}
+
+	lines := make([]models.TaxLine, 0, len(gross))
+	for category, amount := range gross {
+		if amount.IsZero() {
+			continue
+		}
+
+		rate := rates[category]
+		included := new(big.Rat).Quo(rate, new(big.Rat).Add(rate, big.NewRat(1, 1)))
+		tax, err := amount.MulRat(included)
+		if err != nil {
+			return nil, err
+		}
+
+		// The tax is at most the amount, so this cannot overflow
+		taxable, _ := amount.Sub(tax)
+
+		lines = append(lines, models.TaxLine{
+			Category: category,
+			Rate:     rate,
+			Taxable:  taxable,
+			Amount:   tax,
+		})
+	}
+
+	slices.SortFunc(lines, func(a, b models.TaxLine) int {
+		return strings.Compare(a.Category, b.Category)
+	})
+	return lines, nil
+}
+
+// HasRegion implements ports.TaxCalculator
+func (t *RuleTable) HasRegion(_ context.Context, region string) bool {
+	_, ok := t.rates[normalizeRegion(region)]
+	return ok
+}
+
+// category returns the tax category of sku
+func (t *RuleTable) category(sku uint32) string {
+	if category, ok := t.categories[sku]; ok {
+		return category
+	}
+	return t.defaultCategory
+}
+
+// normalizeRegion returns the canonical form of a region code
+func normalizeRegion(region string) string {
+	return strings.ToUpper(strings.TrimSpace(region))
+}
@@ -0,0 +1,137 @@
+package tax
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// rub returns an amount in the default currency
+func rub(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
+// writeRules writes a tax rule file and returns its path
+func writeRules(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "tax_rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+const rules = `
+default_region: RU
+default_category: standard
+skus:
+  2: food
+regions:
+  RU:
+    standard: "20"
+    food: "10"
+  US-CA:
+    standard: "7.25"
+    food: "0"
+`
+
+func TestRuleTable_Taxes(t *testing.T) {
+	table, err := NewRuleTable(writeRules(t, rules))
+	require.NoError(t, err)
+
+	items := []models.Item{
+		{SKU: 1, Quantity: 2, Price: rub(1005)},
+		{SKU: 2, Quantity: 1, Price: rub(1000), Discount: rub(100)},
+		{SKU: 3, Quantity: 1, Price: rub(500)},
+	}
+
+	tests := []struct {
+		name   string
+		region string
+		want   []models.TaxLine
+		rates  []string
+	}{
+		{
+			name: "default region",
+			want: []models.TaxLine{
+				{Category: "food", Taxable: rub(818), Amount: rub(82)},
+				{Category: "standard", Taxable: rub(2092), Amount: rub(418)},
+			},
+			rates: []string{"10", "20"},
+		},
+		{
+			name:   "region with a fractional rate",
+			region: "us-ca",
+			want: []models.TaxLine{
+				{Category: "food", Taxable: rub(900), Amount: rub(0)},
+				{Category: "standard", Taxable: rub(2340), Amount: rub(170)},
+			},
+			rates: []string{"0", "7.25"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := table.Taxes(context.Background(), tt.region, items)
+			require.NoError(t, err)
+			require.Len(t, lines, len(tt.want))
+
+			for i, line := range lines {
+				assert.Equal(t, tt.rates[i], line.RatePercent())
+				line.Rate = nil
+				assert.Equal(t, tt.want[i], line)
+			}
+		})
+	}
+
+	_, err = table.Taxes(context.Background(), "DE", items)
+	assert.ErrorIs(t, err, ports.ErrRegionNotFound)
+	assert.True(t, table.HasRegion(context.Background(), "US-CA"))
+	assert.False(t, table.HasRegion(context.Background(), "DE"))
+}
+
+func TestRuleTable_TaxesRoundHalfAwayFromZero(t *testing.T) {
+	table, err := NewRuleTable(writeRules(t, rules))
+	require.NoError(t, err)
+
+	// 20% included in 3 is 0.5
+	lines, err := table.Taxes(context.Background(), "", []models.Item{{SKU: 1, Quantity: 1, Price: rub(3)}})
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	assert.Equal(t, rub(1), lines[0].Amount)
+	assert.Equal(t, rub(2), lines[0].Taxable)
+}
+
+func TestRuleTable_NoDefaultRegion(t *testing.T) {
+	table, err := NewRuleTable(writeRules(t, "default_category: standard\nregions:\n  RU:\n    standard: \"20\"\n"))
+	require.NoError(t, err)
+
+	lines, err := table.Taxes(context.Background(), "", []models.Item{{SKU: 1, Quantity: 1, Price: rub(100)}})
+	require.NoError(t, err)
+	assert.Empty(t, lines)
+}
+
+func TestNewRuleTable_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "no default category", content: "regions:\n  RU:\n    standard: \"20\"\n"},
+		{name: "missing category rate", content: "default_category: standard\nskus:\n  1: food\nregions:\n  RU:\n    standard: \"20\"\n"},
+		{name: "invalid rate", content: "default_category: standard\nregions:\n  RU:\n    standard: \"twenty\"\n"},
+		{name: "unknown default region", content: "default_region: KZ\ndefault_category: standard\nregions:\n  RU:\n    standard: \"20\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRuleTable(writeRules(t, tt.content))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package tracing
+
+import (
+	"context"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "route256/cart/internal/usecase/cart"
+
+// cartService decorates ports.CartService with spans
+type cartService struct {
+	next   ports.CartService
+	tracer trace.Tracer
+}
+
+// NewCartService wraps service so that every call is traced
+func NewCartService(service ports.CartService) ports.CartService {
+	return &cartService{
+		next:   service,
+		tracer: otel.Tracer(tracerName),
+	}
+}
+
+// AddItem implements ports.CartService
+func (s *cartService) AddItem(ctx context.Context, userID int64, sku uint32, count uint16, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.AddItem", userID,
+		attribute.Int64("cart.sku", int64(sku)),
+		attribute.Int("cart.count", int(count)),
+	)
+	err := s.next.AddItem(ctx, userID, sku, count, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// AddItems implements ports.CartService
+func (s *cartService) AddItems(ctx context.Context, userID int64, items []models.BatchItem, expectedVersion uint64) ([]models.BatchItemResult, error) {
+	ctx, span := s.start(ctx, "CartService.AddItems", userID,
+		attribute.Int("cart.items", len(items)),
+	)
+	results, err := s.next.AddItems(ctx, userID, items, expectedVersion)
+	if err == nil {
+		failed := 0
+		for _, result := range results {
+			if result.Err != nil {
+				failed++
+			}
+		}
+		span.SetAttributes(attribute.Int("cart.items_failed", failed))
+	}
+	end(span, err)
+	return results, err
+}
+
+// MergeGuestCart implements ports.CartService
+func (s *cartService) MergeGuestCart(ctx context.Context, guestID, userID int64, policy models.MergePolicy, expectedVersion uint64) ([]models.BatchItemResult, error) {
+	ctx, span := s.start(ctx, "CartService.MergeGuestCart", userID,
+		attribute.Int64("cart.guest_id", guestID),
+		attribute.String("cart.merge_policy", string(policy)),
+	)
+	results, err := s.next.MergeGuestCart(ctx, guestID, userID, policy, expectedVersion)
+	end(span, err)
+	return results, err
+}
+
+// RemoveItem implements ports.CartService
+func (s *cartService) RemoveItem(ctx context.Context, userID int64, sku uint32, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.RemoveItem", userID,
+		attribute.Int64("cart.sku", int64(sku)),
+	)
+	err := s.next.RemoveItem(ctx, userID, sku, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// SetItemQuantity implements ports.CartService
+func (s *cartService) SetItemQuantity(ctx context.Context, userID int64, sku uint32, quantity uint16, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.SetItemQuantity", userID,
+		attribute.Int64("cart.sku", int64(sku)),
+		attribute.Int("cart.count", int(quantity)),
+	)
+	err := s.next.SetItemQuantity(ctx, userID, sku, quantity, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// ChangeItemQuantity implements ports.CartService
+func (s *cartService) ChangeItemQuantity(ctx context.Context, userID int64, sku uint32, delta int32, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.ChangeItemQuantity", userID,
+		attribute.Int64("cart.sku", int64(sku)),
+		attribute.Int("cart.delta", int(delta)),
+	)
+	err := s.next.ChangeItemQuantity(ctx, userID, sku, delta, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// GetCart implements ports.CartService
+func (s *cartService) GetCart(ctx context.Context, userID int64, currency string) (*models.Cart, error) {
+	ctx, span := s.start(ctx, "CartService.GetCart", userID, attribute.String("cart.currency", currency))
+	cart, err := s.next.GetCart(ctx, userID, currency)
+	end(span, err)
+	return cart, err
+}
+
+// ClearCart implements ports.CartService
+func (s *cartService) ClearCart(ctx context.Context, userID int64, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.ClearCart", userID)
+	err := s.next.ClearCart(ctx, userID, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// ApplyCoupon implements ports.CartService
+func (s *cartService) ApplyCoupon(ctx context.Context, userID int64, code string, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.ApplyCoupon", userID, attribute.String("cart.coupon", code))
+	err := s.next.ApplyCoupon(ctx, userID, code, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// RemoveCoupon implements ports.CartService
+func (s *cartService) RemoveCoupon(ctx context.Context, userID int64, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.RemoveCoupon", userID)
+	err := s.next.RemoveCoupon(ctx, userID, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// SetRegion implements ports.CartService
+func (s *cartService) SetRegion(ctx context.Context, userID int64, region string, expectedVersion uint64) error {
+	ctx, span := s.start(ctx, "CartService.SetRegion", userID, attribute.String("cart.region", region))
+	err := s.next.SetRegion(ctx, userID, region, expectedVersion)
+	end(span, err)
+	return err
+}
+
+// Checkout implements ports.CartService
+func (s *cartService) Checkout(ctx context.Context, userID int64, expectedVersion uint64, confirmPriceChanges bool) (int64, error) {
+	ctx, span := s.start(ctx, "CartService.Checkout", userID)
+	orderID, err := s.next.Checkout(ctx, userID, expectedVersion, confirmPriceChanges)
+	if err == nil {
+		span.SetAttributes(attribute.Int64("cart.order_id", orderID))
+	}
+	end(span, err)
+	return orderID, err
+}
+
+// start opens a span annotated with the cart owner
+func (s *cartService) start(ctx context.Context, name string, userID int64, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return s.tracer.Start(ctx, name, trace.WithAttributes(
+		append([]attribute.KeyValue{attribute.Int64("cart.user_id", userID)}, attrs...)...,
+	))
+}
+
+// end records err on span and closes it
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
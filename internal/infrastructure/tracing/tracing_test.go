@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInit(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name: "disabled",
+			cfg:  Config{Exporter: ExporterNone},
+		},
+		{
+			name: "stdout",
+			cfg:  Config{Exporter: ExporterStdout, ServiceName: "cart-test"},
+		},
+		{
+			name: "otlp",
+			cfg:  Config{Exporter: ExporterOTLP, Endpoint: "localhost:4317", Insecure: true},
+		},
+		{
+			name:    "unknown exporter",
+			cfg:     Config{Exporter: "zipkin"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Init(context.Background(), tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package abandoned
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// DefaultBatchSize is the number of carts read at once when no batch size is configured
+const DefaultBatchSize = 100
+
+// Scanner periodically looks for carts left unchanged for long and notifies
+// about each once for every idle threshold it crosses
+type Scanner struct {
+	repo       ports.CartRepository
+	notifier   ports.AbandonedCartNotifier
+	notices    ports.AbandonedCartLog
+	thresholds []time.Duration
+	interval   time.Duration
+	batchSize  int
+	now        func() time.Time
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// NewScanner creates a scanner looking for carts of repo idle for longer than
+// any of thresholds every interval, batchSize carts at a time. Notifications
+// go to notifier and are recorded in notices.
+func NewScanner(
+	repo ports.CartRepository,
+	notifier ports.AbandonedCartNotifier,
+	notices ports.AbandonedCartLog,
+	thresholds []time.Duration,
+	interval time.Duration,
+	batchSize int,
+) (*Scanner, error) {
+	if len(thresholds) == 0 {
+		return nil, errors.New("no idle thresholds")
+	}
+
+	thresholds = slices.Clone(thresholds)
+	slices.Sort(thresholds)
+	if thresholds[0] <= 0 {
+		return nil, fmt.Errorf("invalid idle threshold %s", thresholds[0])
+	}
+
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &Scanner{
+		repo:       repo,
+		notifier:   notifier,
+		notices:    notices,
+		thresholds: slices.Compact(thresholds),
+		interval:   interval,
+		batchSize:  batchSize,
+		now:        time.Now,
+	}, nil
+}
+
+// Start runs Scan every interval in the background until Stop is called
+func (s *Scanner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.done.Add(1)
+	go func() {
+		defer s.done.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			sent, err := s.Scan(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to notify about abandoned carts: %v", err)
+			}
+			if sent > 0 {
+				log.Printf("Sent %d abandoned cart notifications", sent)
+			}
+		}
+	}()
+}
+
+// Stop stops the background scans, interrupting a running one between
+// carts, and waits for them to finish
+func (s *Scanner) Stop() {
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	s.done.Wait()
+}
+
+// Scan notifies about the carts that crossed an idle threshold since they
+// were last notified about and returns how many notifications it sent.
+// A failed notification does not stop the scan; it is retried by the next one.
+// Notices about carts that are gone or no longer idle are forgotten once all
+// carts are scanned.
+func (s *Scanner) Scan(ctx context.Context) (int, error) {
+	now := s.now()
+	cutoff := now.Add(-s.thresholds[0])
+
+	var (
+		sent, failed int
+		firstErr     error
+		idle         = make(map[int64]struct{})
+	)
+
+	// Guest carts have negative IDs and nobody to notify
+	after := int64(0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		carts, err := s.repo.ListIdle(ctx, cutoff, after, s.batchSize)
+		if err != nil {
+			return sent, fmt.Errorf("failed to list idle carts: %w", err)
+		}
+
+		for _, cart := range carts {
+			after = cart.UserID
+			idle[cart.UserID] = struct{}{}
+
+			notified, err := s.notify(ctx, cart, now)
+			if notified {
+				sent++
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return sent, ctx.Err()
+				}
+
+				failed++
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+
+		if len(carts) < s.batchSize {
+			break
+		}
+	}
+
+	// A cart changed since its notice is notified about anew anyway
+	if err := s.notices.Retain(ctx, idle); err != nil {
+		return sent, fmt.Errorf("failed to forget stale notices: %w", err)
+	}
+
+	if failed > 0 {
+		return sent, fmt.Errorf("failed to notify about %d carts: %w", failed, firstErr)
+	}
+	return sent, nil
+}
+
+// notify sends a notification about cart unless one was already sent for
+// the stage it reached, and reports whether it sent one
+func (s *Scanner) notify(ctx context.Context, cart *models.Cart, now time.Time) (bool, error) {
+	if len(cart.Items) == 0 {
+		return false, nil
+	}
+
+	stage := s.stage(now.Sub(cart.UpdatedAt))
+	if stage == 0 {
+		return false, nil
+	}
+
+	last, found, err := s.notices.LastNotice(ctx, cart.UserID)
+	if err != nil {
+		return false, fmt.Errorf("failed to read notices of user %d: %w", cart.UserID, err)
+	}
+	if found && last.CartUpdatedAt.Equal(cart.UpdatedAt) && last.Stage >= stage {
+		return false, nil
+	}
+
+	event := models.AbandonedCart{
+		Cart:       cart,
+		Stage:      stage,
+		Threshold:  s.thresholds[stage-1],
+		DetectedAt: now,
+	}
+	if err := s.notifier.NotifyAbandonedCart(ctx, event); err != nil {
+		return false, fmt.Errorf("failed to notify user %d: %w", cart.UserID, err)
+	}
+
+	notice := models.AbandonedCartNotice{
+		UserID:        cart.UserID,
+		CartUpdatedAt: cart.UpdatedAt,
+		Stage:         stage,
+		SentAt:        now,
+	}
+	if err := s.notices.Record(ctx, notice); err != nil {
+		return true, fmt.Errorf("failed to record notice to user %d: %w", cart.UserID, err)
+	}
+
+	return true, nil
+}
+
+// stage returns the number of thresholds a cart idle for idle has crossed
+func (s *Scanner) stage(idle time.Duration) int {
+	stage := 0
+	for _, threshold := range s.thresholds {
+		if idle < threshold {
+			break
+		}
+		stage++
+	}
+	return stage
+}
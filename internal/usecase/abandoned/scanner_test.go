@@ -0,0 +1,170 @@
+package abandoned
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/infrastructure/notifier"
+	"route256/cart/internal/infrastructure/repository/inmemory"
+)
+
+// fakeNotifier records the events it is sent, or fails with err
+type fakeNotifier struct {
+	events []models.AbandonedCart
+	err    error
+}
+
+func (n *fakeNotifier) NotifyAbandonedCart(_ context.Context, event models.AbandonedCart) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.events = append(n.events, event)
+	return nil
+}
+
+// stages returns the user ID and stage of every event sent
+func (n *fakeNotifier) stages() map[int64]int {
+	stages := make(map[int64]int)
+	for _, event := range n.events {
+		stages[event.Cart.UserID] = event.Stage
+	}
+	return stages
+}
+
+// addItem puts an item into the cart of userID
+func addItem(t *testing.T, repo *inmemory.CartRepository, userID int64) {
+	t.Helper()
+
+	require.NoError(t, repo.UpdateCart(context.Background(), userID, func(cart *models.Cart) error {
+		cart.AddItem(models.Item{SKU: 1, Quantity: 1, Price: models.NewMoney(100, models.DefaultCurrency)})
+		return nil
+	}))
+}
+
+func newTestScanner(t *testing.T, repo *inmemory.CartRepository, sink *fakeNotifier, batchSize int) (*Scanner, *time.Time) {
+	t.Helper()
+
+	notices, err := notifier.NewNotices("")
+	require.NoError(t, err)
+
+	scanner, err := NewScanner(repo, sink, notices, []time.Duration{24 * time.Hour, time.Hour}, time.Minute, batchSize)
+	require.NoError(t, err)
+
+	clock := time.Now()
+	scanner.now = func() time.Time { return clock }
+	return scanner, &clock
+}
+
+func TestScanner_Scan(t *testing.T) {
+	repo := inmemory.NewCartRepository()
+	addItem(t, repo, 1)
+	addItem(t, repo, 2)
+	require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(3)))
+	addItem(t, repo, -4)
+
+	sink := &fakeNotifier{}
+	scanner, clock := newTestScanner(t, repo, sink, 0)
+
+	sent, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// Empty and guest carts are never notified about
+	*clock = clock.Add(time.Hour + time.Minute)
+	sent, err = scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, map[int64]int{1: 1, 2: 1}, sink.stages())
+	assert.Equal(t, time.Hour, sink.events[0].Threshold)
+
+	// Nothing new to notify about
+	sent, err = scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// A cart changed since the notification is idle anew
+	addItem(t, repo, 2)
+	sink.events = nil
+
+	sent, err = scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, map[int64]int{2: 1}, sink.stages())
+
+	// Every threshold crossed is notified about once
+	*clock = clock.Add(23 * time.Hour)
+	sink.events = nil
+
+	sent, err = scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, map[int64]int{1: 2, 2: 2}, sink.stages())
+	assert.Equal(t, 24*time.Hour, sink.events[0].Threshold)
+}
+
+func TestScanner_ScanForgetsStaleNotices(t *testing.T) {
+	repo := inmemory.NewCartRepository()
+	addItem(t, repo, 1)
+	addItem(t, repo, 2)
+
+	sink := &fakeNotifier{}
+	scanner, clock := newTestScanner(t, repo, sink, 0)
+	*clock = clock.Add(time.Hour + time.Minute)
+
+	sent, err := scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	// Both carts expire and another one is abandoned
+	_, err = repo.DeleteExpired(context.Background(), time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	addItem(t, repo, 3)
+
+	sent, err = scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+
+	for userID, want := range map[int64]bool{1: false, 2: false, 3: true} {
+		_, found, err := scanner.notices.LastNotice(context.Background(), userID)
+		require.NoError(t, err)
+		assert.Equal(t, want, found, "user %d", userID)
+	}
+}
+
+func TestScanner_ScanRetriesFailedNotifications(t *testing.T) {
+	repo := inmemory.NewCartRepository()
+	for userID := int64(1); userID <= 5; userID++ {
+		addItem(t, repo, userID)
+	}
+
+	sink := &fakeNotifier{err: errors.New("webhook is down")}
+	scanner, clock := newTestScanner(t, repo, sink, 2)
+	*clock = clock.Add(2 * time.Hour)
+
+	sent, err := scanner.Scan(context.Background())
+	assert.ErrorIs(t, err, sink.err)
+	assert.Zero(t, sent)
+
+	sink.err = nil
+	sent, err = scanner.Scan(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, sent)
+	assert.Len(t, sink.stages(), 5)
+}
+
+func TestNewScanner_Invalid(t *testing.T) {
+	repo := inmemory.NewCartRepository()
+	notices, err := notifier.NewNotices("")
+	require.NoError(t, err)
+
+	_, err = NewScanner(repo, &fakeNotifier{}, notices, nil, time.Minute, 0)
+	assert.Error(t, err)
+	_, err = NewScanner(repo, &fakeNotifier{}, notices, []time.Duration{time.Hour, 0}, time.Minute, 0)
+	assert.Error(t, err)
+}
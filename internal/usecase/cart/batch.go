@@ -0,0 +1,140 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"route256/cart/internal/domain/models"
+)
+
+// stockInfo is the product and stock looked up for one SKU of a batch
+type stockInfo struct {
+	product *models.Product
+	stock   uint64
+	err     error
+}
+
+// FailureReason returns the reason code reported to clients for an entry of
+// AddItems or MergeGuestCart that failed with err
+func FailureReason(err error) string {
+	switch {
+	case errors.Is(err, models.ErrProductNotFound):
+		return "product_not_found"
+	case errors.Is(err, ErrInsufficientStock):
+		return "insufficient_stock"
+	case errors.Is(err, models.ErrLimitExceeded):
+		return "limit_exceeded"
+	default:
+		return "failed"
+	}
+}
+
+// AddItems adds a batch of items to the user's cart. Products and stocks are
+// looked up concurrently, once per SKU; entries are then checked in order like
+// AddItem and all that pass are added in a single update. The result of each
+// entry is reported in the order given. Errors other than an unknown product,
+// a missing stock or an exceeded limit fail the whole batch.
+func (s *CartService) AddItems(ctx context.Context, userID int64, items []models.BatchItem, expectedVersion uint64) ([]models.BatchItemResult, error) {
+	stocks, err := s.lookupStocks(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []models.BatchItemResult
+	err = s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		if err := checkVersion(cart, expectedVersion); err != nil {
+			return err
+		}
+
+		results = make([]models.BatchItemResult, len(items))
+		added := false
+		for i, item := range items {
+			results[i] = models.BatchItemResult{SKU: item.SKU, Count: item.Count}
+
+			info := stocks[item.SKU]
+			if info.err != nil {
+				results[i].Err = info.err
+				continue
+			}
+
+			// Calculate total quantity including existing items
+			target := uint64(cart.Quantity(item.SKU)) + uint64(item.Count)
+			if err := s.limits.CheckQuantity(cart, item.SKU, target); err != nil {
+				results[i].Err = err
+				continue
+			}
+			if target > info.stock {
+				results[i].Err = ErrInsufficientStock
+				continue
+			}
+
+			cart.AddItem(models.Item{
+				SKU:      info.product.SKU,
+				Quantity: item.Count,
+				Price:    info.product.Price,
+			})
+			added = true
+		}
+
+		if !added {
+			return errNoChanges
+		}
+
+		// Calculate total price
+		return cart.CalculateTotalPrice(s.converter(ctx))
+	})
+	if err != nil && !errors.Is(err, errNoChanges) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// lookupStocks fetches the products of items and their stocks concurrently.
+// An unknown product is recorded for its SKU; any other failed lookup cancels
+// the remaining ones.
+func (s *CartService) lookupStocks(ctx context.Context, items []models.BatchItem) (map[uint32]stockInfo, error) {
+	var mu sync.Mutex
+	stocks := make(map[uint32]stockInfo, len(items))
+
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(maxProductLookups)
+
+	seen := make(map[uint32]struct{}, len(items))
+	for _, item := range items {
+		if _, ok := seen[item.SKU]; ok {
+			continue
+		}
+		seen[item.SKU] = struct{}{}
+
+		if groupCtx.Err() != nil {
+			break
+		}
+
+		g.Go(func() error {
+			product, stock, err := s.lookupStock(groupCtx, item.SKU)
+			if err != nil && !errors.Is(err, models.ErrProductNotFound) {
+				return fmt.Errorf("look up sku %d: %w", item.SKU, err)
+			}
+
+			mu.Lock()
+			stocks[item.SKU] = stockInfo{product: product, stock: stock, err: err}
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	// The loop only stops early if the request is cancelled
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return stocks, nil
+}
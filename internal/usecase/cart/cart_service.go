@@ -1,15 +1,31 @@
 package cart
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
 
 	"route256/cart/internal/domain/models"
 	"route256/cart/internal/domain/ports"
+	"route256/cart/internal/usecase/promotion"
 )
 
+// maxProductLookups bounds the number of concurrent product service calls made by one GetCart or AddItems
+const maxProductLookups = 8
+
 var (
-	ErrCartEmpty = errors.New("cart is empty")
+	ErrCartEmpty         = errors.New("cart is empty")
+	ErrInsufficientStock = errors.New("not enough items in stock")
+	ErrOrderFailed       = errors.New("order creation failed: not enough items in stock")
+	ErrPriceChanged      = errors.New("prices have changed")
+
+	// errNoChanges aborts a cart update that would not modify the cart
+	errNoChanges = errors.New("no changes")
 )
 
 // CartService implements ports.CartService interface
@@ -17,104 +33,104 @@ type CartService struct {
 	repo           ports.CartRepository
 	productService ports.ProductService
 	lomsClient     ports.LOMSClient
+	exchangeRates  ports.ExchangeRateProvider
+	promotions     *promotion.Engine
+	taxes          ports.TaxCalculator
+	limits         models.CartLimits
 }
 
-// NewCartService creates a new cart service
-func NewCartService(repo ports.CartRepository, productService ports.ProductService, lomsClient ports.LOMSClient) ports.CartService {
+// NewCartService creates a new cart service.
+// Without exchange rates, carts cannot mix or be shown in other currencies;
+// without promotions, carts are never discounted; without a tax calculator,
+// carts are not taxed. Zero limits leave carts unbounded but for stock.
+func NewCartService(
+	repo ports.CartRepository,
+	productService ports.ProductService,
+	lomsClient ports.LOMSClient,
+	exchangeRates ports.ExchangeRateProvider,
+	promotions *promotion.Engine,
+	taxes ports.TaxCalculator,
+	limits models.CartLimits,
+) ports.CartService {
 	return &CartService{
 		repo:           repo,
 		productService: productService,
 		lomsClient:     lomsClient,
+		exchangeRates:  exchangeRates,
+		promotions:     promotions,
+		taxes:          taxes,
+		limits:         limits,
 	}
 }
 
-// AddItem adds an item to the user's cart
-func (s *CartService) AddItem(userID int64, sku uint32, quantity uint16) error {
-	// Get product info
-	product, err := s.productService.GetProduct(sku)
-	if err != nil {
-		return models.ErrProductNotFound
-	}
-
-	// Check stock quantity
-	stock, err := s.lomsClient.GetStocksInfo(context.Background(), sku)
-	if err != nil {
+// checkVersion returns a VersionConflictError unless the cart has the expected
+// version. A caller expecting the current version has seen the cart at its
+// current prices, so the price changes are acknowledged.
+func checkVersion(cart *models.Cart, expected uint64) error {
+	if err := cart.CheckVersion(expected); err != nil {
 		return err
 	}
 
-	// Get current cart to check existing items
-	cart, err := s.repo.GetCart(userID)
-	if err != nil {
-		if errors.Is(err, models.ErrCartNotFound) {
-			cart = models.NewCart(userID)
-			// Create new cart
-			if err := s.repo.CreateCart(cart); err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
-	}
-
-	// Calculate total quantity including existing items
-	totalQuantity := quantity
-	for _, item := range cart.Items {
-		if item.SKU == sku {
-			totalQuantity += item.Quantity
-		}
-	}
-
-	if uint64(totalQuantity) > stock {
-		return errors.New("not enough items in stock")
+	if expected != models.AnyVersion {
+		cart.AcknowledgePrices()
 	}
+	return nil
+}
 
-	// Add item to cart
-	cart.AddItem(models.Item{
-		SKU:      product.SKU,
-		Quantity: quantity,
-		Price:    product.Price,
+// AddItem adds an item to the user's cart
+func (s *CartService) AddItem(ctx context.Context, userID int64, sku uint32, quantity uint16, expectedVersion uint64) error {
+	return s.updateQuantity(ctx, userID, sku, expectedVersion, true, func(current uint64) uint64 {
+		// Calculate total quantity including existing items
+		return current + uint64(quantity)
 	})
-
-	// Calculate total price
-	cart.CalculateTotalPrice()
-
-	// Save cart
-	return s.repo.SaveCart(cart)
 }
 
 // RemoveItem removes an item from the user's cart
-func (s *CartService) RemoveItem(userID int64, sku uint32) error {
-	cart, err := s.repo.GetCart(userID)
-	if err != nil {
-		if errors.Is(err, models.ErrCartNotFound) {
-			return nil // As per spec, return success if cart doesn't exist
+func (s *CartService) RemoveItem(ctx context.Context, userID int64, sku uint32, expectedVersion uint64) error {
+	err := s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		if err := checkVersion(cart, expectedVersion); err != nil {
+			return err
 		}
-		return err
-	}
 
-	cart.RemoveItem(sku)
-	cart.CalculateTotalPrice()
+		if !cart.RemoveItem(sku) {
+			return errNoChanges
+		}
 
-	return s.repo.SaveCart(cart)
+		return cart.CalculateTotalPrice(s.converter(ctx))
+	})
+	if errors.Is(err, errNoChanges) {
+		return nil // As per spec, return success if cart or item doesn't exist
+	}
+
+	return err
 }
 
 // ClearCart removes all items from the user's cart
-func (s *CartService) ClearCart(userID int64) error {
-	cart, err := s.repo.GetCart(userID)
-	if err != nil {
-		if errors.Is(err, models.ErrCartNotFound) {
-			return nil // As per spec, return success if cart doesn't exist
+func (s *CartService) ClearCart(ctx context.Context, userID int64, expectedVersion uint64) error {
+	err := s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		if err := checkVersion(cart, expectedVersion); err != nil {
+			return err
 		}
-		return err
+
+		if len(cart.Items) == 0 {
+			return errNoChanges
+		}
+
+		cart.Clear()
+		return nil
+	})
+	if errors.Is(err, errNoChanges) {
+		return nil // As per spec, return success if cart doesn't exist
 	}
 
-	cart.Clear()
-	return s.repo.SaveCart(cart)
+	return err
 }
 
-// GetCart returns the user's cart
-func (s *CartService) GetCart(userID int64) (*models.Cart, error) {
-	cart, err := s.repo.GetCart(userID)
+// GetCart returns the user's cart with items sorted by SKU, repriced and
+// named after the current product data, discounted by promotions and taxed.
+// Prices and totals are converted to currency unless it is empty.
+func (s *CartService) GetCart(ctx context.Context, userID int64, currency string) (*models.Cart, error) {
+	cart, err := s.reprice(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -123,54 +139,180 @@ func (s *CartService) GetCart(userID int64) (*models.Cart, error) {
 		return nil, models.ErrCartNotFound
 	}
 
+	cart, err = s.discount(ctx, cart, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.tax(ctx, cart); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(cart.Items, func(a, b models.Item) int {
+		return cmp.Compare(a.SKU, b.SKU)
+	})
+
 	return cart, nil
 }
 
-// Checkout creates an order from the cart and clears it
-func (s *CartService) Checkout(ctx context.Context, userID int64) (int64, error) {
-	// Get cart
-	cart, err := s.repo.GetCart(userID)
+// reprice reads the cart, brings its prices in line with the product service
+// and stores them if any changed, which changes the version but not UpdatedAt. Items of the returned cart are named after
+// the current product data; those whose products are gone are marked unavailable.
+func (s *CartService) reprice(ctx context.Context, userID int64) (*models.Cart, error) {
+	cart, err := s.repo.GetCart(ctx, userID)
 	if err != nil {
-		if errors.Is(err, models.ErrCartNotFound) {
-			return 0, models.ErrCartNotFound
-		}
-		return 0, err
+		return nil, err
 	}
 
-	// Check if cart is empty
 	if len(cart.Items) == 0 {
-		return 0, ErrCartEmpty
+		return cart, nil
 	}
 
-	// Convert cart items to LOMS items
-	items := make([]ports.Item, len(cart.Items))
-	for i, item := range cart.Items {
-		items[i] = ports.Item{
-			SKU:   item.SKU,
-			Count: item.Quantity, // Quantity is already uint16
+	products, err := s.lookupProducts(ctx, cart.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[uint32]models.Money, len(products))
+	for sku, product := range products {
+		prices[sku] = product.Price
+	}
+
+	changed, err := cart.Clone().Reprice(prices, s.converter(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		// The cart may have changed since it was read; reprice what is stored now
+		err = s.repo.RefreshCart(ctx, userID, func(stored *models.Cart) error {
+			changed, err := stored.Reprice(prices, s.converter(ctx))
+			if err != nil {
+				return err
+			}
+
+			cart = stored.Clone()
+			if !changed {
+				return errNoChanges
+			}
+			return nil
+		})
+		switch {
+		case err == nil:
+			// Stored with the version bumped
+			cart.Version++
+		case !errors.Is(err, errNoChanges):
+			return nil, err
 		}
 	}
 
-	// Create order in LOMS
-	orderID, err := s.lomsClient.CreateOrder(ctx, userID, items)
+	for i := range cart.Items {
+		if product, ok := products[cart.Items[i].SKU]; ok {
+			cart.Items[i].Name = product.Name
+		} else {
+			cart.Items[i].Unavailable = true
+		}
+	}
+
+	return cart, nil
+}
+
+// discount returns a copy of the cart with prices and totals in currency, or
+// in the cart's own currency if it is empty, and promotions applied. Totals
+// are recalculated at current exchange rates.
+func (s *CartService) discount(ctx context.Context, cart *models.Cart, currency string) (*models.Cart, error) {
+	if currency == "" {
+		currency = cart.Currency()
+	}
+
+	cart, err := cart.ConvertTo(currency, s.converter(ctx))
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	if s.promotions != nil {
+		if err := s.promotions.Apply(cart, s.converter(ctx)); err != nil {
+			return nil, err
+		}
 	}
 
-	// Check order status
-	orderInfo, err := s.lomsClient.GetOrderInfo(ctx, orderID)
+	return cart, nil
+}
+
+// tax sets the taxes of the cart's region included in its prices. A region
+// dropped from the tax rules since it was set falls back to the default one,
+// so that the cart stays readable; the returned cart then has no region and
+// names the dropped one in UnknownRegion.
+func (s *CartService) tax(ctx context.Context, cart *models.Cart) error {
+	if s.taxes == nil {
+		return nil
+	}
+
+	taxes, err := s.taxes.Taxes(ctx, cart.Region, cart.Items)
+	if errors.Is(err, ports.ErrRegionNotFound) && cart.Region != "" {
+		cart.UnknownRegion, cart.Region = cart.Region, ""
+		taxes, err = s.taxes.Taxes(ctx, cart.Region, cart.Items)
+	}
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	if orderInfo.Status == "failed" {
-		return 0, errors.New("order creation failed: not enough items in stock")
+	return cart.ApplyTaxes(taxes)
+}
+
+// lookupProducts fetches the products of items concurrently. Products that
+// are not found are left out. The first failed lookup cancels the remaining ones.
+func (s *CartService) lookupProducts(ctx context.Context, items []models.Item) (map[uint32]*models.Product, error) {
+	var mu sync.Mutex
+	products := make(map[uint32]*models.Product, len(items))
+
+	g, groupCtx := errgroup.WithContext(ctx)
+	g.SetLimit(maxProductLookups)
+
+	for _, item := range items {
+		if groupCtx.Err() != nil {
+			break
+		}
+
+		g.Go(func() error {
+			product, err := s.productService.GetProduct(groupCtx, item.SKU)
+			if errors.Is(err, models.ErrProductNotFound) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("get product %d: %w", item.SKU, err)
+			}
+
+			mu.Lock()
+			products[item.SKU] = product
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
 	}
+	// Lookups skipped by a cancelled caller must not pass for missing products
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return products, nil
+}
 
-	// Clear cart after successful order creation
-	if err := s.ClearCart(userID); err != nil {
-		return 0, err
+// converter returns a models.ConvertFunc backed by the exchange rate provider,
+// or nil if there is none
+func (s *CartService) converter(ctx context.Context) models.ConvertFunc {
+	if s.exchangeRates == nil {
+		return nil
 	}
 
-	return orderID, nil
+	return func(amount models.Money, currency string) (models.Money, error) {
+		rate, err := s.exchangeRates.Rate(ctx, amount.Currency, currency)
+		if err != nil {
+			return models.Money{}, err
+		}
+		return amount.Convert(currency, rate)
+	}
 }
@@ -0,0 +1,1153 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gojuno/minimock/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+	"route256/cart/internal/infrastructure/repository/inmemory"
+	"route256/cart/internal/usecase/cart/mocks"
+	"route256/cart/internal/usecase/promotion"
+)
+
+// rub returns an amount in the default currency
+func rub(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
+// fakeLOMS is an in-process ports.LOMSClient for service tests
+type fakeLOMS struct {
+	mu sync.Mutex
+
+	stock       uint64
+	orderStatus string
+
+	createErr error
+	infoErr   error
+	cancelErr error
+
+	created    []int64
+	ordered    [][]ports.Item
+	cancelled  []int64
+	stockCalls int
+
+	// onCreate, if set, runs before an order is created
+	onCreate func()
+}
+
+func (f *fakeLOMS) CreateOrder(_ context.Context, _ int64, items []ports.Item) (int64, error) {
+	if f.onCreate != nil {
+		f.onCreate()
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.createErr != nil {
+		return 0, f.createErr
+	}
+
+	orderID := int64(len(f.created) + 1)
+	f.created = append(f.created, orderID)
+	f.ordered = append(f.ordered, items)
+	return orderID, nil
+}
+
+func (f *fakeLOMS) GetStocksInfo(ctx context.Context, _ uint32) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stockCalls++
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.stock, nil
+}
+
+func (f *fakeLOMS) GetOrderInfo(_ context.Context, _ int64) (*ports.OrderInfo, error) {
+	if f.infoErr != nil {
+		return nil, f.infoErr
+	}
+
+	status := f.orderStatus
+	if status == "" {
+		status = "awaiting payment"
+	}
+	return &ports.OrderInfo{Status: status}, nil
+}
+
+func (f *fakeLOMS) CancelOrder(ctx context.Context, orderID int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if f.cancelErr != nil {
+		return f.cancelErr
+	}
+
+	f.cancelled = append(f.cancelled, orderID)
+	return nil
+}
+
+// flatTax is a ports.TaxCalculator charging 20% in region RU, the default
+type flatTax struct{}
+
+func (flatTax) Taxes(_ context.Context, region string, items []models.Item) ([]models.TaxLine, error) {
+	if region != "" && region != "RU" {
+		return nil, ports.ErrRegionNotFound
+	}
+
+	var gross models.Money
+	for _, item := range items {
+		price, err := item.FinalPrice()
+		if err != nil {
+			return nil, err
+		}
+		if gross, err = gross.Add(price); err != nil {
+			return nil, err
+		}
+	}
+
+	// 20% included in the price is a sixth of it
+	amount, err := gross.MulRat(big.NewRat(1, 6))
+	if err != nil {
+		return nil, err
+	}
+	taxable, err := gross.Sub(amount)
+	if err != nil {
+		return nil, err
+	}
+	return []models.TaxLine{{Category: "standard", Rate: big.NewRat(1, 5), Taxable: taxable, Amount: amount}}, nil
+}
+
+func (flatTax) HasRegion(_ context.Context, region string) bool {
+	return region == "RU"
+}
+
+// failingUpdateRepository fails every cart update after setup is done
+type failingUpdateRepository struct {
+	*inmemory.CartRepository
+	err error
+}
+
+func (r *failingUpdateRepository) UpdateCart(ctx context.Context, userID int64, update func(cart *models.Cart) error) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.CartRepository.UpdateCart(ctx, userID, update)
+}
+
+func TestCartService_AddItemConcurrent(t *testing.T) {
+	ctx := context.Background()
+	const writers = 300
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Return(&models.Product{SKU: 123, Name: "product", Price: rub(100)}, nil)
+
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, &fakeLOMS{stock: writers}, nil, nil, nil, models.CartLimits{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+		}()
+	}
+	wg.Wait()
+
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, uint16(writers), cart.Items[0].Quantity)
+	assert.Equal(t, rub(writers*100), cart.TotalPrice)
+
+	// Stock is exhausted now, so another unit must be rejected
+	assert.ErrorIs(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion), ErrInsufficientStock)
+}
+
+func TestCartService_ExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Return(&models.Product{SKU: 123, Name: "product", Price: rub(100)}, nil)
+
+	service := NewCartService(inmemory.NewCartRepository(), productService, &fakeLOMS{stock: 10}, nil, nil, nil, models.CartLimits{})
+
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	seen := cart.Version
+
+	// A change made in another tab bumps the version
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, seen))
+
+	assert.ErrorIs(t, service.AddItem(ctx, 1, 123, 1, seen), models.ErrVersionConflict)
+	assert.ErrorIs(t, service.RemoveItem(ctx, 1, 123, seen), models.ErrVersionConflict)
+	assert.ErrorIs(t, service.ClearCart(ctx, 1, seen), models.ErrVersionConflict)
+	_, err = service.Checkout(ctx, 1, seen, false)
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+	cart, err = service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(2), cart.Items[0].Quantity)
+	assert.NoError(t, service.ClearCart(ctx, 1, cart.Version))
+}
+
+func TestCartService_Checkout(t *testing.T) {
+	errLOMS := errors.New("loms is unavailable")
+	errStorage := errors.New("storage is unavailable")
+
+	tests := []struct {
+		name          string
+		loms          *fakeLOMS
+		updateErr     error
+		wantErr       error
+		wantOrderID   int64
+		wantCancelled []int64
+		wantCartItems int
+	}{
+		{
+			name:          "success",
+			loms:          &fakeLOMS{stock: 10},
+			wantOrderID:   1,
+			wantCancelled: nil,
+			wantCartItems: 0,
+		},
+		{
+			name:          "create order fails",
+			loms:          &fakeLOMS{stock: 10, createErr: errLOMS},
+			wantErr:       errLOMS,
+			wantCancelled: nil,
+			wantCartItems: 1,
+		},
+		{
+			name:          "order info fails",
+			loms:          &fakeLOMS{stock: 10, infoErr: errLOMS},
+			wantErr:       errLOMS,
+			wantCancelled: []int64{1},
+			wantCartItems: 1,
+		},
+		{
+			name:          "order failed",
+			loms:          &fakeLOMS{stock: 10, orderStatus: "failed"},
+			wantErr:       ErrOrderFailed,
+			wantCancelled: []int64{1},
+			wantCartItems: 1,
+		},
+		{
+			name:          "removing ordered items fails",
+			loms:          &fakeLOMS{stock: 10},
+			updateErr:     errStorage,
+			wantErr:       errStorage,
+			wantCancelled: []int64{1},
+			wantCartItems: 1,
+		},
+		{
+			name:          "cancel fails",
+			loms:          &fakeLOMS{stock: 10, infoErr: errLOMS, cancelErr: errors.New("cancel failed")},
+			wantErr:       errLOMS,
+			wantCancelled: nil,
+			wantCartItems: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := minimock.NewController(t)
+			productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+				Return(&models.Product{SKU: 123, Name: "product", Price: rub(100)}, nil)
+
+			repo := &failingUpdateRepository{CartRepository: inmemory.NewCartRepository()}
+			service := NewCartService(repo, productService, tt.loms, nil, nil, nil, models.CartLimits{})
+			require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+			repo.err = tt.updateErr
+
+			orderID, err := service.Checkout(ctx, 1, models.AnyVersion, false)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantOrderID, orderID)
+			assert.Equal(t, tt.wantCancelled, tt.loms.cancelled)
+
+			cart, err := repo.GetCart(ctx, 1)
+			require.NoError(t, err)
+			assert.Len(t, cart.Items, tt.wantCartItems)
+		})
+	}
+}
+
+func TestCartService_CheckoutConcurrentChange(t *testing.T) {
+	ctx := context.Background()
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Return(&models.Product{SKU: 123, Name: "product", Price: rub(100)}, nil)
+
+	loms := &fakeLOMS{stock: 10}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+
+	// Another tab adds to the cart while the order is being created
+	loms.onCreate = func() {
+		loms.onCreate = nil
+		require.NoError(t, service.AddItem(ctx, 1, 123, 2, models.AnyVersion))
+	}
+
+	orderID, err := service.Checkout(ctx, 1, models.AnyVersion, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), orderID)
+	assert.Nil(t, loms.cancelled)
+
+	// Only the ordered quantity leaves the cart
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, uint16(2), cart.Items[0].Quantity)
+}
+
+func TestCartService_CheckoutCancelledContext(t *testing.T) {
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Return(&models.Product{SKU: 123, Name: "product", Price: rub(100)}, nil)
+
+	loms := &fakeLOMS{stock: 10, infoErr: context.Canceled}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(context.Background(), 1, 123, 1, models.AnyVersion))
+
+	// The client goes away once the order is created
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	loms.onCreate = cancel
+
+	_, err := service.Checkout(ctx, 1, models.AnyVersion, false)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The order must still be cancelled even though the request was aborted
+	assert.Equal(t, []int64{1}, loms.cancelled)
+}
+
+func TestCartService_AddItemCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The client goes away while the product is being looked up
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(ctx context.Context, _ uint32) (*models.Product, error) {
+			cancel()
+			return nil, ctx.Err()
+		})
+
+	loms := &fakeLOMS{stock: 10}
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, loms, nil, nil, nil, models.CartLimits{})
+
+	err := service.AddItem(ctx, 1, 123, 1, models.AnyVersion)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, models.ErrProductNotFound)
+	assert.Zero(t, loms.stockCalls)
+
+	_, err = repo.GetCart(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrCartNotFound)
+}
+
+func TestCartService_AddItemProductServiceUnavailable(t *testing.T) {
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Return(nil, &models.DependencyUnavailableError{Dependency: "product", RetryAfter: time.Second})
+
+	loms := &fakeLOMS{stock: 10}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, models.CartLimits{})
+
+	err := service.AddItem(context.Background(), 1, 123, 1, models.AnyVersion)
+	assert.ErrorIs(t, err, models.ErrDependencyUnavailable)
+	assert.NotErrorIs(t, err, models.ErrProductNotFound)
+	assert.Zero(t, loms.stockCalls)
+}
+
+func TestCartService_GetCartEnrichesItems(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewCartRepository()
+	require.NoError(t, repo.SaveCart(ctx, &models.Cart{
+		UserID: 1,
+		Items: models.ItemList{
+			{SKU: 30, Quantity: 1, Price: rub(300)},
+			{SKU: 10, Quantity: 2, Price: rub(100)},
+			{SKU: 20, Quantity: 3, Price: rub(200)},
+		},
+	}))
+
+	var inFlight, maxInFlight atomic.Int64
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				current := maxInFlight.Load()
+				if n <= current || maxInFlight.CompareAndSwap(current, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			return &models.Product{SKU: sku, Name: fmt.Sprintf("product %d", sku), Price: rub(int64(sku) * 10)}, nil
+		})
+
+	service := NewCartService(repo, productService, &fakeLOMS{}, nil, nil, nil, models.CartLimits{})
+
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemList{
+		{SKU: 10, Quantity: 2, Price: rub(100), Name: "product 10"},
+		{SKU: 20, Quantity: 3, Price: rub(200), Name: "product 20"},
+		{SKU: 30, Quantity: 1, Price: rub(300), Name: "product 30"},
+	}, cart.Items)
+	assert.Greater(t, maxInFlight.Load(), int64(1))
+	assert.LessOrEqual(t, maxInFlight.Load(), int64(maxProductLookups))
+}
+
+func TestCartService_GetCartLookupFails(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemory.NewCartRepository()
+
+	items := make(models.ItemList, 4*maxProductLookups)
+	for i := range items {
+		items[i] = models.Item{SKU: uint32(i + 1), Quantity: 1, Price: rub(100)}
+	}
+	require.NoError(t, repo.SaveCart(ctx, &models.Cart{UserID: 1, Items: items}))
+
+	// The first lookup fails and the remaining ones must not be started
+	var calls atomic.Int64
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(ctx context.Context, sku uint32) (*models.Product, error) {
+			calls.Add(1)
+			if sku == 1 {
+				return nil, &models.DependencyUnavailableError{Dependency: "product", RetryAfter: time.Second}
+			}
+
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	service := NewCartService(repo, productService, &fakeLOMS{}, nil, nil, nil, models.CartLimits{})
+
+	_, err := service.GetCart(ctx, 1, "")
+	assert.ErrorIs(t, err, models.ErrDependencyUnavailable)
+	assert.LessOrEqual(t, calls.Load(), int64(maxProductLookups+1))
+}
+
+func TestCartService_PriceChanges(t *testing.T) {
+	ctx := context.Background()
+
+	var price atomic.Uint32
+	price.Store(100)
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(int64(price.Load()))}, nil
+		})
+
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, &fakeLOMS{stock: 10}, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 123, 2, models.AnyVersion))
+
+	seen, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.False(t, seen.PriceChanged())
+
+	// The price changes after the customer has seen the cart
+	price.Store(150)
+
+	_, err = service.Checkout(ctx, 1, models.AnyVersion, false)
+	assert.ErrorIs(t, err, ErrPriceChanged)
+	_, err = service.Checkout(ctx, 1, seen.Version, false)
+	assert.ErrorIs(t, err, ErrPriceChanged)
+
+	// The new price is stored and shown
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemList{{SKU: 123, Quantity: 2, Price: rub(150), PreviousPrice: rub(100), Name: "product"}}, cart.Items)
+	assert.Equal(t, rub(300), cart.TotalPrice)
+	assert.Greater(t, cart.Version, seen.Version)
+
+	// Having seen the new price, the customer may check out
+	orderID, err := service.Checkout(ctx, 1, cart.Version, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), orderID)
+}
+
+func TestCartService_CheckoutConfirmedPriceChanges(t *testing.T) {
+	ctx := context.Background()
+
+	var price atomic.Uint32
+	price.Store(100)
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(int64(price.Load()))}, nil
+		})
+
+	loms := &fakeLOMS{stock: 10}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+
+	price.Store(90)
+
+	orderID, err := service.Checkout(ctx, 1, models.AnyVersion, true)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{orderID}, loms.created)
+}
+
+func TestCartService_ProductRemovedFromCatalog(t *testing.T) {
+	ctx := context.Background()
+
+	var removed atomic.Bool
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			if sku == 456 && removed.Load() {
+				return nil, fmt.Errorf("%w: sku %d", models.ErrProductNotFound, sku)
+			}
+			return &models.Product{SKU: sku, Name: "product", Price: rub(100)}, nil
+		})
+
+	loms := &fakeLOMS{stock: 10}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+	require.NoError(t, service.AddItem(ctx, 1, 456, 1, models.AnyVersion))
+
+	removed.Store(true)
+
+	// The cart stays readable and shows the item at its last known price
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 2)
+	assert.False(t, cart.Items[0].Unavailable)
+	assert.True(t, cart.Items[1].Unavailable)
+	assert.Equal(t, rub(200), cart.TotalPrice)
+
+	_, err = service.Checkout(ctx, 1, models.AnyVersion, false)
+	assert.ErrorIs(t, err, models.ErrProductGone)
+	assert.Empty(t, loms.created)
+
+	// Once the item is removed the cart can be checked out
+	require.NoError(t, service.RemoveItem(ctx, 1, 456, cart.Version))
+	_, err = service.Checkout(ctx, 1, models.AnyVersion, false)
+	require.NoError(t, err)
+}
+
+func TestCartService_GetCartRepricingKeepsActivity(t *testing.T) {
+	ctx := context.Background()
+
+	var price atomic.Uint32
+	price.Store(100)
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(int64(price.Load()))}, nil
+		})
+
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, &fakeLOMS{stock: 10}, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+	added, err := repo.GetCart(ctx, 1)
+	require.NoError(t, err)
+
+	price.Store(90)
+
+	// Repricing is stored under a new version, but reading is no activity
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	stored, err := repo.GetCart(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, stored.Version, cart.Version)
+	assert.Equal(t, added.Version+1, stored.Version)
+	assert.True(t, added.UpdatedAt.Equal(stored.UpdatedAt))
+
+	// Reading again at unchanged prices stores nothing
+	cart, err = service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, stored.Version, cart.Version)
+}
+
+func TestCartService_PriceChangesAcknowledged(t *testing.T) {
+	ctx := context.Background()
+
+	var price atomic.Uint32
+	price.Store(100)
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(int64(price.Load()))}, nil
+		})
+
+	loms := &fakeLOMS{stock: 10}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+
+	price.Store(90)
+
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.True(t, cart.PriceChanged())
+	_, err = service.Checkout(ctx, 1, models.AnyVersion, false)
+	assert.ErrorIs(t, err, ErrPriceChanged)
+
+	// A write against the version read acknowledges the changed prices
+	require.NoError(t, service.AddItem(ctx, 1, 123, 1, cart.Version))
+
+	for range 2 {
+		cart, err = service.GetCart(ctx, 1, "")
+		require.NoError(t, err)
+		assert.False(t, cart.PriceChanged())
+		_, err = service.Checkout(ctx, 1, models.AnyVersion, false)
+		require.NoError(t, err)
+		require.NoError(t, service.AddItem(ctx, 1, 123, 2, models.AnyVersion))
+	}
+
+	// Another tab adds to the cart while a confirmed checkout is in progress
+	price.Store(80)
+	loms.onCreate = func() {
+		loms.onCreate = nil
+		require.NoError(t, service.AddItem(ctx, 1, 123, 1, models.AnyVersion))
+	}
+
+	_, err = service.Checkout(ctx, 1, models.AnyVersion, true)
+	require.NoError(t, err)
+
+	// What is left in the cart is at the price just confirmed
+	cart, err = service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1), cart.Items[0].Quantity)
+	assert.False(t, cart.PriceChanged())
+
+	_, err = service.Checkout(ctx, 1, models.AnyVersion, false)
+	require.NoError(t, err)
+	assert.Len(t, loms.created, 4)
+}
+
+func TestCartService_Coupons(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(1000)}, nil
+		})
+
+	threeForTwo, err := promotion.NewBuyXGetY(123, 2, 1)
+	require.NoError(t, err)
+	tenPercent, err := promotion.NewPercentOff(10)
+	require.NoError(t, err)
+	promotions, err := promotion.NewEngine([]promotion.Promotion{
+		{ID: "3-for-2", Rule: threeForTwo},
+		{ID: "welcome", Coupon: "WELCOME", Rule: tenPercent},
+	})
+	require.NoError(t, err)
+
+	loms := &fakeLOMS{stock: 10}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, promotions, nil, models.CartLimits{})
+
+	// Coupons apply to existing carts only
+	assert.ErrorIs(t, service.ApplyCoupon(ctx, 1, "welcome", models.AnyVersion), models.ErrCartNotFound)
+
+	require.NoError(t, service.AddItem(ctx, 1, 123, 3, models.AnyVersion))
+	require.NoError(t, service.AddItem(ctx, 1, 456, 1, models.AnyVersion))
+	assert.ErrorIs(t, service.ApplyCoupon(ctx, 1, "unknown", models.AnyVersion), models.ErrCouponNotFound)
+
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, rub(1000), cart.Items[0].Discount)
+	assert.Equal(t, rub(3000), cart.DiscountedTotal)
+
+	require.NoError(t, service.ApplyCoupon(ctx, 1, " welcome", cart.Version))
+
+	cart, err = service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "WELCOME", cart.Coupon)
+	assert.Equal(t, []models.AppliedPromotion{
+		{ID: "3-for-2", Discount: rub(1000)},
+		{ID: "welcome", Discount: rub(300)},
+	}, cart.Promotions)
+	assert.Equal(t, rub(1200), cart.Items[0].Discount)
+	assert.Equal(t, rub(100), cart.Items[1].Discount)
+	assert.Equal(t, rub(4000), cart.TotalPrice)
+	assert.Equal(t, rub(2700), cart.DiscountedTotal)
+
+	// The order is placed at discounted prices and the coupon is used up
+	_, err = service.Checkout(ctx, 1, cart.Version, false)
+	require.NoError(t, err)
+	assert.Equal(t, [][]ports.Item{{
+		{SKU: 123, Count: 3, Price: rub(1800)},
+		{SKU: 456, Count: 1, Price: rub(900)},
+	}}, loms.ordered)
+
+	require.NoError(t, service.AddItem(ctx, 1, 456, 1, models.AnyVersion))
+	cart, err = service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Empty(t, cart.Coupon)
+	assert.Empty(t, cart.Promotions)
+
+	// Removing a missing coupon succeeds
+	require.NoError(t, service.RemoveCoupon(ctx, 1, models.AnyVersion))
+}
+
+func TestCartService_Taxes(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(1000)}, nil
+		})
+
+	halfOff, err := promotion.NewPercentOff(50)
+	require.NoError(t, err)
+	promotions, err := promotion.NewEngine([]promotion.Promotion{{ID: "half", Rule: halfOff}})
+	require.NoError(t, err)
+
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, &fakeLOMS{stock: 10}, nil, promotions, flatTax{}, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 123, 3, models.AnyVersion))
+
+	assert.ErrorIs(t, service.SetRegion(ctx, 1, "DE", models.AnyVersion), ports.ErrRegionNotFound)
+	require.NoError(t, service.SetRegion(ctx, 1, " ru ", models.AnyVersion))
+
+	// Taxes are included in the discounted total
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, "RU", cart.Region)
+	assert.Equal(t, rub(3000), cart.TotalPrice)
+	assert.Equal(t, rub(1500), cart.DiscountedTotal)
+	require.Len(t, cart.Taxes, 1)
+	assert.Equal(t, rub(250), cart.Taxes[0].Amount)
+	assert.Equal(t, rub(1250), cart.Subtotal)
+	assert.Equal(t, rub(1500), cart.GrandTotal)
+
+	// A region dropped from the tax rules falls back to the default one
+	require.NoError(t, repo.UpdateCart(ctx, 1, func(cart *models.Cart) error {
+		cart.Region = "DE"
+		return nil
+	}))
+	cart, err = service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Empty(t, cart.Region)
+	assert.Equal(t, "DE", cart.UnknownRegion)
+	assert.Equal(t, rub(1500), cart.GrandTotal)
+}
+
+func TestCartService_AddItemLimits(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(100)}, nil
+		})
+
+	limits := models.CartLimits{
+		MaxQuantityPerSKU: 5,
+		SKUMaxQuantity:    map[uint32]uint64{1: 1},
+		MaxDistinctSKUs:   2,
+	}
+	service := NewCartService(inmemory.NewCartRepository(), productService, &fakeLOMS{stock: math.MaxUint32}, nil, nil, nil, limits)
+
+	require.NoError(t, service.AddItem(ctx, 1, 1, 1, models.AnyVersion))
+	err := service.AddItem(ctx, 1, 1, 1, models.AnyVersion)
+	assert.ErrorIs(t, err, models.ErrLimitExceeded)
+	assert.EqualError(t, err, "cart limit exceeded: at most 1 items of sku 1 per cart")
+
+	require.NoError(t, service.AddItem(ctx, 1, 2, 5, models.AnyVersion))
+	assert.ErrorIs(t, service.AddItem(ctx, 1, 2, 1, models.AnyVersion), models.ErrLimitExceeded)
+	assert.ErrorIs(t, service.AddItem(ctx, 1, 3, 1, models.AnyVersion), models.ErrLimitExceeded)
+
+	// Without a limit, quantities still cannot overflow
+	service = NewCartService(inmemory.NewCartRepository(), productService, &fakeLOMS{stock: math.MaxUint32}, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, 1, 1, math.MaxUint16, models.AnyVersion))
+	assert.ErrorIs(t, service.AddItem(ctx, 1, 1, 1, models.AnyVersion), models.ErrLimitExceeded)
+
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(math.MaxUint16), cart.Items[0].Quantity)
+}
+
+func TestCartService_ItemQuantity(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Name: "product", Price: rub(100)}, nil
+		})
+
+	loms := &fakeLOMS{stock: 10}
+	limits := models.CartLimits{MaxQuantityPerSKU: 8}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, limits)
+
+	quantities := func() map[uint32]uint16 {
+		cart, err := service.GetCart(ctx, 1, "")
+		if errors.Is(err, models.ErrCartNotFound) {
+			return map[uint32]uint16{}
+		}
+		require.NoError(t, err)
+
+		quantities := make(map[uint32]uint16, len(cart.Items))
+		for _, item := range cart.Items {
+			quantities[item.SKU] = item.Quantity
+		}
+		return quantities
+	}
+
+	// Setting the quantity of a missing item adds it
+	require.NoError(t, service.SetItemQuantity(ctx, 1, 1, 3, models.AnyVersion))
+	require.NoError(t, service.ChangeItemQuantity(ctx, 1, 2, 2, models.AnyVersion))
+	assert.Equal(t, map[uint32]uint16{1: 3, 2: 2}, quantities())
+
+	require.NoError(t, service.SetItemQuantity(ctx, 1, 1, 7, models.AnyVersion))
+	require.NoError(t, service.ChangeItemQuantity(ctx, 1, 2, 1, models.AnyVersion))
+	assert.Equal(t, map[uint32]uint16{1: 7, 2: 3}, quantities())
+
+	// Increases are checked against the limits and the stock
+	assert.ErrorIs(t, service.SetItemQuantity(ctx, 1, 1, 9, models.AnyVersion), models.ErrLimitExceeded)
+	loms.stock = 7
+	assert.ErrorIs(t, service.ChangeItemQuantity(ctx, 1, 1, 1, models.AnyVersion), ErrInsufficientStock)
+	assert.Equal(t, map[uint32]uint16{1: 7, 2: 3}, quantities())
+
+	// Decreases need neither the product nor the stock
+	stockCalls := loms.stockCalls
+	productCalls := productService.GetProductMock.Calls()
+	require.NoError(t, service.SetItemQuantity(ctx, 1, 1, 5, models.AnyVersion))
+	require.NoError(t, service.ChangeItemQuantity(ctx, 1, 2, -1, models.AnyVersion))
+	assert.Equal(t, stockCalls, loms.stockCalls)
+	assert.Len(t, productService.GetProductMock.Calls(), len(productCalls))
+	assert.Equal(t, map[uint32]uint16{1: 5, 2: 2}, quantities())
+
+	// Decrementing past zero and setting zero remove the item
+	require.NoError(t, service.ChangeItemQuantity(ctx, 1, 2, -5, models.AnyVersion))
+	assert.Equal(t, map[uint32]uint16{1: 5}, quantities())
+	require.NoError(t, service.SetItemQuantity(ctx, 1, 1, 0, models.AnyVersion))
+	assert.Equal(t, map[uint32]uint16{}, quantities())
+
+	// Removing a missing item changes nothing
+	require.NoError(t, service.ChangeItemQuantity(ctx, 1, 3, -1, models.AnyVersion))
+	require.NoError(t, service.SetItemQuantity(ctx, 1, 3, 0, models.AnyVersion))
+
+	// A stale version is rejected
+	require.NoError(t, service.SetItemQuantity(ctx, 1, 1, 1, models.AnyVersion))
+	cart, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.ErrorIs(t, service.SetItemQuantity(ctx, 1, 1, 2, cart.Version+1), models.ErrVersionConflict)
+	assert.ErrorIs(t, service.ChangeItemQuantity(ctx, 1, 1, -1, cart.Version+1), models.ErrVersionConflict)
+	require.NoError(t, service.ChangeItemQuantity(ctx, 1, 1, 1, cart.Version))
+	assert.Equal(t, map[uint32]uint16{1: 2}, quantities())
+}
+
+func TestCartService_AddItems(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			if sku == 404 {
+				return nil, errors.New("not found")
+			}
+			return &models.Product{SKU: sku, Name: "product", Price: rub(100)}, nil
+		})
+
+	loms := &fakeLOMS{stock: 5}
+	limits := models.CartLimits{MaxDistinctSKUs: 3}
+	service := NewCartService(inmemory.NewCartRepository(), productService, loms, nil, nil, nil, limits)
+
+	require.NoError(t, service.AddItem(ctx, 1, 1, 2, models.AnyVersion))
+	before, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+
+	results, err := service.AddItems(ctx, 1, []models.BatchItem{
+		{SKU: 1, Count: 3},
+		{SKU: 404, Count: 1},
+		{SKU: 2, Count: 4},
+		{SKU: 2, Count: 2},
+		{SKU: 3, Count: 1},
+		{SKU: 4, Count: 1},
+	}, before.Version)
+	require.NoError(t, err)
+
+	require.Len(t, results, 6)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, models.ErrProductNotFound)
+	assert.NoError(t, results[2].Err)
+	assert.ErrorIs(t, results[3].Err, ErrInsufficientStock)
+	assert.NoError(t, results[4].Err)
+	assert.ErrorIs(t, results[5].Err, models.ErrLimitExceeded)
+	assert.Equal(t, uint32(2), results[3].SKU)
+	assert.Equal(t, uint16(2), results[3].Count)
+
+	// Products are looked up once per SKU, stocks once per known product,
+	// besides the lookups of AddItem and GetCart
+	assert.Len(t, productService.GetProductMock.Calls(), 2+5)
+	assert.Equal(t, 1+4, loms.stockCalls)
+
+	after, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, before.Version+1, after.Version)
+	assert.Equal(t, models.ItemList{
+		{SKU: 1, Name: "product", Quantity: 5, Price: rub(100)},
+		{SKU: 2, Name: "product", Quantity: 4, Price: rub(100)},
+		{SKU: 3, Name: "product", Quantity: 1, Price: rub(100)},
+	}, after.Items)
+	assert.Equal(t, rub(1000), after.TotalPrice)
+
+	// A batch of failures leaves the cart as it is
+	results, err = service.AddItems(ctx, 1, []models.BatchItem{{SKU: 404, Count: 1}}, after.Version)
+	require.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, models.ErrProductNotFound)
+
+	// A stale version fails the whole batch
+	_, err = service.AddItems(ctx, 1, []models.BatchItem{{SKU: 1, Count: 1}}, before.Version)
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+	last, err := service.GetCart(ctx, 1, "")
+	require.NoError(t, err)
+	assert.Equal(t, after.Version, last.Version)
+}
+
+func TestCartService_AddItemsProductServiceUnavailable(t *testing.T) {
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Return(nil, &models.DependencyUnavailableError{Dependency: "product", RetryAfter: time.Second})
+
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, &fakeLOMS{stock: 10}, nil, nil, nil, models.CartLimits{})
+
+	_, err := service.AddItems(context.Background(), 1, []models.BatchItem{{SKU: 1, Count: 1}, {SKU: 2, Count: 1}}, models.AnyVersion)
+	assert.ErrorIs(t, err, models.ErrDependencyUnavailable)
+
+	_, err = service.GetCart(context.Background(), 1, "")
+	assert.ErrorIs(t, err, models.ErrCartNotFound)
+}
+
+func TestCartService_MergeGuestCart(t *testing.T) {
+	const guestID = -1
+
+	tests := []struct {
+		policy models.MergePolicy
+		want   models.ItemList
+	}{
+		{
+			policy: models.MergeSum,
+			want: models.ItemList{
+				{SKU: 1, Quantity: 5, Price: rub(100)},
+				{SKU: 2, Quantity: 6, Price: rub(100)},
+				{SKU: 3, Quantity: 1, Price: rub(100)},
+			},
+		},
+		{
+			policy: models.MergeMax,
+			want: models.ItemList{
+				{SKU: 1, Quantity: 3, Price: rub(100)},
+				{SKU: 2, Quantity: 6, Price: rub(100)},
+				{SKU: 3, Quantity: 1, Price: rub(100)},
+			},
+		},
+		{
+			policy: models.MergePreferGuest,
+			want: models.ItemList{
+				{SKU: 1, Quantity: 2, Price: rub(100)},
+				{SKU: 2, Quantity: 1, Price: rub(100)},
+				{SKU: 3, Quantity: 1, Price: rub(100)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			ctx := context.Background()
+
+			ctrl := minimock.NewController(t)
+			productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+				Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+					if sku == 404 {
+						return nil, errors.New("not found")
+					}
+					return &models.Product{SKU: sku, Price: rub(100)}, nil
+				})
+
+			repo := inmemory.NewCartRepository()
+			loms := &fakeLOMS{stock: 6}
+			service := NewCartService(repo, productService, loms, nil, nil, nil, models.CartLimits{})
+
+			require.NoError(t, service.AddItem(ctx, 1, 1, 3, models.AnyVersion))
+			require.NoError(t, service.AddItem(ctx, 1, 2, 6, models.AnyVersion))
+
+			require.NoError(t, service.AddItem(ctx, guestID, 1, 2, models.AnyVersion))
+			require.NoError(t, service.AddItem(ctx, guestID, 2, 1, models.AnyVersion))
+			require.NoError(t, service.AddItem(ctx, guestID, 3, 1, models.AnyVersion))
+			require.NoError(t, repo.UpdateCart(ctx, guestID, func(cart *models.Cart) error {
+				cart.Items = append(cart.Items, models.Item{SKU: 404, Quantity: 1, Price: rub(100)})
+				cart.Region = "RU"
+				return nil
+			}))
+
+			results, err := service.MergeGuestCart(ctx, guestID, 1, tt.policy, models.AnyVersion)
+			require.NoError(t, err)
+			require.Len(t, results, 4)
+			assert.NoError(t, results[0].Err)
+			if tt.policy == models.MergeSum {
+				// 6 + 1 is more than there is in stock
+				assert.ErrorIs(t, results[1].Err, ErrInsufficientStock)
+			} else {
+				assert.NoError(t, results[1].Err)
+			}
+			assert.NoError(t, results[2].Err)
+			assert.ErrorIs(t, results[3].Err, models.ErrProductNotFound)
+
+			user, err := repo.GetCart(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, user.Items)
+			assert.Equal(t, "RU", user.Region)
+
+			total := int64(0)
+			for _, item := range tt.want {
+				total += int64(item.Quantity) * 100
+			}
+			assert.Equal(t, rub(total), user.TotalPrice)
+
+			// The guest cart keeps only what failed, so merging again changes nothing
+			wantLeft := models.ItemList{{SKU: 404, Quantity: 1, Price: rub(100)}}
+			if tt.policy == models.MergeSum {
+				wantLeft = models.ItemList{{SKU: 2, Quantity: 1, Price: rub(100)}, {SKU: 404, Quantity: 1, Price: rub(100)}}
+			}
+			guest, err := repo.GetCart(ctx, guestID)
+			require.NoError(t, err)
+			assert.Equal(t, wantLeft, guest.Items)
+
+			results, err = service.MergeGuestCart(ctx, guestID, 1, tt.policy, models.AnyVersion)
+			require.NoError(t, err)
+			assert.Len(t, results, len(wantLeft))
+			again, err := repo.GetCart(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, user.Version, again.Version)
+		})
+	}
+}
+
+func TestCartService_MergeGuestCartConcurrent(t *testing.T) {
+	const (
+		guestID = -1
+		merges  = 10
+	)
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Price: rub(100)}, nil
+		})
+
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, &fakeLOMS{stock: 100}, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, guestID, 1, 2, models.AnyVersion))
+
+	var wg sync.WaitGroup
+	for range merges {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.MergeGuestCart(ctx, guestID, 1, models.MergeSum, models.AnyVersion)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Only one merge gets the items of the guest cart
+	user, err := repo.GetCart(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemList{{SKU: 1, Quantity: 2, Price: rub(100)}}, user.Items)
+
+	guest, err := repo.GetCart(ctx, guestID)
+	require.NoError(t, err)
+	assert.Empty(t, guest.Items)
+}
+
+func TestCartService_MergeGuestCartFailureRestoresGuestCart(t *testing.T) {
+	const guestID = -1
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	productService := mocks.NewProductServiceMock(ctrl).GetProductMock.
+		Set(func(_ context.Context, sku uint32) (*models.Product, error) {
+			return &models.Product{SKU: sku, Price: rub(100)}, nil
+		})
+
+	repo := inmemory.NewCartRepository()
+	service := NewCartService(repo, productService, &fakeLOMS{stock: 100}, nil, nil, nil, models.CartLimits{})
+	require.NoError(t, service.AddItem(ctx, guestID, 1, 2, models.AnyVersion))
+	require.NoError(t, service.AddItem(ctx, 1, 2, 1, models.AnyVersion))
+
+	_, err := service.MergeGuestCart(ctx, guestID, 1, models.MergeSum, 5)
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+	guest, err := repo.GetCart(ctx, guestID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ItemList{{SKU: 1, Quantity: 2, Price: rub(100)}}, guest.Items)
+	assert.Equal(t, rub(200), guest.TotalPrice)
+}
+
+func TestCartService_MergeGuestCartInvalid(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := minimock.NewController(t)
+	service := NewCartService(inmemory.NewCartRepository(), mocks.NewProductServiceMock(ctrl), &fakeLOMS{}, nil, nil, nil, models.CartLimits{})
+
+	// Nothing to merge without a guest cart
+	results, err := service.MergeGuestCart(ctx, -1, 1, models.MergeSum, models.AnyVersion)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = service.MergeGuestCart(ctx, -1, 1, "min", models.AnyVersion)
+	assert.ErrorIs(t, err, models.ErrInvalidMergePolicy)
+	_, err = service.MergeGuestCart(ctx, 2, 1, models.MergeSum, models.AnyVersion)
+	assert.Error(t, err)
+	_, err = service.MergeGuestCart(ctx, -1, -2, models.MergeSum, models.AnyVersion)
+	assert.Error(t, err)
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: fmt.Errorf("%w: sku 1", models.ErrProductNotFound), want: "product_not_found"},
+		{err: ErrInsufficientStock, want: "insufficient_stock"},
+		{err: &models.LimitExceededError{}, want: "limit_exceeded"},
+		{err: errors.New("boom"), want: "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, FailureReason(tt.err))
+		})
+	}
+}
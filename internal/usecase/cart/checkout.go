@@ -0,0 +1,156 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// Checkout creates an order from the cart at current prices less discounts
+// and removes the ordered items from the cart.
+// It runs as a saga: once the order exists in LOMS, any later failure,
+// including one to remove the ordered items, cancels the order so that no
+// reservation is left dangling and a retry does not order the items twice.
+func (s *CartService) Checkout(ctx context.Context, userID int64, expectedVersion uint64, confirmPriceChanges bool) (int64, error) {
+	// Get cart at current prices
+	cart, err := s.reprice(ctx, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrCartNotFound) {
+			return 0, models.ErrCartNotFound
+		}
+		return 0, err
+	}
+
+	// A caller expecting the current version has already seen the changed prices
+	if cart.PriceChanged() && !confirmPriceChanges && expectedVersion != cart.Version {
+		return 0, ErrPriceChanged
+	}
+
+	if err := cart.CheckVersion(expectedVersion); err != nil {
+		return 0, err
+	}
+
+	// Check if cart is empty
+	if len(cart.Items) == 0 {
+		return 0, ErrCartEmpty
+	}
+
+	// Products removed from the catalog cannot be ordered
+	for _, item := range cart.Items {
+		if item.Unavailable {
+			return 0, fmt.Errorf("%w: sku %d", models.ErrProductGone, item.SKU)
+		}
+	}
+
+	// The order is placed at the prices the customer pays
+	discounted, err := s.discount(ctx, cart, "")
+	if err != nil {
+		return 0, err
+	}
+
+	// Convert cart items to LOMS items
+	items := make([]ports.Item, len(discounted.Items))
+	for i, item := range discounted.Items {
+		price, err := item.FinalPrice()
+		if err != nil {
+			return 0, err
+		}
+
+		items[i] = ports.Item{
+			SKU:   item.SKU,
+			Count: item.Quantity, // Quantity is already uint16
+			Price: price,
+		}
+	}
+
+	var orderID int64
+	err = runSaga(ctx, []sagaStep{
+		{
+			name: "create order",
+			action: func(ctx context.Context) error {
+				var err error
+				orderID, err = s.lomsClient.CreateOrder(ctx, userID, items)
+				return err
+			},
+			compensate: func(ctx context.Context) error {
+				return s.lomsClient.CancelOrder(ctx, orderID)
+			},
+		},
+		{
+			name: "check order status",
+			action: func(ctx context.Context) error {
+				orderInfo, err := s.lomsClient.GetOrderInfo(ctx, orderID)
+				if err != nil {
+					return err
+				}
+
+				if orderInfo.Status == "failed" {
+					return ErrOrderFailed
+				}
+				return nil
+			},
+		},
+		{
+			// Items added while the order was being created are not part of
+			// it and must not be lost
+			name: "remove ordered items",
+			action: func(ctx context.Context) error {
+				return s.removeOrdered(ctx, userID, cart)
+			},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return orderID, nil
+}
+
+// removeOrdered removes the items and the coupon of ordered from the cart of
+// userID. Changes made to the cart while the order was being created are
+// kept: added items and quantities stay in the cart.
+// Price changes of the items that stay at the ordered prices are acknowledged.
+func (s *CartService) removeOrdered(ctx context.Context, userID int64, ordered *models.Cart) error {
+	orderedPrices := make(map[uint32]models.Money, len(ordered.Items))
+	for _, item := range ordered.Items {
+		orderedPrices[item.SKU] = item.Price
+	}
+
+	err := s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		changed := false
+		for _, item := range ordered.Items {
+			quantity := cart.Quantity(item.SKU)
+			if quantity == 0 {
+				continue
+			}
+
+			cart.SetQuantity(item.SKU, quantity-min(quantity, item.Quantity), item.Price)
+			changed = true
+		}
+
+		// The prices paid for the order are acknowledged
+		for i := range cart.Items {
+			if price, ok := orderedPrices[cart.Items[i].SKU]; ok && cart.Items[i].Price == price {
+				cart.Items[i].PreviousPrice = models.Money{}
+			}
+		}
+
+		if ordered.Coupon != "" && cart.Coupon == ordered.Coupon {
+			cart.Coupon = ""
+			changed = true
+		}
+
+		if !changed {
+			return errNoChanges
+		}
+		return cart.CalculateTotalPrice(s.converter(ctx))
+	})
+	if errors.Is(err, errNoChanges) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+package cart
+
+import (
+	"context"
+	"errors"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/usecase/promotion"
+)
+
+// ApplyCoupon applies a coupon to the user's cart, replacing the one applied
+// before. The coupon must activate a promotion; its discounts show once the
+// cart qualifies for it.
+func (s *CartService) ApplyCoupon(ctx context.Context, userID int64, code string, expectedVersion uint64) error {
+	if s.promotions == nil || !s.promotions.IsCoupon(code) {
+		return models.ErrCouponNotFound
+	}
+	code = promotion.NormalizeCoupon(code)
+
+	err := s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		if err := checkVersion(cart, expectedVersion); err != nil {
+			return err
+		}
+
+		if len(cart.Items) == 0 {
+			return models.ErrCartNotFound
+		}
+
+		if cart.Coupon == code {
+			return errNoChanges
+		}
+
+		cart.Coupon = code
+		return nil
+	})
+	if errors.Is(err, errNoChanges) {
+		return nil
+	}
+
+	return err
+}
+
+// RemoveCoupon removes the coupon from the user's cart
+func (s *CartService) RemoveCoupon(ctx context.Context, userID int64, expectedVersion uint64) error {
+	err := s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		if err := checkVersion(cart, expectedVersion); err != nil {
+			return err
+		}
+
+		if cart.Coupon == "" {
+			return errNoChanges
+		}
+
+		cart.Coupon = ""
+		return nil
+	})
+	if errors.Is(err, errNoChanges) {
+		return nil // Like RemoveItem, succeed if there is no coupon
+	}
+
+	return err
+}
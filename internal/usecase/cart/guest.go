@@ -0,0 +1,182 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"route256/cart/internal/domain/models"
+)
+
+// MergeGuestCart folds the guest cart into the user's cart, as on login.
+// Quantities of products in both carts are merged with policy, and increases
+// are checked against the cart limits and the stock like AddItem. Products
+// that fail the checks are reported in their result and left as the user had
+// them. The coupon and region of the guest cart are kept if the user's cart
+// has none.
+//
+// The guest cart is emptied before merging, so that concurrent merges of it
+// cannot add its products twice. Products that fail the checks, and all of
+// them if the merge fails, are put back into it.
+func (s *CartService) MergeGuestCart(
+	ctx context.Context,
+	guestID, userID int64,
+	policy models.MergePolicy,
+	expectedVersion uint64,
+) ([]models.BatchItemResult, error) {
+	if !models.IsGuestCart(guestID) || models.IsGuestCart(userID) {
+		return nil, fmt.Errorf("merge cart %d into %d: not a guest and a user cart", guestID, userID)
+	}
+	if _, err := models.ParseMergePolicy(string(policy)); err != nil {
+		return nil, err
+	}
+
+	guest, err := s.claimGuestCart(ctx, guestID)
+	if err != nil || guest == nil {
+		return nil, err
+	}
+
+	items := make([]models.BatchItem, len(guest.Items))
+	for i, item := range guest.Items {
+		items[i] = models.BatchItem{SKU: item.SKU, Count: item.Quantity}
+	}
+
+	var (
+		results     []models.BatchItemResult
+		couponTaken bool
+	)
+	err = func() error {
+		stocks, err := s.lookupStocks(ctx, items)
+		if err != nil {
+			return err
+		}
+
+		return s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+			if err := checkVersion(cart, expectedVersion); err != nil {
+				return err
+			}
+
+			results = make([]models.BatchItemResult, len(items))
+			couponTaken = false
+			changed := false
+			for i, item := range items {
+				results[i] = models.BatchItemResult{SKU: item.SKU, Count: item.Count}
+
+				current := uint64(cart.Quantity(item.SKU))
+				target := policy.Quantity(current, uint64(item.Count))
+				if target == current {
+					continue
+				}
+
+				var price models.Money
+				if target > current {
+					info := stocks[item.SKU]
+					if info.err != nil {
+						results[i].Err = info.err
+						continue
+					}
+					if err := s.limits.CheckQuantity(cart, item.SKU, target); err != nil {
+						results[i].Err = err
+						continue
+					}
+					if target > info.stock {
+						results[i].Err = ErrInsufficientStock
+						continue
+					}
+					price = info.product.Price
+				}
+
+				// Limits never allow more than math.MaxUint16
+				cart.SetQuantity(item.SKU, uint16(min(target, math.MaxUint16)), price)
+				changed = true
+			}
+
+			if cart.Coupon == "" && guest.Coupon != "" {
+				cart.Coupon = guest.Coupon
+				couponTaken = true
+				changed = true
+			}
+			if cart.Region == "" && guest.Region != "" {
+				cart.Region = guest.Region
+				changed = true
+			}
+
+			if !changed {
+				return errNoChanges
+			}
+
+			// Calculate total price
+			return cart.CalculateTotalPrice(s.converter(ctx))
+		})
+	}()
+	if err != nil && !errors.Is(err, errNoChanges) {
+		s.restoreGuestCart(ctx, guest, guest.Items, guest.Coupon)
+		return nil, err
+	}
+
+	var left models.ItemList
+	for i, result := range results {
+		if result.Err != nil {
+			left = append(left, guest.Items[i])
+		}
+	}
+	coupon := guest.Coupon
+	if couponTaken {
+		coupon = ""
+	}
+	s.restoreGuestCart(ctx, guest, left, coupon)
+
+	return results, nil
+}
+
+// claimGuestCart empties the guest cart and returns what it held, or nil if
+// there was nothing to merge
+func (s *CartService) claimGuestCart(ctx context.Context, guestID int64) (*models.Cart, error) {
+	var claimed *models.Cart
+	err := s.repo.UpdateCart(ctx, guestID, func(cart *models.Cart) error {
+		// The region is copied, not moved, so it alone is nothing to merge
+		if len(cart.Items) == 0 && cart.Coupon == "" {
+			return errNoChanges
+		}
+
+		claimed = cart.Clone()
+		cart.Clear()
+		return nil
+	})
+	if errors.Is(err, errNoChanges) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// restoreGuestCart puts items and the coupon that were not merged back into
+// the guest cart, even if ctx is cancelled. It logs failures: the merge is
+// done either way.
+func (s *CartService) restoreGuestCart(ctx context.Context, guest *models.Cart, items models.ItemList, coupon string) {
+	if len(items) == 0 && coupon == "" {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	err := s.repo.UpdateCart(ctx, guest.UserID, func(cart *models.Cart) error {
+		for _, item := range items {
+			cart.AddItem(item)
+		}
+		if cart.Coupon == "" {
+			cart.Coupon = coupon
+		}
+
+		// Calculate total price
+		return cart.CalculateTotalPrice(s.converter(ctx))
+	})
+	if err != nil {
+		log.Printf("Failed to restore %d items of guest cart %d: %v", len(items), guest.UserID, err)
+	}
+}
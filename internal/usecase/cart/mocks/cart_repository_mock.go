@@ -3,9 +3,11 @@
 package mocks
 
 import (
+	"context"
 	"route256/cart/internal/domain/models"
 	"sync"
 	mm_atomic "sync/atomic"
+	"time"
 	mm_time "time"
 
 	"github.com/gojuno/minimock/v3"
@@ -16,26 +18,54 @@ type CartRepositoryMock struct {
 	t          minimock.Tester
 	finishOnce sync.Once
 
-	funcCreateCart          func(cart *models.Cart) (err error)
+	funcCreateCart          func(ctx context.Context, cart *models.Cart) (err error)
 	funcCreateCartOrigin    string
-	inspectFuncCreateCart   func(cart *models.Cart)
+	inspectFuncCreateCart   func(ctx context.Context, cart *models.Cart)
 	afterCreateCartCounter  uint64
 	beforeCreateCartCounter uint64
 	CreateCartMock          mCartRepositoryMockCreateCart
 
-	funcGetCart          func(userID int64) (cp1 *models.Cart, err error)
+	funcDeleteExpired          func(ctx context.Context, cutoff time.Time, limit int) (i1 int, err error)
+	funcDeleteExpiredOrigin    string
+	inspectFuncDeleteExpired   func(ctx context.Context, cutoff time.Time, limit int)
+	afterDeleteExpiredCounter  uint64
+	beforeDeleteExpiredCounter uint64
+	DeleteExpiredMock          mCartRepositoryMockDeleteExpired
+
+	funcGetCart          func(ctx context.Context, userID int64) (cp1 *models.Cart, err error)
 	funcGetCartOrigin    string
-	inspectFuncGetCart   func(userID int64)
+	inspectFuncGetCart   func(ctx context.Context, userID int64)
 	afterGetCartCounter  uint64
 	beforeGetCartCounter uint64
 	GetCartMock          mCartRepositoryMockGetCart
 
-	funcSaveCart          func(cart *models.Cart) (err error)
+	funcListIdle          func(ctx context.Context, cutoff time.Time, after int64, limit int) (cpa1 []*models.Cart, err error)
+	funcListIdleOrigin    string
+	inspectFuncListIdle   func(ctx context.Context, cutoff time.Time, after int64, limit int)
+	afterListIdleCounter  uint64
+	beforeListIdleCounter uint64
+	ListIdleMock          mCartRepositoryMockListIdle
+
+	funcRefreshCart          func(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error)
+	funcRefreshCartOrigin    string
+	inspectFuncRefreshCart   func(ctx context.Context, userID int64, update func(cart *models.Cart) error)
+	afterRefreshCartCounter  uint64
+	beforeRefreshCartCounter uint64
+	RefreshCartMock          mCartRepositoryMockRefreshCart
+
+	funcSaveCart          func(ctx context.Context, cart *models.Cart) (err error)
 	funcSaveCartOrigin    string
-	inspectFuncSaveCart   func(cart *models.Cart)
+	inspectFuncSaveCart   func(ctx context.Context, cart *models.Cart)
 	afterSaveCartCounter  uint64
 	beforeSaveCartCounter uint64
 	SaveCartMock          mCartRepositoryMockSaveCart
+
+	funcUpdateCart          func(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error)
+	funcUpdateCartOrigin    string
+	inspectFuncUpdateCart   func(ctx context.Context, userID int64, update func(cart *models.Cart) error)
+	afterUpdateCartCounter  uint64
+	beforeUpdateCartCounter uint64
+	UpdateCartMock          mCartRepositoryMockUpdateCart
 }
 
 // NewCartRepositoryMock returns a mock for mm_cart.CartRepository
@@ -49,12 +79,24 @@ func NewCartRepositoryMock(t minimock.Tester) *CartRepositoryMock {
 	m.CreateCartMock = mCartRepositoryMockCreateCart{mock: m}
 	m.CreateCartMock.callArgs = []*CartRepositoryMockCreateCartParams{}
 
+	m.DeleteExpiredMock = mCartRepositoryMockDeleteExpired{mock: m}
+	m.DeleteExpiredMock.callArgs = []*CartRepositoryMockDeleteExpiredParams{}
+
 	m.GetCartMock = mCartRepositoryMockGetCart{mock: m}
 	m.GetCartMock.callArgs = []*CartRepositoryMockGetCartParams{}
 
+	m.ListIdleMock = mCartRepositoryMockListIdle{mock: m}
+	m.ListIdleMock.callArgs = []*CartRepositoryMockListIdleParams{}
+
+	m.RefreshCartMock = mCartRepositoryMockRefreshCart{mock: m}
+	m.RefreshCartMock.callArgs = []*CartRepositoryMockRefreshCartParams{}
+
 	m.SaveCartMock = mCartRepositoryMockSaveCart{mock: m}
 	m.SaveCartMock.callArgs = []*CartRepositoryMockSaveCartParams{}
 
+	m.UpdateCartMock = mCartRepositoryMockUpdateCart{mock: m}
+	m.UpdateCartMock.callArgs = []*CartRepositoryMockUpdateCartParams{}
+
 	t.Cleanup(m.MinimockFinish)
 
 	return m
@@ -86,11 +128,13 @@ type CartRepositoryMockCreateCartExpectation struct {
 
 // CartRepositoryMockCreateCartParams contains parameters of the CartRepository.CreateCart
 type CartRepositoryMockCreateCartParams struct {
+	ctx  context.Context
 	cart *models.Cart
 }
 
 // CartRepositoryMockCreateCartParamPtrs contains pointers to parameters of the CartRepository.CreateCart
 type CartRepositoryMockCreateCartParamPtrs struct {
+	ctx  *context.Context
 	cart **models.Cart
 }
 
@@ -102,6 +146,7 @@ type CartRepositoryMockCreateCartResults struct {
 // CartRepositoryMockCreateCartOrigins contains origins of expectations of the CartRepository.CreateCart
 type CartRepositoryMockCreateCartExpectationOrigins struct {
 	origin     string
+	originCtx  string
 	originCart string
 }
 
@@ -116,7 +161,7 @@ func (mmCreateCart *mCartRepositoryMockCreateCart) Optional() *mCartRepositoryMo
 }
 
 // Expect sets up expected params for CartRepository.CreateCart
-func (mmCreateCart *mCartRepositoryMockCreateCart) Expect(cart *models.Cart) *mCartRepositoryMockCreateCart {
+func (mmCreateCart *mCartRepositoryMockCreateCart) Expect(ctx context.Context, cart *models.Cart) *mCartRepositoryMockCreateCart {
 	if mmCreateCart.mock.funcCreateCart != nil {
 		mmCreateCart.mock.t.Fatalf("CartRepositoryMock.CreateCart mock is already set by Set")
 	}
@@ -129,7 +174,7 @@ func (mmCreateCart *mCartRepositoryMockCreateCart) Expect(cart *models.Cart) *mC
 		mmCreateCart.mock.t.Fatalf("CartRepositoryMock.CreateCart mock is already set by ExpectParams functions")
 	}
 
-	mmCreateCart.defaultExpectation.params = &CartRepositoryMockCreateCartParams{cart}
+	mmCreateCart.defaultExpectation.params = &CartRepositoryMockCreateCartParams{ctx, cart}
 	mmCreateCart.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmCreateCart.expectations {
 		if minimock.Equal(e.params, mmCreateCart.defaultExpectation.params) {
@@ -140,8 +185,31 @@ func (mmCreateCart *mCartRepositoryMockCreateCart) Expect(cart *models.Cart) *mC
 	return mmCreateCart
 }
 
-// ExpectCartParam1 sets up expected param cart for CartRepository.CreateCart
-func (mmCreateCart *mCartRepositoryMockCreateCart) ExpectCartParam1(cart *models.Cart) *mCartRepositoryMockCreateCart {
+// ExpectCtxParam1 sets up expected param ctx for CartRepository.CreateCart
+func (mmCreateCart *mCartRepositoryMockCreateCart) ExpectCtxParam1(ctx context.Context) *mCartRepositoryMockCreateCart {
+	if mmCreateCart.mock.funcCreateCart != nil {
+		mmCreateCart.mock.t.Fatalf("CartRepositoryMock.CreateCart mock is already set by Set")
+	}
+
+	if mmCreateCart.defaultExpectation == nil {
+		mmCreateCart.defaultExpectation = &CartRepositoryMockCreateCartExpectation{}
+	}
+
+	if mmCreateCart.defaultExpectation.params != nil {
+		mmCreateCart.mock.t.Fatalf("CartRepositoryMock.CreateCart mock is already set by Expect")
+	}
+
+	if mmCreateCart.defaultExpectation.paramPtrs == nil {
+		mmCreateCart.defaultExpectation.paramPtrs = &CartRepositoryMockCreateCartParamPtrs{}
+	}
+	mmCreateCart.defaultExpectation.paramPtrs.ctx = &ctx
+	mmCreateCart.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmCreateCart
+}
+
+// ExpectCartParam2 sets up expected param cart for CartRepository.CreateCart
+func (mmCreateCart *mCartRepositoryMockCreateCart) ExpectCartParam2(cart *models.Cart) *mCartRepositoryMockCreateCart {
 	if mmCreateCart.mock.funcCreateCart != nil {
 		mmCreateCart.mock.t.Fatalf("CartRepositoryMock.CreateCart mock is already set by Set")
 	}
@@ -164,7 +232,7 @@ func (mmCreateCart *mCartRepositoryMockCreateCart) ExpectCartParam1(cart *models
 }
 
 // Inspect accepts an inspector function that has same arguments as the CartRepository.CreateCart
-func (mmCreateCart *mCartRepositoryMockCreateCart) Inspect(f func(cart *models.Cart)) *mCartRepositoryMockCreateCart {
+func (mmCreateCart *mCartRepositoryMockCreateCart) Inspect(f func(ctx context.Context, cart *models.Cart)) *mCartRepositoryMockCreateCart {
 	if mmCreateCart.mock.inspectFuncCreateCart != nil {
 		mmCreateCart.mock.t.Fatalf("Inspect function is already set for CartRepositoryMock.CreateCart")
 	}
@@ -189,7 +257,7 @@ func (mmCreateCart *mCartRepositoryMockCreateCart) Return(err error) *CartReposi
 }
 
 // Set uses given function f to mock the CartRepository.CreateCart method
-func (mmCreateCart *mCartRepositoryMockCreateCart) Set(f func(cart *models.Cart) (err error)) *CartRepositoryMock {
+func (mmCreateCart *mCartRepositoryMockCreateCart) Set(f func(ctx context.Context, cart *models.Cart) (err error)) *CartRepositoryMock {
 	if mmCreateCart.defaultExpectation != nil {
 		mmCreateCart.mock.t.Fatalf("Default expectation is already set for the CartRepository.CreateCart method")
 	}
@@ -205,14 +273,14 @@ func (mmCreateCart *mCartRepositoryMockCreateCart) Set(f func(cart *models.Cart)
 
 // When sets expectation for the CartRepository.CreateCart which will trigger the result defined by the following
 // Then helper
-func (mmCreateCart *mCartRepositoryMockCreateCart) When(cart *models.Cart) *CartRepositoryMockCreateCartExpectation {
+func (mmCreateCart *mCartRepositoryMockCreateCart) When(ctx context.Context, cart *models.Cart) *CartRepositoryMockCreateCartExpectation {
 	if mmCreateCart.mock.funcCreateCart != nil {
 		mmCreateCart.mock.t.Fatalf("CartRepositoryMock.CreateCart mock is already set by Set")
 	}
 
 	expectation := &CartRepositoryMockCreateCartExpectation{
 		mock:               mmCreateCart.mock,
-		params:             &CartRepositoryMockCreateCartParams{cart},
+		params:             &CartRepositoryMockCreateCartParams{ctx, cart},
 		expectationOrigins: CartRepositoryMockCreateCartExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmCreateCart.expectations = append(mmCreateCart.expectations, expectation)
@@ -247,17 +315,17 @@ func (mmCreateCart *mCartRepositoryMockCreateCart) invocationsDone() bool {
 }
 
 // CreateCart implements mm_cart.CartRepository
-func (mmCreateCart *CartRepositoryMock) CreateCart(cart *models.Cart) (err error) {
+func (mmCreateCart *CartRepositoryMock) CreateCart(ctx context.Context, cart *models.Cart) (err error) {
 	mm_atomic.AddUint64(&mmCreateCart.beforeCreateCartCounter, 1)
 	defer mm_atomic.AddUint64(&mmCreateCart.afterCreateCartCounter, 1)
 
 	mmCreateCart.t.Helper()
 
 	if mmCreateCart.inspectFuncCreateCart != nil {
-		mmCreateCart.inspectFuncCreateCart(cart)
+		mmCreateCart.inspectFuncCreateCart(ctx, cart)
 	}
 
-	mm_params := CartRepositoryMockCreateCartParams{cart}
+	mm_params := CartRepositoryMockCreateCartParams{ctx, cart}
 
 	// Record call args
 	mmCreateCart.CreateCartMock.mutex.Lock()
@@ -276,10 +344,15 @@ func (mmCreateCart *CartRepositoryMock) CreateCart(cart *models.Cart) (err error
 		mm_want := mmCreateCart.CreateCartMock.defaultExpectation.params
 		mm_want_ptrs := mmCreateCart.CreateCartMock.defaultExpectation.paramPtrs
 
-		mm_got := CartRepositoryMockCreateCartParams{cart}
+		mm_got := CartRepositoryMockCreateCartParams{ctx, cart}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmCreateCart.t.Errorf("CartRepositoryMock.CreateCart got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmCreateCart.CreateCartMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.cart != nil && !minimock.Equal(*mm_want_ptrs.cart, mm_got.cart) {
 				mmCreateCart.t.Errorf("CartRepositoryMock.CreateCart got unexpected parameter cart, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmCreateCart.CreateCartMock.defaultExpectation.expectationOrigins.originCart, *mm_want_ptrs.cart, mm_got.cart, minimock.Diff(*mm_want_ptrs.cart, mm_got.cart))
@@ -297,9 +370,9 @@ func (mmCreateCart *CartRepositoryMock) CreateCart(cart *models.Cart) (err error
 		return (*mm_results).err
 	}
 	if mmCreateCart.funcCreateCart != nil {
-		return mmCreateCart.funcCreateCart(cart)
+		return mmCreateCart.funcCreateCart(ctx, cart)
 	}
-	mmCreateCart.t.Fatalf("Unexpected call to CartRepositoryMock.CreateCart. %v", cart)
+	mmCreateCart.t.Fatalf("Unexpected call to CartRepositoryMock.CreateCart. %v %v", ctx, cart)
 	return
 }
 
@@ -371,6 +444,380 @@ func (m *CartRepositoryMock) MinimockCreateCartInspect() {
 	}
 }
 
+type mCartRepositoryMockDeleteExpired struct {
+	optional           bool
+	mock               *CartRepositoryMock
+	defaultExpectation *CartRepositoryMockDeleteExpiredExpectation
+	expectations       []*CartRepositoryMockDeleteExpiredExpectation
+
+	callArgs []*CartRepositoryMockDeleteExpiredParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// CartRepositoryMockDeleteExpiredExpectation specifies expectation struct of the CartRepository.DeleteExpired
+type CartRepositoryMockDeleteExpiredExpectation struct {
+	mock               *CartRepositoryMock
+	params             *CartRepositoryMockDeleteExpiredParams
+	paramPtrs          *CartRepositoryMockDeleteExpiredParamPtrs
+	expectationOrigins CartRepositoryMockDeleteExpiredExpectationOrigins
+	results            *CartRepositoryMockDeleteExpiredResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// CartRepositoryMockDeleteExpiredParams contains parameters of the CartRepository.DeleteExpired
+type CartRepositoryMockDeleteExpiredParams struct {
+	ctx    context.Context
+	cutoff time.Time
+	limit  int
+}
+
+// CartRepositoryMockDeleteExpiredParamPtrs contains pointers to parameters of the CartRepository.DeleteExpired
+type CartRepositoryMockDeleteExpiredParamPtrs struct {
+	ctx    *context.Context
+	cutoff *time.Time
+	limit  *int
+}
+
+// CartRepositoryMockDeleteExpiredResults contains results of the CartRepository.DeleteExpired
+type CartRepositoryMockDeleteExpiredResults struct {
+	i1  int
+	err error
+}
+
+// CartRepositoryMockDeleteExpiredOrigins contains origins of expectations of the CartRepository.DeleteExpired
+type CartRepositoryMockDeleteExpiredExpectationOrigins struct {
+	origin       string
+	originCtx    string
+	originCutoff string
+	originLimit  string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) Optional() *mCartRepositoryMockDeleteExpired {
+	mmDeleteExpired.optional = true
+	return mmDeleteExpired
+}
+
+// Expect sets up expected params for CartRepository.DeleteExpired
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) Expect(ctx context.Context, cutoff time.Time, limit int) *mCartRepositoryMockDeleteExpired {
+	if mmDeleteExpired.mock.funcDeleteExpired != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Set")
+	}
+
+	if mmDeleteExpired.defaultExpectation == nil {
+		mmDeleteExpired.defaultExpectation = &CartRepositoryMockDeleteExpiredExpectation{}
+	}
+
+	if mmDeleteExpired.defaultExpectation.paramPtrs != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by ExpectParams functions")
+	}
+
+	mmDeleteExpired.defaultExpectation.params = &CartRepositoryMockDeleteExpiredParams{ctx, cutoff, limit}
+	mmDeleteExpired.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmDeleteExpired.expectations {
+		if minimock.Equal(e.params, mmDeleteExpired.defaultExpectation.params) {
+			mmDeleteExpired.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmDeleteExpired.defaultExpectation.params)
+		}
+	}
+
+	return mmDeleteExpired
+}
+
+// ExpectCtxParam1 sets up expected param ctx for CartRepository.DeleteExpired
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) ExpectCtxParam1(ctx context.Context) *mCartRepositoryMockDeleteExpired {
+	if mmDeleteExpired.mock.funcDeleteExpired != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Set")
+	}
+
+	if mmDeleteExpired.defaultExpectation == nil {
+		mmDeleteExpired.defaultExpectation = &CartRepositoryMockDeleteExpiredExpectation{}
+	}
+
+	if mmDeleteExpired.defaultExpectation.params != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Expect")
+	}
+
+	if mmDeleteExpired.defaultExpectation.paramPtrs == nil {
+		mmDeleteExpired.defaultExpectation.paramPtrs = &CartRepositoryMockDeleteExpiredParamPtrs{}
+	}
+	mmDeleteExpired.defaultExpectation.paramPtrs.ctx = &ctx
+	mmDeleteExpired.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmDeleteExpired
+}
+
+// ExpectCutoffParam2 sets up expected param cutoff for CartRepository.DeleteExpired
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) ExpectCutoffParam2(cutoff time.Time) *mCartRepositoryMockDeleteExpired {
+	if mmDeleteExpired.mock.funcDeleteExpired != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Set")
+	}
+
+	if mmDeleteExpired.defaultExpectation == nil {
+		mmDeleteExpired.defaultExpectation = &CartRepositoryMockDeleteExpiredExpectation{}
+	}
+
+	if mmDeleteExpired.defaultExpectation.params != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Expect")
+	}
+
+	if mmDeleteExpired.defaultExpectation.paramPtrs == nil {
+		mmDeleteExpired.defaultExpectation.paramPtrs = &CartRepositoryMockDeleteExpiredParamPtrs{}
+	}
+	mmDeleteExpired.defaultExpectation.paramPtrs.cutoff = &cutoff
+	mmDeleteExpired.defaultExpectation.expectationOrigins.originCutoff = minimock.CallerInfo(1)
+
+	return mmDeleteExpired
+}
+
+// ExpectLimitParam3 sets up expected param limit for CartRepository.DeleteExpired
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) ExpectLimitParam3(limit int) *mCartRepositoryMockDeleteExpired {
+	if mmDeleteExpired.mock.funcDeleteExpired != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Set")
+	}
+
+	if mmDeleteExpired.defaultExpectation == nil {
+		mmDeleteExpired.defaultExpectation = &CartRepositoryMockDeleteExpiredExpectation{}
+	}
+
+	if mmDeleteExpired.defaultExpectation.params != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Expect")
+	}
+
+	if mmDeleteExpired.defaultExpectation.paramPtrs == nil {
+		mmDeleteExpired.defaultExpectation.paramPtrs = &CartRepositoryMockDeleteExpiredParamPtrs{}
+	}
+	mmDeleteExpired.defaultExpectation.paramPtrs.limit = &limit
+	mmDeleteExpired.defaultExpectation.expectationOrigins.originLimit = minimock.CallerInfo(1)
+
+	return mmDeleteExpired
+}
+
+// Inspect accepts an inspector function that has same arguments as the CartRepository.DeleteExpired
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) Inspect(f func(ctx context.Context, cutoff time.Time, limit int)) *mCartRepositoryMockDeleteExpired {
+	if mmDeleteExpired.mock.inspectFuncDeleteExpired != nil {
+		mmDeleteExpired.mock.t.Fatalf("Inspect function is already set for CartRepositoryMock.DeleteExpired")
+	}
+
+	mmDeleteExpired.mock.inspectFuncDeleteExpired = f
+
+	return mmDeleteExpired
+}
+
+// Return sets up results that will be returned by CartRepository.DeleteExpired
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) Return(i1 int, err error) *CartRepositoryMock {
+	if mmDeleteExpired.mock.funcDeleteExpired != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Set")
+	}
+
+	if mmDeleteExpired.defaultExpectation == nil {
+		mmDeleteExpired.defaultExpectation = &CartRepositoryMockDeleteExpiredExpectation{mock: mmDeleteExpired.mock}
+	}
+	mmDeleteExpired.defaultExpectation.results = &CartRepositoryMockDeleteExpiredResults{i1, err}
+	mmDeleteExpired.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmDeleteExpired.mock
+}
+
+// Set uses given function f to mock the CartRepository.DeleteExpired method
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) Set(f func(ctx context.Context, cutoff time.Time, limit int) (i1 int, err error)) *CartRepositoryMock {
+	if mmDeleteExpired.defaultExpectation != nil {
+		mmDeleteExpired.mock.t.Fatalf("Default expectation is already set for the CartRepository.DeleteExpired method")
+	}
+
+	if len(mmDeleteExpired.expectations) > 0 {
+		mmDeleteExpired.mock.t.Fatalf("Some expectations are already set for the CartRepository.DeleteExpired method")
+	}
+
+	mmDeleteExpired.mock.funcDeleteExpired = f
+	mmDeleteExpired.mock.funcDeleteExpiredOrigin = minimock.CallerInfo(1)
+	return mmDeleteExpired.mock
+}
+
+// When sets expectation for the CartRepository.DeleteExpired which will trigger the result defined by the following
+// Then helper
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) When(ctx context.Context, cutoff time.Time, limit int) *CartRepositoryMockDeleteExpiredExpectation {
+	if mmDeleteExpired.mock.funcDeleteExpired != nil {
+		mmDeleteExpired.mock.t.Fatalf("CartRepositoryMock.DeleteExpired mock is already set by Set")
+	}
+
+	expectation := &CartRepositoryMockDeleteExpiredExpectation{
+		mock:               mmDeleteExpired.mock,
+		params:             &CartRepositoryMockDeleteExpiredParams{ctx, cutoff, limit},
+		expectationOrigins: CartRepositoryMockDeleteExpiredExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmDeleteExpired.expectations = append(mmDeleteExpired.expectations, expectation)
+	return expectation
+}
+
+// Then sets up CartRepository.DeleteExpired return parameters for the expectation previously defined by the When method
+func (e *CartRepositoryMockDeleteExpiredExpectation) Then(i1 int, err error) *CartRepositoryMock {
+	e.results = &CartRepositoryMockDeleteExpiredResults{i1, err}
+	return e.mock
+}
+
+// Times sets number of times CartRepository.DeleteExpired should be invoked
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) Times(n uint64) *mCartRepositoryMockDeleteExpired {
+	if n == 0 {
+		mmDeleteExpired.mock.t.Fatalf("Times of CartRepositoryMock.DeleteExpired mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmDeleteExpired.expectedInvocations, n)
+	mmDeleteExpired.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmDeleteExpired
+}
+
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) invocationsDone() bool {
+	if len(mmDeleteExpired.expectations) == 0 && mmDeleteExpired.defaultExpectation == nil && mmDeleteExpired.mock.funcDeleteExpired == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmDeleteExpired.mock.afterDeleteExpiredCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmDeleteExpired.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// DeleteExpired implements mm_cart.CartRepository
+func (mmDeleteExpired *CartRepositoryMock) DeleteExpired(ctx context.Context, cutoff time.Time, limit int) (i1 int, err error) {
+	mm_atomic.AddUint64(&mmDeleteExpired.beforeDeleteExpiredCounter, 1)
+	defer mm_atomic.AddUint64(&mmDeleteExpired.afterDeleteExpiredCounter, 1)
+
+	mmDeleteExpired.t.Helper()
+
+	if mmDeleteExpired.inspectFuncDeleteExpired != nil {
+		mmDeleteExpired.inspectFuncDeleteExpired(ctx, cutoff, limit)
+	}
+
+	mm_params := CartRepositoryMockDeleteExpiredParams{ctx, cutoff, limit}
+
+	// Record call args
+	mmDeleteExpired.DeleteExpiredMock.mutex.Lock()
+	mmDeleteExpired.DeleteExpiredMock.callArgs = append(mmDeleteExpired.DeleteExpiredMock.callArgs, &mm_params)
+	mmDeleteExpired.DeleteExpiredMock.mutex.Unlock()
+
+	for _, e := range mmDeleteExpired.DeleteExpiredMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.i1, e.results.err
+		}
+	}
+
+	if mmDeleteExpired.DeleteExpiredMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmDeleteExpired.DeleteExpiredMock.defaultExpectation.Counter, 1)
+		mm_want := mmDeleteExpired.DeleteExpiredMock.defaultExpectation.params
+		mm_want_ptrs := mmDeleteExpired.DeleteExpiredMock.defaultExpectation.paramPtrs
+
+		mm_got := CartRepositoryMockDeleteExpiredParams{ctx, cutoff, limit}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmDeleteExpired.t.Errorf("CartRepositoryMock.DeleteExpired got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDeleteExpired.DeleteExpiredMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.cutoff != nil && !minimock.Equal(*mm_want_ptrs.cutoff, mm_got.cutoff) {
+				mmDeleteExpired.t.Errorf("CartRepositoryMock.DeleteExpired got unexpected parameter cutoff, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDeleteExpired.DeleteExpiredMock.defaultExpectation.expectationOrigins.originCutoff, *mm_want_ptrs.cutoff, mm_got.cutoff, minimock.Diff(*mm_want_ptrs.cutoff, mm_got.cutoff))
+			}
+
+			if mm_want_ptrs.limit != nil && !minimock.Equal(*mm_want_ptrs.limit, mm_got.limit) {
+				mmDeleteExpired.t.Errorf("CartRepositoryMock.DeleteExpired got unexpected parameter limit, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmDeleteExpired.DeleteExpiredMock.defaultExpectation.expectationOrigins.originLimit, *mm_want_ptrs.limit, mm_got.limit, minimock.Diff(*mm_want_ptrs.limit, mm_got.limit))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmDeleteExpired.t.Errorf("CartRepositoryMock.DeleteExpired got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmDeleteExpired.DeleteExpiredMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmDeleteExpired.DeleteExpiredMock.defaultExpectation.results
+		if mm_results == nil {
+			mmDeleteExpired.t.Fatal("No results are set for the CartRepositoryMock.DeleteExpired")
+		}
+		return (*mm_results).i1, (*mm_results).err
+	}
+	if mmDeleteExpired.funcDeleteExpired != nil {
+		return mmDeleteExpired.funcDeleteExpired(ctx, cutoff, limit)
+	}
+	mmDeleteExpired.t.Fatalf("Unexpected call to CartRepositoryMock.DeleteExpired. %v %v %v", ctx, cutoff, limit)
+	return
+}
+
+// DeleteExpiredAfterCounter returns a count of finished CartRepositoryMock.DeleteExpired invocations
+func (mmDeleteExpired *CartRepositoryMock) DeleteExpiredAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteExpired.afterDeleteExpiredCounter)
+}
+
+// DeleteExpiredBeforeCounter returns a count of CartRepositoryMock.DeleteExpired invocations
+func (mmDeleteExpired *CartRepositoryMock) DeleteExpiredBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmDeleteExpired.beforeDeleteExpiredCounter)
+}
+
+// Calls returns a list of arguments used in each call to CartRepositoryMock.DeleteExpired.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmDeleteExpired *mCartRepositoryMockDeleteExpired) Calls() []*CartRepositoryMockDeleteExpiredParams {
+	mmDeleteExpired.mutex.RLock()
+
+	argCopy := make([]*CartRepositoryMockDeleteExpiredParams, len(mmDeleteExpired.callArgs))
+	copy(argCopy, mmDeleteExpired.callArgs)
+
+	mmDeleteExpired.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockDeleteExpiredDone returns true if the count of the DeleteExpired invocations corresponds
+// the number of defined expectations
+func (m *CartRepositoryMock) MinimockDeleteExpiredDone() bool {
+	if m.DeleteExpiredMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.DeleteExpiredMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.DeleteExpiredMock.invocationsDone()
+}
+
+// MinimockDeleteExpiredInspect logs each unmet expectation
+func (m *CartRepositoryMock) MinimockDeleteExpiredInspect() {
+	for _, e := range m.DeleteExpiredMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to CartRepositoryMock.DeleteExpired at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterDeleteExpiredCounter := mm_atomic.LoadUint64(&m.afterDeleteExpiredCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.DeleteExpiredMock.defaultExpectation != nil && afterDeleteExpiredCounter < 1 {
+		if m.DeleteExpiredMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to CartRepositoryMock.DeleteExpired at\n%s", m.DeleteExpiredMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to CartRepositoryMock.DeleteExpired at\n%s with params: %#v", m.DeleteExpiredMock.defaultExpectation.expectationOrigins.origin, *m.DeleteExpiredMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcDeleteExpired != nil && afterDeleteExpiredCounter < 1 {
+		m.t.Errorf("Expected call to CartRepositoryMock.DeleteExpired at\n%s", m.funcDeleteExpiredOrigin)
+	}
+
+	if !m.DeleteExpiredMock.invocationsDone() && afterDeleteExpiredCounter > 0 {
+		m.t.Errorf("Expected %d calls to CartRepositoryMock.DeleteExpired at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.DeleteExpiredMock.expectedInvocations), m.DeleteExpiredMock.expectedInvocationsOrigin, afterDeleteExpiredCounter)
+	}
+}
+
 type mCartRepositoryMockGetCart struct {
 	optional           bool
 	mock               *CartRepositoryMock
@@ -397,11 +844,13 @@ type CartRepositoryMockGetCartExpectation struct {
 
 // CartRepositoryMockGetCartParams contains parameters of the CartRepository.GetCart
 type CartRepositoryMockGetCartParams struct {
+	ctx    context.Context
 	userID int64
 }
 
 // CartRepositoryMockGetCartParamPtrs contains pointers to parameters of the CartRepository.GetCart
 type CartRepositoryMockGetCartParamPtrs struct {
+	ctx    *context.Context
 	userID *int64
 }
 
@@ -414,6 +863,7 @@ type CartRepositoryMockGetCartResults struct {
 // CartRepositoryMockGetCartOrigins contains origins of expectations of the CartRepository.GetCart
 type CartRepositoryMockGetCartExpectationOrigins struct {
 	origin       string
+	originCtx    string
 	originUserID string
 }
 
@@ -428,7 +878,7 @@ func (mmGetCart *mCartRepositoryMockGetCart) Optional() *mCartRepositoryMockGetC
 }
 
 // Expect sets up expected params for CartRepository.GetCart
-func (mmGetCart *mCartRepositoryMockGetCart) Expect(userID int64) *mCartRepositoryMockGetCart {
+func (mmGetCart *mCartRepositoryMockGetCart) Expect(ctx context.Context, userID int64) *mCartRepositoryMockGetCart {
 	if mmGetCart.mock.funcGetCart != nil {
 		mmGetCart.mock.t.Fatalf("CartRepositoryMock.GetCart mock is already set by Set")
 	}
@@ -441,7 +891,7 @@ func (mmGetCart *mCartRepositoryMockGetCart) Expect(userID int64) *mCartReposito
 		mmGetCart.mock.t.Fatalf("CartRepositoryMock.GetCart mock is already set by ExpectParams functions")
 	}
 
-	mmGetCart.defaultExpectation.params = &CartRepositoryMockGetCartParams{userID}
+	mmGetCart.defaultExpectation.params = &CartRepositoryMockGetCartParams{ctx, userID}
 	mmGetCart.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmGetCart.expectations {
 		if minimock.Equal(e.params, mmGetCart.defaultExpectation.params) {
@@ -452,8 +902,31 @@ func (mmGetCart *mCartRepositoryMockGetCart) Expect(userID int64) *mCartReposito
 	return mmGetCart
 }
 
-// ExpectUserIDParam1 sets up expected param userID for CartRepository.GetCart
-func (mmGetCart *mCartRepositoryMockGetCart) ExpectUserIDParam1(userID int64) *mCartRepositoryMockGetCart {
+// ExpectCtxParam1 sets up expected param ctx for CartRepository.GetCart
+func (mmGetCart *mCartRepositoryMockGetCart) ExpectCtxParam1(ctx context.Context) *mCartRepositoryMockGetCart {
+	if mmGetCart.mock.funcGetCart != nil {
+		mmGetCart.mock.t.Fatalf("CartRepositoryMock.GetCart mock is already set by Set")
+	}
+
+	if mmGetCart.defaultExpectation == nil {
+		mmGetCart.defaultExpectation = &CartRepositoryMockGetCartExpectation{}
+	}
+
+	if mmGetCart.defaultExpectation.params != nil {
+		mmGetCart.mock.t.Fatalf("CartRepositoryMock.GetCart mock is already set by Expect")
+	}
+
+	if mmGetCart.defaultExpectation.paramPtrs == nil {
+		mmGetCart.defaultExpectation.paramPtrs = &CartRepositoryMockGetCartParamPtrs{}
+	}
+	mmGetCart.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetCart.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetCart
+}
+
+// ExpectUserIDParam2 sets up expected param userID for CartRepository.GetCart
+func (mmGetCart *mCartRepositoryMockGetCart) ExpectUserIDParam2(userID int64) *mCartRepositoryMockGetCart {
 	if mmGetCart.mock.funcGetCart != nil {
 		mmGetCart.mock.t.Fatalf("CartRepositoryMock.GetCart mock is already set by Set")
 	}
@@ -476,7 +949,7 @@ func (mmGetCart *mCartRepositoryMockGetCart) ExpectUserIDParam1(userID int64) *m
 }
 
 // Inspect accepts an inspector function that has same arguments as the CartRepository.GetCart
-func (mmGetCart *mCartRepositoryMockGetCart) Inspect(f func(userID int64)) *mCartRepositoryMockGetCart {
+func (mmGetCart *mCartRepositoryMockGetCart) Inspect(f func(ctx context.Context, userID int64)) *mCartRepositoryMockGetCart {
 	if mmGetCart.mock.inspectFuncGetCart != nil {
 		mmGetCart.mock.t.Fatalf("Inspect function is already set for CartRepositoryMock.GetCart")
 	}
@@ -501,7 +974,7 @@ func (mmGetCart *mCartRepositoryMockGetCart) Return(cp1 *models.Cart, err error)
 }
 
 // Set uses given function f to mock the CartRepository.GetCart method
-func (mmGetCart *mCartRepositoryMockGetCart) Set(f func(userID int64) (cp1 *models.Cart, err error)) *CartRepositoryMock {
+func (mmGetCart *mCartRepositoryMockGetCart) Set(f func(ctx context.Context, userID int64) (cp1 *models.Cart, err error)) *CartRepositoryMock {
 	if mmGetCart.defaultExpectation != nil {
 		mmGetCart.mock.t.Fatalf("Default expectation is already set for the CartRepository.GetCart method")
 	}
@@ -517,14 +990,14 @@ func (mmGetCart *mCartRepositoryMockGetCart) Set(f func(userID int64) (cp1 *mode
 
 // When sets expectation for the CartRepository.GetCart which will trigger the result defined by the following
 // Then helper
-func (mmGetCart *mCartRepositoryMockGetCart) When(userID int64) *CartRepositoryMockGetCartExpectation {
+func (mmGetCart *mCartRepositoryMockGetCart) When(ctx context.Context, userID int64) *CartRepositoryMockGetCartExpectation {
 	if mmGetCart.mock.funcGetCart != nil {
 		mmGetCart.mock.t.Fatalf("CartRepositoryMock.GetCart mock is already set by Set")
 	}
 
 	expectation := &CartRepositoryMockGetCartExpectation{
 		mock:               mmGetCart.mock,
-		params:             &CartRepositoryMockGetCartParams{userID},
+		params:             &CartRepositoryMockGetCartParams{ctx, userID},
 		expectationOrigins: CartRepositoryMockGetCartExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmGetCart.expectations = append(mmGetCart.expectations, expectation)
@@ -559,17 +1032,17 @@ func (mmGetCart *mCartRepositoryMockGetCart) invocationsDone() bool {
 }
 
 // GetCart implements mm_cart.CartRepository
-func (mmGetCart *CartRepositoryMock) GetCart(userID int64) (cp1 *models.Cart, err error) {
+func (mmGetCart *CartRepositoryMock) GetCart(ctx context.Context, userID int64) (cp1 *models.Cart, err error) {
 	mm_atomic.AddUint64(&mmGetCart.beforeGetCartCounter, 1)
 	defer mm_atomic.AddUint64(&mmGetCart.afterGetCartCounter, 1)
 
 	mmGetCart.t.Helper()
 
 	if mmGetCart.inspectFuncGetCart != nil {
-		mmGetCart.inspectFuncGetCart(userID)
+		mmGetCart.inspectFuncGetCart(ctx, userID)
 	}
 
-	mm_params := CartRepositoryMockGetCartParams{userID}
+	mm_params := CartRepositoryMockGetCartParams{ctx, userID}
 
 	// Record call args
 	mmGetCart.GetCartMock.mutex.Lock()
@@ -588,10 +1061,15 @@ func (mmGetCart *CartRepositoryMock) GetCart(userID int64) (cp1 *models.Cart, er
 		mm_want := mmGetCart.GetCartMock.defaultExpectation.params
 		mm_want_ptrs := mmGetCart.GetCartMock.defaultExpectation.paramPtrs
 
-		mm_got := CartRepositoryMockGetCartParams{userID}
+		mm_got := CartRepositoryMockGetCartParams{ctx, userID}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetCart.t.Errorf("CartRepositoryMock.GetCart got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetCart.GetCartMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.userID != nil && !minimock.Equal(*mm_want_ptrs.userID, mm_got.userID) {
 				mmGetCart.t.Errorf("CartRepositoryMock.GetCart got unexpected parameter userID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmGetCart.GetCartMock.defaultExpectation.expectationOrigins.originUserID, *mm_want_ptrs.userID, mm_got.userID, minimock.Diff(*mm_want_ptrs.userID, mm_got.userID))
@@ -609,9 +1087,9 @@ func (mmGetCart *CartRepositoryMock) GetCart(userID int64) (cp1 *models.Cart, er
 		return (*mm_results).cp1, (*mm_results).err
 	}
 	if mmGetCart.funcGetCart != nil {
-		return mmGetCart.funcGetCart(userID)
+		return mmGetCart.funcGetCart(ctx, userID)
 	}
-	mmGetCart.t.Fatalf("Unexpected call to CartRepositoryMock.GetCart. %v", userID)
+	mmGetCart.t.Fatalf("Unexpected call to CartRepositoryMock.GetCart. %v %v", ctx, userID)
 	return
 }
 
@@ -683,49 +1161,59 @@ func (m *CartRepositoryMock) MinimockGetCartInspect() {
 	}
 }
 
-type mCartRepositoryMockSaveCart struct {
+type mCartRepositoryMockListIdle struct {
 	optional           bool
 	mock               *CartRepositoryMock
-	defaultExpectation *CartRepositoryMockSaveCartExpectation
-	expectations       []*CartRepositoryMockSaveCartExpectation
+	defaultExpectation *CartRepositoryMockListIdleExpectation
+	expectations       []*CartRepositoryMockListIdleExpectation
 
-	callArgs []*CartRepositoryMockSaveCartParams
+	callArgs []*CartRepositoryMockListIdleParams
 	mutex    sync.RWMutex
 
 	expectedInvocations       uint64
 	expectedInvocationsOrigin string
 }
 
-// CartRepositoryMockSaveCartExpectation specifies expectation struct of the CartRepository.SaveCart
-type CartRepositoryMockSaveCartExpectation struct {
+// CartRepositoryMockListIdleExpectation specifies expectation struct of the CartRepository.ListIdle
+type CartRepositoryMockListIdleExpectation struct {
 	mock               *CartRepositoryMock
-	params             *CartRepositoryMockSaveCartParams
-	paramPtrs          *CartRepositoryMockSaveCartParamPtrs
-	expectationOrigins CartRepositoryMockSaveCartExpectationOrigins
-	results            *CartRepositoryMockSaveCartResults
+	params             *CartRepositoryMockListIdleParams
+	paramPtrs          *CartRepositoryMockListIdleParamPtrs
+	expectationOrigins CartRepositoryMockListIdleExpectationOrigins
+	results            *CartRepositoryMockListIdleResults
 	returnOrigin       string
 	Counter            uint64
 }
 
-// CartRepositoryMockSaveCartParams contains parameters of the CartRepository.SaveCart
-type CartRepositoryMockSaveCartParams struct {
-	cart *models.Cart
+// CartRepositoryMockListIdleParams contains parameters of the CartRepository.ListIdle
+type CartRepositoryMockListIdleParams struct {
+	ctx    context.Context
+	cutoff time.Time
+	after  int64
+	limit  int
 }
 
-// CartRepositoryMockSaveCartParamPtrs contains pointers to parameters of the CartRepository.SaveCart
-type CartRepositoryMockSaveCartParamPtrs struct {
-	cart **models.Cart
+// CartRepositoryMockListIdleParamPtrs contains pointers to parameters of the CartRepository.ListIdle
+type CartRepositoryMockListIdleParamPtrs struct {
+	ctx    *context.Context
+	cutoff *time.Time
+	after  *int64
+	limit  *int
 }
 
-// CartRepositoryMockSaveCartResults contains results of the CartRepository.SaveCart
-type CartRepositoryMockSaveCartResults struct {
-	err error
+// CartRepositoryMockListIdleResults contains results of the CartRepository.ListIdle
+type CartRepositoryMockListIdleResults struct {
+	cpa1 []*models.Cart
+	err  error
 }
 
-// CartRepositoryMockSaveCartOrigins contains origins of expectations of the CartRepository.SaveCart
-type CartRepositoryMockSaveCartExpectationOrigins struct {
-	origin     string
-	originCart string
+// CartRepositoryMockListIdleOrigins contains origins of expectations of the CartRepository.ListIdle
+type CartRepositoryMockListIdleExpectationOrigins struct {
+	origin       string
+	originCtx    string
+	originCutoff string
+	originAfter  string
+	originLimit  string
 }
 
 // Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
@@ -733,15 +1221,786 @@ type CartRepositoryMockSaveCartExpectationOrigins struct {
 // Optional() makes method check to work in '0 or more' mode.
 // It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
 // catch the problems when the expected method call is totally skipped during test run.
-func (mmSaveCart *mCartRepositoryMockSaveCart) Optional() *mCartRepositoryMockSaveCart {
-	mmSaveCart.optional = true
-	return mmSaveCart
+func (mmListIdle *mCartRepositoryMockListIdle) Optional() *mCartRepositoryMockListIdle {
+	mmListIdle.optional = true
+	return mmListIdle
 }
 
-// Expect sets up expected params for CartRepository.SaveCart
-func (mmSaveCart *mCartRepositoryMockSaveCart) Expect(cart *models.Cart) *mCartRepositoryMockSaveCart {
-	if mmSaveCart.mock.funcSaveCart != nil {
-		mmSaveCart.mock.t.Fatalf("CartRepositoryMock.SaveCart mock is already set by Set")
+// Expect sets up expected params for CartRepository.ListIdle
+func (mmListIdle *mCartRepositoryMockListIdle) Expect(ctx context.Context, cutoff time.Time, after int64, limit int) *mCartRepositoryMockListIdle {
+	if mmListIdle.mock.funcListIdle != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Set")
+	}
+
+	if mmListIdle.defaultExpectation == nil {
+		mmListIdle.defaultExpectation = &CartRepositoryMockListIdleExpectation{}
+	}
+
+	if mmListIdle.defaultExpectation.paramPtrs != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by ExpectParams functions")
+	}
+
+	mmListIdle.defaultExpectation.params = &CartRepositoryMockListIdleParams{ctx, cutoff, after, limit}
+	mmListIdle.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmListIdle.expectations {
+		if minimock.Equal(e.params, mmListIdle.defaultExpectation.params) {
+			mmListIdle.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmListIdle.defaultExpectation.params)
+		}
+	}
+
+	return mmListIdle
+}
+
+// ExpectCtxParam1 sets up expected param ctx for CartRepository.ListIdle
+func (mmListIdle *mCartRepositoryMockListIdle) ExpectCtxParam1(ctx context.Context) *mCartRepositoryMockListIdle {
+	if mmListIdle.mock.funcListIdle != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Set")
+	}
+
+	if mmListIdle.defaultExpectation == nil {
+		mmListIdle.defaultExpectation = &CartRepositoryMockListIdleExpectation{}
+	}
+
+	if mmListIdle.defaultExpectation.params != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Expect")
+	}
+
+	if mmListIdle.defaultExpectation.paramPtrs == nil {
+		mmListIdle.defaultExpectation.paramPtrs = &CartRepositoryMockListIdleParamPtrs{}
+	}
+	mmListIdle.defaultExpectation.paramPtrs.ctx = &ctx
+	mmListIdle.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmListIdle
+}
+
+// ExpectCutoffParam2 sets up expected param cutoff for CartRepository.ListIdle
+func (mmListIdle *mCartRepositoryMockListIdle) ExpectCutoffParam2(cutoff time.Time) *mCartRepositoryMockListIdle {
+	if mmListIdle.mock.funcListIdle != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Set")
+	}
+
+	if mmListIdle.defaultExpectation == nil {
+		mmListIdle.defaultExpectation = &CartRepositoryMockListIdleExpectation{}
+	}
+
+	if mmListIdle.defaultExpectation.params != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Expect")
+	}
+
+	if mmListIdle.defaultExpectation.paramPtrs == nil {
+		mmListIdle.defaultExpectation.paramPtrs = &CartRepositoryMockListIdleParamPtrs{}
+	}
+	mmListIdle.defaultExpectation.paramPtrs.cutoff = &cutoff
+	mmListIdle.defaultExpectation.expectationOrigins.originCutoff = minimock.CallerInfo(1)
+
+	return mmListIdle
+}
+
+// ExpectAfterParam3 sets up expected param after for CartRepository.ListIdle
+func (mmListIdle *mCartRepositoryMockListIdle) ExpectAfterParam3(after int64) *mCartRepositoryMockListIdle {
+	if mmListIdle.mock.funcListIdle != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Set")
+	}
+
+	if mmListIdle.defaultExpectation == nil {
+		mmListIdle.defaultExpectation = &CartRepositoryMockListIdleExpectation{}
+	}
+
+	if mmListIdle.defaultExpectation.params != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Expect")
+	}
+
+	if mmListIdle.defaultExpectation.paramPtrs == nil {
+		mmListIdle.defaultExpectation.paramPtrs = &CartRepositoryMockListIdleParamPtrs{}
+	}
+	mmListIdle.defaultExpectation.paramPtrs.after = &after
+	mmListIdle.defaultExpectation.expectationOrigins.originAfter = minimock.CallerInfo(1)
+
+	return mmListIdle
+}
+
+// ExpectLimitParam4 sets up expected param limit for CartRepository.ListIdle
+func (mmListIdle *mCartRepositoryMockListIdle) ExpectLimitParam4(limit int) *mCartRepositoryMockListIdle {
+	if mmListIdle.mock.funcListIdle != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Set")
+	}
+
+	if mmListIdle.defaultExpectation == nil {
+		mmListIdle.defaultExpectation = &CartRepositoryMockListIdleExpectation{}
+	}
+
+	if mmListIdle.defaultExpectation.params != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Expect")
+	}
+
+	if mmListIdle.defaultExpectation.paramPtrs == nil {
+		mmListIdle.defaultExpectation.paramPtrs = &CartRepositoryMockListIdleParamPtrs{}
+	}
+	mmListIdle.defaultExpectation.paramPtrs.limit = &limit
+	mmListIdle.defaultExpectation.expectationOrigins.originLimit = minimock.CallerInfo(1)
+
+	return mmListIdle
+}
+
+// Inspect accepts an inspector function that has same arguments as the CartRepository.ListIdle
+func (mmListIdle *mCartRepositoryMockListIdle) Inspect(f func(ctx context.Context, cutoff time.Time, after int64, limit int)) *mCartRepositoryMockListIdle {
+	if mmListIdle.mock.inspectFuncListIdle != nil {
+		mmListIdle.mock.t.Fatalf("Inspect function is already set for CartRepositoryMock.ListIdle")
+	}
+
+	mmListIdle.mock.inspectFuncListIdle = f
+
+	return mmListIdle
+}
+
+// Return sets up results that will be returned by CartRepository.ListIdle
+func (mmListIdle *mCartRepositoryMockListIdle) Return(cpa1 []*models.Cart, err error) *CartRepositoryMock {
+	if mmListIdle.mock.funcListIdle != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Set")
+	}
+
+	if mmListIdle.defaultExpectation == nil {
+		mmListIdle.defaultExpectation = &CartRepositoryMockListIdleExpectation{mock: mmListIdle.mock}
+	}
+	mmListIdle.defaultExpectation.results = &CartRepositoryMockListIdleResults{cpa1, err}
+	mmListIdle.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmListIdle.mock
+}
+
+// Set uses given function f to mock the CartRepository.ListIdle method
+func (mmListIdle *mCartRepositoryMockListIdle) Set(f func(ctx context.Context, cutoff time.Time, after int64, limit int) (cpa1 []*models.Cart, err error)) *CartRepositoryMock {
+	if mmListIdle.defaultExpectation != nil {
+		mmListIdle.mock.t.Fatalf("Default expectation is already set for the CartRepository.ListIdle method")
+	}
+
+	if len(mmListIdle.expectations) > 0 {
+		mmListIdle.mock.t.Fatalf("Some expectations are already set for the CartRepository.ListIdle method")
+	}
+
+	mmListIdle.mock.funcListIdle = f
+	mmListIdle.mock.funcListIdleOrigin = minimock.CallerInfo(1)
+	return mmListIdle.mock
+}
+
+// When sets expectation for the CartRepository.ListIdle which will trigger the result defined by the following
+// Then helper
+func (mmListIdle *mCartRepositoryMockListIdle) When(ctx context.Context, cutoff time.Time, after int64, limit int) *CartRepositoryMockListIdleExpectation {
+	if mmListIdle.mock.funcListIdle != nil {
+		mmListIdle.mock.t.Fatalf("CartRepositoryMock.ListIdle mock is already set by Set")
+	}
+
+	expectation := &CartRepositoryMockListIdleExpectation{
+		mock:               mmListIdle.mock,
+		params:             &CartRepositoryMockListIdleParams{ctx, cutoff, after, limit},
+		expectationOrigins: CartRepositoryMockListIdleExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmListIdle.expectations = append(mmListIdle.expectations, expectation)
+	return expectation
+}
+
+// Then sets up CartRepository.ListIdle return parameters for the expectation previously defined by the When method
+func (e *CartRepositoryMockListIdleExpectation) Then(cpa1 []*models.Cart, err error) *CartRepositoryMock {
+	e.results = &CartRepositoryMockListIdleResults{cpa1, err}
+	return e.mock
+}
+
+// Times sets number of times CartRepository.ListIdle should be invoked
+func (mmListIdle *mCartRepositoryMockListIdle) Times(n uint64) *mCartRepositoryMockListIdle {
+	if n == 0 {
+		mmListIdle.mock.t.Fatalf("Times of CartRepositoryMock.ListIdle mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmListIdle.expectedInvocations, n)
+	mmListIdle.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmListIdle
+}
+
+func (mmListIdle *mCartRepositoryMockListIdle) invocationsDone() bool {
+	if len(mmListIdle.expectations) == 0 && mmListIdle.defaultExpectation == nil && mmListIdle.mock.funcListIdle == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmListIdle.mock.afterListIdleCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmListIdle.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// ListIdle implements mm_cart.CartRepository
+func (mmListIdle *CartRepositoryMock) ListIdle(ctx context.Context, cutoff time.Time, after int64, limit int) (cpa1 []*models.Cart, err error) {
+	mm_atomic.AddUint64(&mmListIdle.beforeListIdleCounter, 1)
+	defer mm_atomic.AddUint64(&mmListIdle.afterListIdleCounter, 1)
+
+	mmListIdle.t.Helper()
+
+	if mmListIdle.inspectFuncListIdle != nil {
+		mmListIdle.inspectFuncListIdle(ctx, cutoff, after, limit)
+	}
+
+	mm_params := CartRepositoryMockListIdleParams{ctx, cutoff, after, limit}
+
+	// Record call args
+	mmListIdle.ListIdleMock.mutex.Lock()
+	mmListIdle.ListIdleMock.callArgs = append(mmListIdle.ListIdleMock.callArgs, &mm_params)
+	mmListIdle.ListIdleMock.mutex.Unlock()
+
+	for _, e := range mmListIdle.ListIdleMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.cpa1, e.results.err
+		}
+	}
+
+	if mmListIdle.ListIdleMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmListIdle.ListIdleMock.defaultExpectation.Counter, 1)
+		mm_want := mmListIdle.ListIdleMock.defaultExpectation.params
+		mm_want_ptrs := mmListIdle.ListIdleMock.defaultExpectation.paramPtrs
+
+		mm_got := CartRepositoryMockListIdleParams{ctx, cutoff, after, limit}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmListIdle.t.Errorf("CartRepositoryMock.ListIdle got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListIdle.ListIdleMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.cutoff != nil && !minimock.Equal(*mm_want_ptrs.cutoff, mm_got.cutoff) {
+				mmListIdle.t.Errorf("CartRepositoryMock.ListIdle got unexpected parameter cutoff, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListIdle.ListIdleMock.defaultExpectation.expectationOrigins.originCutoff, *mm_want_ptrs.cutoff, mm_got.cutoff, minimock.Diff(*mm_want_ptrs.cutoff, mm_got.cutoff))
+			}
+
+			if mm_want_ptrs.after != nil && !minimock.Equal(*mm_want_ptrs.after, mm_got.after) {
+				mmListIdle.t.Errorf("CartRepositoryMock.ListIdle got unexpected parameter after, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListIdle.ListIdleMock.defaultExpectation.expectationOrigins.originAfter, *mm_want_ptrs.after, mm_got.after, minimock.Diff(*mm_want_ptrs.after, mm_got.after))
+			}
+
+			if mm_want_ptrs.limit != nil && !minimock.Equal(*mm_want_ptrs.limit, mm_got.limit) {
+				mmListIdle.t.Errorf("CartRepositoryMock.ListIdle got unexpected parameter limit, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmListIdle.ListIdleMock.defaultExpectation.expectationOrigins.originLimit, *mm_want_ptrs.limit, mm_got.limit, minimock.Diff(*mm_want_ptrs.limit, mm_got.limit))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmListIdle.t.Errorf("CartRepositoryMock.ListIdle got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmListIdle.ListIdleMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmListIdle.ListIdleMock.defaultExpectation.results
+		if mm_results == nil {
+			mmListIdle.t.Fatal("No results are set for the CartRepositoryMock.ListIdle")
+		}
+		return (*mm_results).cpa1, (*mm_results).err
+	}
+	if mmListIdle.funcListIdle != nil {
+		return mmListIdle.funcListIdle(ctx, cutoff, after, limit)
+	}
+	mmListIdle.t.Fatalf("Unexpected call to CartRepositoryMock.ListIdle. %v %v %v %v", ctx, cutoff, after, limit)
+	return
+}
+
+// ListIdleAfterCounter returns a count of finished CartRepositoryMock.ListIdle invocations
+func (mmListIdle *CartRepositoryMock) ListIdleAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmListIdle.afterListIdleCounter)
+}
+
+// ListIdleBeforeCounter returns a count of CartRepositoryMock.ListIdle invocations
+func (mmListIdle *CartRepositoryMock) ListIdleBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmListIdle.beforeListIdleCounter)
+}
+
+// Calls returns a list of arguments used in each call to CartRepositoryMock.ListIdle.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmListIdle *mCartRepositoryMockListIdle) Calls() []*CartRepositoryMockListIdleParams {
+	mmListIdle.mutex.RLock()
+
+	argCopy := make([]*CartRepositoryMockListIdleParams, len(mmListIdle.callArgs))
+	copy(argCopy, mmListIdle.callArgs)
+
+	mmListIdle.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockListIdleDone returns true if the count of the ListIdle invocations corresponds
+// the number of defined expectations
+func (m *CartRepositoryMock) MinimockListIdleDone() bool {
+	if m.ListIdleMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.ListIdleMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.ListIdleMock.invocationsDone()
+}
+
+// MinimockListIdleInspect logs each unmet expectation
+func (m *CartRepositoryMock) MinimockListIdleInspect() {
+	for _, e := range m.ListIdleMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to CartRepositoryMock.ListIdle at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterListIdleCounter := mm_atomic.LoadUint64(&m.afterListIdleCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.ListIdleMock.defaultExpectation != nil && afterListIdleCounter < 1 {
+		if m.ListIdleMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to CartRepositoryMock.ListIdle at\n%s", m.ListIdleMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to CartRepositoryMock.ListIdle at\n%s with params: %#v", m.ListIdleMock.defaultExpectation.expectationOrigins.origin, *m.ListIdleMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcListIdle != nil && afterListIdleCounter < 1 {
+		m.t.Errorf("Expected call to CartRepositoryMock.ListIdle at\n%s", m.funcListIdleOrigin)
+	}
+
+	if !m.ListIdleMock.invocationsDone() && afterListIdleCounter > 0 {
+		m.t.Errorf("Expected %d calls to CartRepositoryMock.ListIdle at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.ListIdleMock.expectedInvocations), m.ListIdleMock.expectedInvocationsOrigin, afterListIdleCounter)
+	}
+}
+
+type mCartRepositoryMockRefreshCart struct {
+	optional           bool
+	mock               *CartRepositoryMock
+	defaultExpectation *CartRepositoryMockRefreshCartExpectation
+	expectations       []*CartRepositoryMockRefreshCartExpectation
+
+	callArgs []*CartRepositoryMockRefreshCartParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// CartRepositoryMockRefreshCartExpectation specifies expectation struct of the CartRepository.RefreshCart
+type CartRepositoryMockRefreshCartExpectation struct {
+	mock               *CartRepositoryMock
+	params             *CartRepositoryMockRefreshCartParams
+	paramPtrs          *CartRepositoryMockRefreshCartParamPtrs
+	expectationOrigins CartRepositoryMockRefreshCartExpectationOrigins
+	results            *CartRepositoryMockRefreshCartResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// CartRepositoryMockRefreshCartParams contains parameters of the CartRepository.RefreshCart
+type CartRepositoryMockRefreshCartParams struct {
+	ctx    context.Context
+	userID int64
+	update func(cart *models.Cart) error
+}
+
+// CartRepositoryMockRefreshCartParamPtrs contains pointers to parameters of the CartRepository.RefreshCart
+type CartRepositoryMockRefreshCartParamPtrs struct {
+	ctx    *context.Context
+	userID *int64
+	update *func(cart *models.Cart) error
+}
+
+// CartRepositoryMockRefreshCartResults contains results of the CartRepository.RefreshCart
+type CartRepositoryMockRefreshCartResults struct {
+	err error
+}
+
+// CartRepositoryMockRefreshCartOrigins contains origins of expectations of the CartRepository.RefreshCart
+type CartRepositoryMockRefreshCartExpectationOrigins struct {
+	origin       string
+	originCtx    string
+	originUserID string
+	originUpdate string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) Optional() *mCartRepositoryMockRefreshCart {
+	mmRefreshCart.optional = true
+	return mmRefreshCart
+}
+
+// Expect sets up expected params for CartRepository.RefreshCart
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) Expect(ctx context.Context, userID int64, update func(cart *models.Cart) error) *mCartRepositoryMockRefreshCart {
+	if mmRefreshCart.mock.funcRefreshCart != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Set")
+	}
+
+	if mmRefreshCart.defaultExpectation == nil {
+		mmRefreshCart.defaultExpectation = &CartRepositoryMockRefreshCartExpectation{}
+	}
+
+	if mmRefreshCart.defaultExpectation.paramPtrs != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by ExpectParams functions")
+	}
+
+	mmRefreshCart.defaultExpectation.params = &CartRepositoryMockRefreshCartParams{ctx, userID, update}
+	mmRefreshCart.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmRefreshCart.expectations {
+		if minimock.Equal(e.params, mmRefreshCart.defaultExpectation.params) {
+			mmRefreshCart.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmRefreshCart.defaultExpectation.params)
+		}
+	}
+
+	return mmRefreshCart
+}
+
+// ExpectCtxParam1 sets up expected param ctx for CartRepository.RefreshCart
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) ExpectCtxParam1(ctx context.Context) *mCartRepositoryMockRefreshCart {
+	if mmRefreshCart.mock.funcRefreshCart != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Set")
+	}
+
+	if mmRefreshCart.defaultExpectation == nil {
+		mmRefreshCart.defaultExpectation = &CartRepositoryMockRefreshCartExpectation{}
+	}
+
+	if mmRefreshCart.defaultExpectation.params != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Expect")
+	}
+
+	if mmRefreshCart.defaultExpectation.paramPtrs == nil {
+		mmRefreshCart.defaultExpectation.paramPtrs = &CartRepositoryMockRefreshCartParamPtrs{}
+	}
+	mmRefreshCart.defaultExpectation.paramPtrs.ctx = &ctx
+	mmRefreshCart.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmRefreshCart
+}
+
+// ExpectUserIDParam2 sets up expected param userID for CartRepository.RefreshCart
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) ExpectUserIDParam2(userID int64) *mCartRepositoryMockRefreshCart {
+	if mmRefreshCart.mock.funcRefreshCart != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Set")
+	}
+
+	if mmRefreshCart.defaultExpectation == nil {
+		mmRefreshCart.defaultExpectation = &CartRepositoryMockRefreshCartExpectation{}
+	}
+
+	if mmRefreshCart.defaultExpectation.params != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Expect")
+	}
+
+	if mmRefreshCart.defaultExpectation.paramPtrs == nil {
+		mmRefreshCart.defaultExpectation.paramPtrs = &CartRepositoryMockRefreshCartParamPtrs{}
+	}
+	mmRefreshCart.defaultExpectation.paramPtrs.userID = &userID
+	mmRefreshCart.defaultExpectation.expectationOrigins.originUserID = minimock.CallerInfo(1)
+
+	return mmRefreshCart
+}
+
+// ExpectUpdateParam3 sets up expected param update for CartRepository.RefreshCart
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) ExpectUpdateParam3(update func(cart *models.Cart) error) *mCartRepositoryMockRefreshCart {
+	if mmRefreshCart.mock.funcRefreshCart != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Set")
+	}
+
+	if mmRefreshCart.defaultExpectation == nil {
+		mmRefreshCart.defaultExpectation = &CartRepositoryMockRefreshCartExpectation{}
+	}
+
+	if mmRefreshCart.defaultExpectation.params != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Expect")
+	}
+
+	if mmRefreshCart.defaultExpectation.paramPtrs == nil {
+		mmRefreshCart.defaultExpectation.paramPtrs = &CartRepositoryMockRefreshCartParamPtrs{}
+	}
+	mmRefreshCart.defaultExpectation.paramPtrs.update = &update
+	mmRefreshCart.defaultExpectation.expectationOrigins.originUpdate = minimock.CallerInfo(1)
+
+	return mmRefreshCart
+}
+
+// Inspect accepts an inspector function that has same arguments as the CartRepository.RefreshCart
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) Inspect(f func(ctx context.Context, userID int64, update func(cart *models.Cart) error)) *mCartRepositoryMockRefreshCart {
+	if mmRefreshCart.mock.inspectFuncRefreshCart != nil {
+		mmRefreshCart.mock.t.Fatalf("Inspect function is already set for CartRepositoryMock.RefreshCart")
+	}
+
+	mmRefreshCart.mock.inspectFuncRefreshCart = f
+
+	return mmRefreshCart
+}
+
+// Return sets up results that will be returned by CartRepository.RefreshCart
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) Return(err error) *CartRepositoryMock {
+	if mmRefreshCart.mock.funcRefreshCart != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Set")
+	}
+
+	if mmRefreshCart.defaultExpectation == nil {
+		mmRefreshCart.defaultExpectation = &CartRepositoryMockRefreshCartExpectation{mock: mmRefreshCart.mock}
+	}
+	mmRefreshCart.defaultExpectation.results = &CartRepositoryMockRefreshCartResults{err}
+	mmRefreshCart.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmRefreshCart.mock
+}
+
+// Set uses given function f to mock the CartRepository.RefreshCart method
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) Set(f func(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error)) *CartRepositoryMock {
+	if mmRefreshCart.defaultExpectation != nil {
+		mmRefreshCart.mock.t.Fatalf("Default expectation is already set for the CartRepository.RefreshCart method")
+	}
+
+	if len(mmRefreshCart.expectations) > 0 {
+		mmRefreshCart.mock.t.Fatalf("Some expectations are already set for the CartRepository.RefreshCart method")
+	}
+
+	mmRefreshCart.mock.funcRefreshCart = f
+	mmRefreshCart.mock.funcRefreshCartOrigin = minimock.CallerInfo(1)
+	return mmRefreshCart.mock
+}
+
+// When sets expectation for the CartRepository.RefreshCart which will trigger the result defined by the following
+// Then helper
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) When(ctx context.Context, userID int64, update func(cart *models.Cart) error) *CartRepositoryMockRefreshCartExpectation {
+	if mmRefreshCart.mock.funcRefreshCart != nil {
+		mmRefreshCart.mock.t.Fatalf("CartRepositoryMock.RefreshCart mock is already set by Set")
+	}
+
+	expectation := &CartRepositoryMockRefreshCartExpectation{
+		mock:               mmRefreshCart.mock,
+		params:             &CartRepositoryMockRefreshCartParams{ctx, userID, update},
+		expectationOrigins: CartRepositoryMockRefreshCartExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmRefreshCart.expectations = append(mmRefreshCart.expectations, expectation)
+	return expectation
+}
+
+// Then sets up CartRepository.RefreshCart return parameters for the expectation previously defined by the When method
+func (e *CartRepositoryMockRefreshCartExpectation) Then(err error) *CartRepositoryMock {
+	e.results = &CartRepositoryMockRefreshCartResults{err}
+	return e.mock
+}
+
+// Times sets number of times CartRepository.RefreshCart should be invoked
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) Times(n uint64) *mCartRepositoryMockRefreshCart {
+	if n == 0 {
+		mmRefreshCart.mock.t.Fatalf("Times of CartRepositoryMock.RefreshCart mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmRefreshCart.expectedInvocations, n)
+	mmRefreshCart.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmRefreshCart
+}
+
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) invocationsDone() bool {
+	if len(mmRefreshCart.expectations) == 0 && mmRefreshCart.defaultExpectation == nil && mmRefreshCart.mock.funcRefreshCart == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmRefreshCart.mock.afterRefreshCartCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmRefreshCart.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// RefreshCart implements mm_cart.CartRepository
+func (mmRefreshCart *CartRepositoryMock) RefreshCart(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error) {
+	mm_atomic.AddUint64(&mmRefreshCart.beforeRefreshCartCounter, 1)
+	defer mm_atomic.AddUint64(&mmRefreshCart.afterRefreshCartCounter, 1)
+
+	mmRefreshCart.t.Helper()
+
+	if mmRefreshCart.inspectFuncRefreshCart != nil {
+		mmRefreshCart.inspectFuncRefreshCart(ctx, userID, update)
+	}
+
+	mm_params := CartRepositoryMockRefreshCartParams{ctx, userID, update}
+
+	// Record call args
+	mmRefreshCart.RefreshCartMock.mutex.Lock()
+	mmRefreshCart.RefreshCartMock.callArgs = append(mmRefreshCart.RefreshCartMock.callArgs, &mm_params)
+	mmRefreshCart.RefreshCartMock.mutex.Unlock()
+
+	for _, e := range mmRefreshCart.RefreshCartMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmRefreshCart.RefreshCartMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmRefreshCart.RefreshCartMock.defaultExpectation.Counter, 1)
+		mm_want := mmRefreshCart.RefreshCartMock.defaultExpectation.params
+		mm_want_ptrs := mmRefreshCart.RefreshCartMock.defaultExpectation.paramPtrs
+
+		mm_got := CartRepositoryMockRefreshCartParams{ctx, userID, update}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmRefreshCart.t.Errorf("CartRepositoryMock.RefreshCart got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRefreshCart.RefreshCartMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.userID != nil && !minimock.Equal(*mm_want_ptrs.userID, mm_got.userID) {
+				mmRefreshCart.t.Errorf("CartRepositoryMock.RefreshCart got unexpected parameter userID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRefreshCart.RefreshCartMock.defaultExpectation.expectationOrigins.originUserID, *mm_want_ptrs.userID, mm_got.userID, minimock.Diff(*mm_want_ptrs.userID, mm_got.userID))
+			}
+
+			if mm_want_ptrs.update != nil && !minimock.Equal(*mm_want_ptrs.update, mm_got.update) {
+				mmRefreshCart.t.Errorf("CartRepositoryMock.RefreshCart got unexpected parameter update, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmRefreshCart.RefreshCartMock.defaultExpectation.expectationOrigins.originUpdate, *mm_want_ptrs.update, mm_got.update, minimock.Diff(*mm_want_ptrs.update, mm_got.update))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmRefreshCart.t.Errorf("CartRepositoryMock.RefreshCart got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmRefreshCart.RefreshCartMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmRefreshCart.RefreshCartMock.defaultExpectation.results
+		if mm_results == nil {
+			mmRefreshCart.t.Fatal("No results are set for the CartRepositoryMock.RefreshCart")
+		}
+		return (*mm_results).err
+	}
+	if mmRefreshCart.funcRefreshCart != nil {
+		return mmRefreshCart.funcRefreshCart(ctx, userID, update)
+	}
+	mmRefreshCart.t.Fatalf("Unexpected call to CartRepositoryMock.RefreshCart. %v %v %v", ctx, userID, update)
+	return
+}
+
+// RefreshCartAfterCounter returns a count of finished CartRepositoryMock.RefreshCart invocations
+func (mmRefreshCart *CartRepositoryMock) RefreshCartAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRefreshCart.afterRefreshCartCounter)
+}
+
+// RefreshCartBeforeCounter returns a count of CartRepositoryMock.RefreshCart invocations
+func (mmRefreshCart *CartRepositoryMock) RefreshCartBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmRefreshCart.beforeRefreshCartCounter)
+}
+
+// Calls returns a list of arguments used in each call to CartRepositoryMock.RefreshCart.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmRefreshCart *mCartRepositoryMockRefreshCart) Calls() []*CartRepositoryMockRefreshCartParams {
+	mmRefreshCart.mutex.RLock()
+
+	argCopy := make([]*CartRepositoryMockRefreshCartParams, len(mmRefreshCart.callArgs))
+	copy(argCopy, mmRefreshCart.callArgs)
+
+	mmRefreshCart.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockRefreshCartDone returns true if the count of the RefreshCart invocations corresponds
+// the number of defined expectations
+func (m *CartRepositoryMock) MinimockRefreshCartDone() bool {
+	if m.RefreshCartMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.RefreshCartMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.RefreshCartMock.invocationsDone()
+}
+
+// MinimockRefreshCartInspect logs each unmet expectation
+func (m *CartRepositoryMock) MinimockRefreshCartInspect() {
+	for _, e := range m.RefreshCartMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to CartRepositoryMock.RefreshCart at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterRefreshCartCounter := mm_atomic.LoadUint64(&m.afterRefreshCartCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.RefreshCartMock.defaultExpectation != nil && afterRefreshCartCounter < 1 {
+		if m.RefreshCartMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to CartRepositoryMock.RefreshCart at\n%s", m.RefreshCartMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to CartRepositoryMock.RefreshCart at\n%s with params: %#v", m.RefreshCartMock.defaultExpectation.expectationOrigins.origin, *m.RefreshCartMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcRefreshCart != nil && afterRefreshCartCounter < 1 {
+		m.t.Errorf("Expected call to CartRepositoryMock.RefreshCart at\n%s", m.funcRefreshCartOrigin)
+	}
+
+	if !m.RefreshCartMock.invocationsDone() && afterRefreshCartCounter > 0 {
+		m.t.Errorf("Expected %d calls to CartRepositoryMock.RefreshCart at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.RefreshCartMock.expectedInvocations), m.RefreshCartMock.expectedInvocationsOrigin, afterRefreshCartCounter)
+	}
+}
+
+type mCartRepositoryMockSaveCart struct {
+	optional           bool
+	mock               *CartRepositoryMock
+	defaultExpectation *CartRepositoryMockSaveCartExpectation
+	expectations       []*CartRepositoryMockSaveCartExpectation
+
+	callArgs []*CartRepositoryMockSaveCartParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// CartRepositoryMockSaveCartExpectation specifies expectation struct of the CartRepository.SaveCart
+type CartRepositoryMockSaveCartExpectation struct {
+	mock               *CartRepositoryMock
+	params             *CartRepositoryMockSaveCartParams
+	paramPtrs          *CartRepositoryMockSaveCartParamPtrs
+	expectationOrigins CartRepositoryMockSaveCartExpectationOrigins
+	results            *CartRepositoryMockSaveCartResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// CartRepositoryMockSaveCartParams contains parameters of the CartRepository.SaveCart
+type CartRepositoryMockSaveCartParams struct {
+	ctx  context.Context
+	cart *models.Cart
+}
+
+// CartRepositoryMockSaveCartParamPtrs contains pointers to parameters of the CartRepository.SaveCart
+type CartRepositoryMockSaveCartParamPtrs struct {
+	ctx  *context.Context
+	cart **models.Cart
+}
+
+// CartRepositoryMockSaveCartResults contains results of the CartRepository.SaveCart
+type CartRepositoryMockSaveCartResults struct {
+	err error
+}
+
+// CartRepositoryMockSaveCartOrigins contains origins of expectations of the CartRepository.SaveCart
+type CartRepositoryMockSaveCartExpectationOrigins struct {
+	origin     string
+	originCtx  string
+	originCart string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmSaveCart *mCartRepositoryMockSaveCart) Optional() *mCartRepositoryMockSaveCart {
+	mmSaveCart.optional = true
+	return mmSaveCart
+}
+
+// Expect sets up expected params for CartRepository.SaveCart
+func (mmSaveCart *mCartRepositoryMockSaveCart) Expect(ctx context.Context, cart *models.Cart) *mCartRepositoryMockSaveCart {
+	if mmSaveCart.mock.funcSaveCart != nil {
+		mmSaveCart.mock.t.Fatalf("CartRepositoryMock.SaveCart mock is already set by Set")
 	}
 
 	if mmSaveCart.defaultExpectation == nil {
@@ -752,7 +2011,7 @@ func (mmSaveCart *mCartRepositoryMockSaveCart) Expect(cart *models.Cart) *mCartR
 		mmSaveCart.mock.t.Fatalf("CartRepositoryMock.SaveCart mock is already set by ExpectParams functions")
 	}
 
-	mmSaveCart.defaultExpectation.params = &CartRepositoryMockSaveCartParams{cart}
+	mmSaveCart.defaultExpectation.params = &CartRepositoryMockSaveCartParams{ctx, cart}
 	mmSaveCart.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmSaveCart.expectations {
 		if minimock.Equal(e.params, mmSaveCart.defaultExpectation.params) {
@@ -763,8 +2022,31 @@ func (mmSaveCart *mCartRepositoryMockSaveCart) Expect(cart *models.Cart) *mCartR
 	return mmSaveCart
 }
 
-// ExpectCartParam1 sets up expected param cart for CartRepository.SaveCart
-func (mmSaveCart *mCartRepositoryMockSaveCart) ExpectCartParam1(cart *models.Cart) *mCartRepositoryMockSaveCart {
+// ExpectCtxParam1 sets up expected param ctx for CartRepository.SaveCart
+func (mmSaveCart *mCartRepositoryMockSaveCart) ExpectCtxParam1(ctx context.Context) *mCartRepositoryMockSaveCart {
+	if mmSaveCart.mock.funcSaveCart != nil {
+		mmSaveCart.mock.t.Fatalf("CartRepositoryMock.SaveCart mock is already set by Set")
+	}
+
+	if mmSaveCart.defaultExpectation == nil {
+		mmSaveCart.defaultExpectation = &CartRepositoryMockSaveCartExpectation{}
+	}
+
+	if mmSaveCart.defaultExpectation.params != nil {
+		mmSaveCart.mock.t.Fatalf("CartRepositoryMock.SaveCart mock is already set by Expect")
+	}
+
+	if mmSaveCart.defaultExpectation.paramPtrs == nil {
+		mmSaveCart.defaultExpectation.paramPtrs = &CartRepositoryMockSaveCartParamPtrs{}
+	}
+	mmSaveCart.defaultExpectation.paramPtrs.ctx = &ctx
+	mmSaveCart.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmSaveCart
+}
+
+// ExpectCartParam2 sets up expected param cart for CartRepository.SaveCart
+func (mmSaveCart *mCartRepositoryMockSaveCart) ExpectCartParam2(cart *models.Cart) *mCartRepositoryMockSaveCart {
 	if mmSaveCart.mock.funcSaveCart != nil {
 		mmSaveCart.mock.t.Fatalf("CartRepositoryMock.SaveCart mock is already set by Set")
 	}
@@ -787,7 +2069,7 @@ func (mmSaveCart *mCartRepositoryMockSaveCart) ExpectCartParam1(cart *models.Car
 }
 
 // Inspect accepts an inspector function that has same arguments as the CartRepository.SaveCart
-func (mmSaveCart *mCartRepositoryMockSaveCart) Inspect(f func(cart *models.Cart)) *mCartRepositoryMockSaveCart {
+func (mmSaveCart *mCartRepositoryMockSaveCart) Inspect(f func(ctx context.Context, cart *models.Cart)) *mCartRepositoryMockSaveCart {
 	if mmSaveCart.mock.inspectFuncSaveCart != nil {
 		mmSaveCart.mock.t.Fatalf("Inspect function is already set for CartRepositoryMock.SaveCart")
 	}
@@ -812,7 +2094,7 @@ func (mmSaveCart *mCartRepositoryMockSaveCart) Return(err error) *CartRepository
 }
 
 // Set uses given function f to mock the CartRepository.SaveCart method
-func (mmSaveCart *mCartRepositoryMockSaveCart) Set(f func(cart *models.Cart) (err error)) *CartRepositoryMock {
+func (mmSaveCart *mCartRepositoryMockSaveCart) Set(f func(ctx context.Context, cart *models.Cart) (err error)) *CartRepositoryMock {
 	if mmSaveCart.defaultExpectation != nil {
 		mmSaveCart.mock.t.Fatalf("Default expectation is already set for the CartRepository.SaveCart method")
 	}
@@ -828,14 +2110,14 @@ func (mmSaveCart *mCartRepositoryMockSaveCart) Set(f func(cart *models.Cart) (er
 
 // When sets expectation for the CartRepository.SaveCart which will trigger the result defined by the following
 // Then helper
-func (mmSaveCart *mCartRepositoryMockSaveCart) When(cart *models.Cart) *CartRepositoryMockSaveCartExpectation {
+func (mmSaveCart *mCartRepositoryMockSaveCart) When(ctx context.Context, cart *models.Cart) *CartRepositoryMockSaveCartExpectation {
 	if mmSaveCart.mock.funcSaveCart != nil {
 		mmSaveCart.mock.t.Fatalf("CartRepositoryMock.SaveCart mock is already set by Set")
 	}
 
 	expectation := &CartRepositoryMockSaveCartExpectation{
 		mock:               mmSaveCart.mock,
-		params:             &CartRepositoryMockSaveCartParams{cart},
+		params:             &CartRepositoryMockSaveCartParams{ctx, cart},
 		expectationOrigins: CartRepositoryMockSaveCartExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmSaveCart.expectations = append(mmSaveCart.expectations, expectation)
@@ -870,17 +2152,17 @@ func (mmSaveCart *mCartRepositoryMockSaveCart) invocationsDone() bool {
 }
 
 // SaveCart implements mm_cart.CartRepository
-func (mmSaveCart *CartRepositoryMock) SaveCart(cart *models.Cart) (err error) {
+func (mmSaveCart *CartRepositoryMock) SaveCart(ctx context.Context, cart *models.Cart) (err error) {
 	mm_atomic.AddUint64(&mmSaveCart.beforeSaveCartCounter, 1)
 	defer mm_atomic.AddUint64(&mmSaveCart.afterSaveCartCounter, 1)
 
 	mmSaveCart.t.Helper()
 
 	if mmSaveCart.inspectFuncSaveCart != nil {
-		mmSaveCart.inspectFuncSaveCart(cart)
+		mmSaveCart.inspectFuncSaveCart(ctx, cart)
 	}
 
-	mm_params := CartRepositoryMockSaveCartParams{cart}
+	mm_params := CartRepositoryMockSaveCartParams{ctx, cart}
 
 	// Record call args
 	mmSaveCart.SaveCartMock.mutex.Lock()
@@ -899,10 +2181,15 @@ func (mmSaveCart *CartRepositoryMock) SaveCart(cart *models.Cart) (err error) {
 		mm_want := mmSaveCart.SaveCartMock.defaultExpectation.params
 		mm_want_ptrs := mmSaveCart.SaveCartMock.defaultExpectation.paramPtrs
 
-		mm_got := CartRepositoryMockSaveCartParams{cart}
+		mm_got := CartRepositoryMockSaveCartParams{ctx, cart}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmSaveCart.t.Errorf("CartRepositoryMock.SaveCart got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmSaveCart.SaveCartMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.cart != nil && !minimock.Equal(*mm_want_ptrs.cart, mm_got.cart) {
 				mmSaveCart.t.Errorf("CartRepositoryMock.SaveCart got unexpected parameter cart, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmSaveCart.SaveCartMock.defaultExpectation.expectationOrigins.originCart, *mm_want_ptrs.cart, mm_got.cart, minimock.Diff(*mm_want_ptrs.cart, mm_got.cart))
@@ -920,9 +2207,9 @@ func (mmSaveCart *CartRepositoryMock) SaveCart(cart *models.Cart) (err error) {
 		return (*mm_results).err
 	}
 	if mmSaveCart.funcSaveCart != nil {
-		return mmSaveCart.funcSaveCart(cart)
+		return mmSaveCart.funcSaveCart(ctx, cart)
 	}
-	mmSaveCart.t.Fatalf("Unexpected call to CartRepositoryMock.SaveCart. %v", cart)
+	mmSaveCart.t.Fatalf("Unexpected call to CartRepositoryMock.SaveCart. %v %v", ctx, cart)
 	return
 }
 
@@ -994,15 +2281,396 @@ func (m *CartRepositoryMock) MinimockSaveCartInspect() {
 	}
 }
 
+type mCartRepositoryMockUpdateCart struct {
+	optional           bool
+	mock               *CartRepositoryMock
+	defaultExpectation *CartRepositoryMockUpdateCartExpectation
+	expectations       []*CartRepositoryMockUpdateCartExpectation
+
+	callArgs []*CartRepositoryMockUpdateCartParams
+	mutex    sync.RWMutex
+
+	expectedInvocations       uint64
+	expectedInvocationsOrigin string
+}
+
+// CartRepositoryMockUpdateCartExpectation specifies expectation struct of the CartRepository.UpdateCart
+type CartRepositoryMockUpdateCartExpectation struct {
+	mock               *CartRepositoryMock
+	params             *CartRepositoryMockUpdateCartParams
+	paramPtrs          *CartRepositoryMockUpdateCartParamPtrs
+	expectationOrigins CartRepositoryMockUpdateCartExpectationOrigins
+	results            *CartRepositoryMockUpdateCartResults
+	returnOrigin       string
+	Counter            uint64
+}
+
+// CartRepositoryMockUpdateCartParams contains parameters of the CartRepository.UpdateCart
+type CartRepositoryMockUpdateCartParams struct {
+	ctx    context.Context
+	userID int64
+	update func(cart *models.Cart) error
+}
+
+// CartRepositoryMockUpdateCartParamPtrs contains pointers to parameters of the CartRepository.UpdateCart
+type CartRepositoryMockUpdateCartParamPtrs struct {
+	ctx    *context.Context
+	userID *int64
+	update *func(cart *models.Cart) error
+}
+
+// CartRepositoryMockUpdateCartResults contains results of the CartRepository.UpdateCart
+type CartRepositoryMockUpdateCartResults struct {
+	err error
+}
+
+// CartRepositoryMockUpdateCartOrigins contains origins of expectations of the CartRepository.UpdateCart
+type CartRepositoryMockUpdateCartExpectationOrigins struct {
+	origin       string
+	originCtx    string
+	originUserID string
+	originUpdate string
+}
+
+// Marks this method to be optional. The default behavior of any method with Return() is '1 or more', meaning
+// the test will fail minimock's automatic final call check if the mocked method was not called at least once.
+// Optional() makes method check to work in '0 or more' mode.
+// It is NOT RECOMMENDED to use this option unless you really need it, as default behaviour helps to
+// catch the problems when the expected method call is totally skipped during test run.
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) Optional() *mCartRepositoryMockUpdateCart {
+	mmUpdateCart.optional = true
+	return mmUpdateCart
+}
+
+// Expect sets up expected params for CartRepository.UpdateCart
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) Expect(ctx context.Context, userID int64, update func(cart *models.Cart) error) *mCartRepositoryMockUpdateCart {
+	if mmUpdateCart.mock.funcUpdateCart != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Set")
+	}
+
+	if mmUpdateCart.defaultExpectation == nil {
+		mmUpdateCart.defaultExpectation = &CartRepositoryMockUpdateCartExpectation{}
+	}
+
+	if mmUpdateCart.defaultExpectation.paramPtrs != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by ExpectParams functions")
+	}
+
+	mmUpdateCart.defaultExpectation.params = &CartRepositoryMockUpdateCartParams{ctx, userID, update}
+	mmUpdateCart.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
+	for _, e := range mmUpdateCart.expectations {
+		if minimock.Equal(e.params, mmUpdateCart.defaultExpectation.params) {
+			mmUpdateCart.mock.t.Fatalf("Expectation set by When has same params: %#v", *mmUpdateCart.defaultExpectation.params)
+		}
+	}
+
+	return mmUpdateCart
+}
+
+// ExpectCtxParam1 sets up expected param ctx for CartRepository.UpdateCart
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) ExpectCtxParam1(ctx context.Context) *mCartRepositoryMockUpdateCart {
+	if mmUpdateCart.mock.funcUpdateCart != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Set")
+	}
+
+	if mmUpdateCart.defaultExpectation == nil {
+		mmUpdateCart.defaultExpectation = &CartRepositoryMockUpdateCartExpectation{}
+	}
+
+	if mmUpdateCart.defaultExpectation.params != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Expect")
+	}
+
+	if mmUpdateCart.defaultExpectation.paramPtrs == nil {
+		mmUpdateCart.defaultExpectation.paramPtrs = &CartRepositoryMockUpdateCartParamPtrs{}
+	}
+	mmUpdateCart.defaultExpectation.paramPtrs.ctx = &ctx
+	mmUpdateCart.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmUpdateCart
+}
+
+// ExpectUserIDParam2 sets up expected param userID for CartRepository.UpdateCart
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) ExpectUserIDParam2(userID int64) *mCartRepositoryMockUpdateCart {
+	if mmUpdateCart.mock.funcUpdateCart != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Set")
+	}
+
+	if mmUpdateCart.defaultExpectation == nil {
+		mmUpdateCart.defaultExpectation = &CartRepositoryMockUpdateCartExpectation{}
+	}
+
+	if mmUpdateCart.defaultExpectation.params != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Expect")
+	}
+
+	if mmUpdateCart.defaultExpectation.paramPtrs == nil {
+		mmUpdateCart.defaultExpectation.paramPtrs = &CartRepositoryMockUpdateCartParamPtrs{}
+	}
+	mmUpdateCart.defaultExpectation.paramPtrs.userID = &userID
+	mmUpdateCart.defaultExpectation.expectationOrigins.originUserID = minimock.CallerInfo(1)
+
+	return mmUpdateCart
+}
+
+// ExpectUpdateParam3 sets up expected param update for CartRepository.UpdateCart
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) ExpectUpdateParam3(update func(cart *models.Cart) error) *mCartRepositoryMockUpdateCart {
+	if mmUpdateCart.mock.funcUpdateCart != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Set")
+	}
+
+	if mmUpdateCart.defaultExpectation == nil {
+		mmUpdateCart.defaultExpectation = &CartRepositoryMockUpdateCartExpectation{}
+	}
+
+	if mmUpdateCart.defaultExpectation.params != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Expect")
+	}
+
+	if mmUpdateCart.defaultExpectation.paramPtrs == nil {
+		mmUpdateCart.defaultExpectation.paramPtrs = &CartRepositoryMockUpdateCartParamPtrs{}
+	}
+	mmUpdateCart.defaultExpectation.paramPtrs.update = &update
+	mmUpdateCart.defaultExpectation.expectationOrigins.originUpdate = minimock.CallerInfo(1)
+
+	return mmUpdateCart
+}
+
+// Inspect accepts an inspector function that has same arguments as the CartRepository.UpdateCart
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) Inspect(f func(ctx context.Context, userID int64, update func(cart *models.Cart) error)) *mCartRepositoryMockUpdateCart {
+	if mmUpdateCart.mock.inspectFuncUpdateCart != nil {
+		mmUpdateCart.mock.t.Fatalf("Inspect function is already set for CartRepositoryMock.UpdateCart")
+	}
+
+	mmUpdateCart.mock.inspectFuncUpdateCart = f
+
+	return mmUpdateCart
+}
+
+// Return sets up results that will be returned by CartRepository.UpdateCart
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) Return(err error) *CartRepositoryMock {
+	if mmUpdateCart.mock.funcUpdateCart != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Set")
+	}
+
+	if mmUpdateCart.defaultExpectation == nil {
+		mmUpdateCart.defaultExpectation = &CartRepositoryMockUpdateCartExpectation{mock: mmUpdateCart.mock}
+	}
+	mmUpdateCart.defaultExpectation.results = &CartRepositoryMockUpdateCartResults{err}
+	mmUpdateCart.defaultExpectation.returnOrigin = minimock.CallerInfo(1)
+	return mmUpdateCart.mock
+}
+
+// Set uses given function f to mock the CartRepository.UpdateCart method
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) Set(f func(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error)) *CartRepositoryMock {
+	if mmUpdateCart.defaultExpectation != nil {
+		mmUpdateCart.mock.t.Fatalf("Default expectation is already set for the CartRepository.UpdateCart method")
+	}
+
+	if len(mmUpdateCart.expectations) > 0 {
+		mmUpdateCart.mock.t.Fatalf("Some expectations are already set for the CartRepository.UpdateCart method")
+	}
+
+	mmUpdateCart.mock.funcUpdateCart = f
+	mmUpdateCart.mock.funcUpdateCartOrigin = minimock.CallerInfo(1)
+	return mmUpdateCart.mock
+}
+
+// When sets expectation for the CartRepository.UpdateCart which will trigger the result defined by the following
+// Then helper
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) When(ctx context.Context, userID int64, update func(cart *models.Cart) error) *CartRepositoryMockUpdateCartExpectation {
+	if mmUpdateCart.mock.funcUpdateCart != nil {
+		mmUpdateCart.mock.t.Fatalf("CartRepositoryMock.UpdateCart mock is already set by Set")
+	}
+
+	expectation := &CartRepositoryMockUpdateCartExpectation{
+		mock:               mmUpdateCart.mock,
+		params:             &CartRepositoryMockUpdateCartParams{ctx, userID, update},
+		expectationOrigins: CartRepositoryMockUpdateCartExpectationOrigins{origin: minimock.CallerInfo(1)},
+	}
+	mmUpdateCart.expectations = append(mmUpdateCart.expectations, expectation)
+	return expectation
+}
+
+// Then sets up CartRepository.UpdateCart return parameters for the expectation previously defined by the When method
+func (e *CartRepositoryMockUpdateCartExpectation) Then(err error) *CartRepositoryMock {
+	e.results = &CartRepositoryMockUpdateCartResults{err}
+	return e.mock
+}
+
+// Times sets number of times CartRepository.UpdateCart should be invoked
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) Times(n uint64) *mCartRepositoryMockUpdateCart {
+	if n == 0 {
+		mmUpdateCart.mock.t.Fatalf("Times of CartRepositoryMock.UpdateCart mock can not be zero")
+	}
+	mm_atomic.StoreUint64(&mmUpdateCart.expectedInvocations, n)
+	mmUpdateCart.expectedInvocationsOrigin = minimock.CallerInfo(1)
+	return mmUpdateCart
+}
+
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) invocationsDone() bool {
+	if len(mmUpdateCart.expectations) == 0 && mmUpdateCart.defaultExpectation == nil && mmUpdateCart.mock.funcUpdateCart == nil {
+		return true
+	}
+
+	totalInvocations := mm_atomic.LoadUint64(&mmUpdateCart.mock.afterUpdateCartCounter)
+	expectedInvocations := mm_atomic.LoadUint64(&mmUpdateCart.expectedInvocations)
+
+	return totalInvocations > 0 && (expectedInvocations == 0 || expectedInvocations == totalInvocations)
+}
+
+// UpdateCart implements mm_cart.CartRepository
+func (mmUpdateCart *CartRepositoryMock) UpdateCart(ctx context.Context, userID int64, update func(cart *models.Cart) error) (err error) {
+	mm_atomic.AddUint64(&mmUpdateCart.beforeUpdateCartCounter, 1)
+	defer mm_atomic.AddUint64(&mmUpdateCart.afterUpdateCartCounter, 1)
+
+	mmUpdateCart.t.Helper()
+
+	if mmUpdateCart.inspectFuncUpdateCart != nil {
+		mmUpdateCart.inspectFuncUpdateCart(ctx, userID, update)
+	}
+
+	mm_params := CartRepositoryMockUpdateCartParams{ctx, userID, update}
+
+	// Record call args
+	mmUpdateCart.UpdateCartMock.mutex.Lock()
+	mmUpdateCart.UpdateCartMock.callArgs = append(mmUpdateCart.UpdateCartMock.callArgs, &mm_params)
+	mmUpdateCart.UpdateCartMock.mutex.Unlock()
+
+	for _, e := range mmUpdateCart.UpdateCartMock.expectations {
+		if minimock.Equal(*e.params, mm_params) {
+			mm_atomic.AddUint64(&e.Counter, 1)
+			return e.results.err
+		}
+	}
+
+	if mmUpdateCart.UpdateCartMock.defaultExpectation != nil {
+		mm_atomic.AddUint64(&mmUpdateCart.UpdateCartMock.defaultExpectation.Counter, 1)
+		mm_want := mmUpdateCart.UpdateCartMock.defaultExpectation.params
+		mm_want_ptrs := mmUpdateCart.UpdateCartMock.defaultExpectation.paramPtrs
+
+		mm_got := CartRepositoryMockUpdateCartParams{ctx, userID, update}
+
+		if mm_want_ptrs != nil {
+
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmUpdateCart.t.Errorf("CartRepositoryMock.UpdateCart got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmUpdateCart.UpdateCartMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
+			if mm_want_ptrs.userID != nil && !minimock.Equal(*mm_want_ptrs.userID, mm_got.userID) {
+				mmUpdateCart.t.Errorf("CartRepositoryMock.UpdateCart got unexpected parameter userID, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmUpdateCart.UpdateCartMock.defaultExpectation.expectationOrigins.originUserID, *mm_want_ptrs.userID, mm_got.userID, minimock.Diff(*mm_want_ptrs.userID, mm_got.userID))
+			}
+
+			if mm_want_ptrs.update != nil && !minimock.Equal(*mm_want_ptrs.update, mm_got.update) {
+				mmUpdateCart.t.Errorf("CartRepositoryMock.UpdateCart got unexpected parameter update, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmUpdateCart.UpdateCartMock.defaultExpectation.expectationOrigins.originUpdate, *mm_want_ptrs.update, mm_got.update, minimock.Diff(*mm_want_ptrs.update, mm_got.update))
+			}
+
+		} else if mm_want != nil && !minimock.Equal(*mm_want, mm_got) {
+			mmUpdateCart.t.Errorf("CartRepositoryMock.UpdateCart got unexpected parameters, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+				mmUpdateCart.UpdateCartMock.defaultExpectation.expectationOrigins.origin, *mm_want, mm_got, minimock.Diff(*mm_want, mm_got))
+		}
+
+		mm_results := mmUpdateCart.UpdateCartMock.defaultExpectation.results
+		if mm_results == nil {
+			mmUpdateCart.t.Fatal("No results are set for the CartRepositoryMock.UpdateCart")
+		}
+		return (*mm_results).err
+	}
+	if mmUpdateCart.funcUpdateCart != nil {
+		return mmUpdateCart.funcUpdateCart(ctx, userID, update)
+	}
+	mmUpdateCart.t.Fatalf("Unexpected call to CartRepositoryMock.UpdateCart. %v %v %v", ctx, userID, update)
+	return
+}
+
+// UpdateCartAfterCounter returns a count of finished CartRepositoryMock.UpdateCart invocations
+func (mmUpdateCart *CartRepositoryMock) UpdateCartAfterCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmUpdateCart.afterUpdateCartCounter)
+}
+
+// UpdateCartBeforeCounter returns a count of CartRepositoryMock.UpdateCart invocations
+func (mmUpdateCart *CartRepositoryMock) UpdateCartBeforeCounter() uint64 {
+	return mm_atomic.LoadUint64(&mmUpdateCart.beforeUpdateCartCounter)
+}
+
+// Calls returns a list of arguments used in each call to CartRepositoryMock.UpdateCart.
+// The list is in the same order as the calls were made (i.e. recent calls have a higher index)
+func (mmUpdateCart *mCartRepositoryMockUpdateCart) Calls() []*CartRepositoryMockUpdateCartParams {
+	mmUpdateCart.mutex.RLock()
+
+	argCopy := make([]*CartRepositoryMockUpdateCartParams, len(mmUpdateCart.callArgs))
+	copy(argCopy, mmUpdateCart.callArgs)
+
+	mmUpdateCart.mutex.RUnlock()
+
+	return argCopy
+}
+
+// MinimockUpdateCartDone returns true if the count of the UpdateCart invocations corresponds
+// the number of defined expectations
+func (m *CartRepositoryMock) MinimockUpdateCartDone() bool {
+	if m.UpdateCartMock.optional {
+		// Optional methods provide '0 or more' call count restriction.
+		return true
+	}
+
+	for _, e := range m.UpdateCartMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			return false
+		}
+	}
+
+	return m.UpdateCartMock.invocationsDone()
+}
+
+// MinimockUpdateCartInspect logs each unmet expectation
+func (m *CartRepositoryMock) MinimockUpdateCartInspect() {
+	for _, e := range m.UpdateCartMock.expectations {
+		if mm_atomic.LoadUint64(&e.Counter) < 1 {
+			m.t.Errorf("Expected call to CartRepositoryMock.UpdateCart at\n%s with params: %#v", e.expectationOrigins.origin, *e.params)
+		}
+	}
+
+	afterUpdateCartCounter := mm_atomic.LoadUint64(&m.afterUpdateCartCounter)
+	// if default expectation was set then invocations count should be greater than zero
+	if m.UpdateCartMock.defaultExpectation != nil && afterUpdateCartCounter < 1 {
+		if m.UpdateCartMock.defaultExpectation.params == nil {
+			m.t.Errorf("Expected call to CartRepositoryMock.UpdateCart at\n%s", m.UpdateCartMock.defaultExpectation.returnOrigin)
+		} else {
+			m.t.Errorf("Expected call to CartRepositoryMock.UpdateCart at\n%s with params: %#v", m.UpdateCartMock.defaultExpectation.expectationOrigins.origin, *m.UpdateCartMock.defaultExpectation.params)
+		}
+	}
+	// if func was set then invocations count should be greater than zero
+	if m.funcUpdateCart != nil && afterUpdateCartCounter < 1 {
+		m.t.Errorf("Expected call to CartRepositoryMock.UpdateCart at\n%s", m.funcUpdateCartOrigin)
+	}
+
+	if !m.UpdateCartMock.invocationsDone() && afterUpdateCartCounter > 0 {
+		m.t.Errorf("Expected %d calls to CartRepositoryMock.UpdateCart at\n%s but found %d calls",
+			mm_atomic.LoadUint64(&m.UpdateCartMock.expectedInvocations), m.UpdateCartMock.expectedInvocationsOrigin, afterUpdateCartCounter)
+	}
+}
+
 // MinimockFinish checks that all mocked methods have been called the expected number of times
 func (m *CartRepositoryMock) MinimockFinish() {
 	m.finishOnce.Do(func() {
 		if !m.minimockDone() {
 			m.MinimockCreateCartInspect()
 
+			m.MinimockDeleteExpiredInspect()
+
 			m.MinimockGetCartInspect()
 
+			m.MinimockListIdleInspect()
+
+			m.MinimockRefreshCartInspect()
+
 			m.MinimockSaveCartInspect()
+
+			m.MinimockUpdateCartInspect()
 		}
 	})
 }
@@ -1027,6 +2695,10 @@ func (m *CartRepositoryMock) minimockDone() bool {
 	done := true
 	return done &&
 		m.MinimockCreateCartDone() &&
+		m.MinimockDeleteExpiredDone() &&
 		m.MinimockGetCartDone() &&
-		m.MinimockSaveCartDone()
+		m.MinimockListIdleDone() &&
+		m.MinimockRefreshCartDone() &&
+		m.MinimockSaveCartDone() &&
+		m.MinimockUpdateCartDone()
 }
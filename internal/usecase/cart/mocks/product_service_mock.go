@@ -3,6 +3,7 @@
 package mocks
 
 import (
+	"context"
 	"route256/cart/internal/domain/models"
 	"sync"
 	mm_atomic "sync/atomic"
@@ -16,9 +17,9 @@ type ProductServiceMock struct {
 	t          minimock.Tester
 	finishOnce sync.Once
 
-	funcGetProduct          func(sku uint32) (pp1 *models.Product, err error)
+	funcGetProduct          func(ctx context.Context, sku uint32) (pp1 *models.Product, err error)
 	funcGetProductOrigin    string
-	inspectFuncGetProduct   func(sku uint32)
+	inspectFuncGetProduct   func(ctx context.Context, sku uint32)
 	afterGetProductCounter  uint64
 	beforeGetProductCounter uint64
 	GetProductMock          mProductServiceMockGetProduct
@@ -66,11 +67,13 @@ type ProductServiceMockGetProductExpectation struct {
 
 // ProductServiceMockGetProductParams contains parameters of the ProductService.GetProduct
 type ProductServiceMockGetProductParams struct {
+	ctx context.Context
 	sku uint32
 }
 
 // ProductServiceMockGetProductParamPtrs contains pointers to parameters of the ProductService.GetProduct
 type ProductServiceMockGetProductParamPtrs struct {
+	ctx *context.Context
 	sku *uint32
 }
 
@@ -83,6 +86,7 @@ type ProductServiceMockGetProductResults struct {
 // ProductServiceMockGetProductOrigins contains origins of expectations of the ProductService.GetProduct
 type ProductServiceMockGetProductExpectationOrigins struct {
 	origin    string
+	originCtx string
 	originSku string
 }
 
@@ -97,7 +101,7 @@ func (mmGetProduct *mProductServiceMockGetProduct) Optional() *mProductServiceMo
 }
 
 // Expect sets up expected params for ProductService.GetProduct
-func (mmGetProduct *mProductServiceMockGetProduct) Expect(sku uint32) *mProductServiceMockGetProduct {
+func (mmGetProduct *mProductServiceMockGetProduct) Expect(ctx context.Context, sku uint32) *mProductServiceMockGetProduct {
 	if mmGetProduct.mock.funcGetProduct != nil {
 		mmGetProduct.mock.t.Fatalf("ProductServiceMock.GetProduct mock is already set by Set")
 	}
@@ -110,7 +114,7 @@ func (mmGetProduct *mProductServiceMockGetProduct) Expect(sku uint32) *mProductS
 		mmGetProduct.mock.t.Fatalf("ProductServiceMock.GetProduct mock is already set by ExpectParams functions")
 	}
 
-	mmGetProduct.defaultExpectation.params = &ProductServiceMockGetProductParams{sku}
+	mmGetProduct.defaultExpectation.params = &ProductServiceMockGetProductParams{ctx, sku}
 	mmGetProduct.defaultExpectation.expectationOrigins.origin = minimock.CallerInfo(1)
 	for _, e := range mmGetProduct.expectations {
 		if minimock.Equal(e.params, mmGetProduct.defaultExpectation.params) {
@@ -121,8 +125,31 @@ func (mmGetProduct *mProductServiceMockGetProduct) Expect(sku uint32) *mProductS
 	return mmGetProduct
 }
 
-// ExpectSkuParam1 sets up expected param sku for ProductService.GetProduct
-func (mmGetProduct *mProductServiceMockGetProduct) ExpectSkuParam1(sku uint32) *mProductServiceMockGetProduct {
+// ExpectCtxParam1 sets up expected param ctx for ProductService.GetProduct
+func (mmGetProduct *mProductServiceMockGetProduct) ExpectCtxParam1(ctx context.Context) *mProductServiceMockGetProduct {
+	if mmGetProduct.mock.funcGetProduct != nil {
+		mmGetProduct.mock.t.Fatalf("ProductServiceMock.GetProduct mock is already set by Set")
+	}
+
+	if mmGetProduct.defaultExpectation == nil {
+		mmGetProduct.defaultExpectation = &ProductServiceMockGetProductExpectation{}
+	}
+
+	if mmGetProduct.defaultExpectation.params != nil {
+		mmGetProduct.mock.t.Fatalf("ProductServiceMock.GetProduct mock is already set by Expect")
+	}
+
+	if mmGetProduct.defaultExpectation.paramPtrs == nil {
+		mmGetProduct.defaultExpectation.paramPtrs = &ProductServiceMockGetProductParamPtrs{}
+	}
+	mmGetProduct.defaultExpectation.paramPtrs.ctx = &ctx
+	mmGetProduct.defaultExpectation.expectationOrigins.originCtx = minimock.CallerInfo(1)
+
+	return mmGetProduct
+}
+
+// ExpectSkuParam2 sets up expected param sku for ProductService.GetProduct
+func (mmGetProduct *mProductServiceMockGetProduct) ExpectSkuParam2(sku uint32) *mProductServiceMockGetProduct {
 	if mmGetProduct.mock.funcGetProduct != nil {
 		mmGetProduct.mock.t.Fatalf("ProductServiceMock.GetProduct mock is already set by Set")
 	}
@@ -145,7 +172,7 @@ func (mmGetProduct *mProductServiceMockGetProduct) ExpectSkuParam1(sku uint32) *
 }
 
 // Inspect accepts an inspector function that has same arguments as the ProductService.GetProduct
-func (mmGetProduct *mProductServiceMockGetProduct) Inspect(f func(sku uint32)) *mProductServiceMockGetProduct {
+func (mmGetProduct *mProductServiceMockGetProduct) Inspect(f func(ctx context.Context, sku uint32)) *mProductServiceMockGetProduct {
 	if mmGetProduct.mock.inspectFuncGetProduct != nil {
 		mmGetProduct.mock.t.Fatalf("Inspect function is already set for ProductServiceMock.GetProduct")
 	}
@@ -170,7 +197,7 @@ func (mmGetProduct *mProductServiceMockGetProduct) Return(pp1 *models.Product, e
 }
 
 // Set uses given function f to mock the ProductService.GetProduct method
-func (mmGetProduct *mProductServiceMockGetProduct) Set(f func(sku uint32) (pp1 *models.Product, err error)) *ProductServiceMock {
+func (mmGetProduct *mProductServiceMockGetProduct) Set(f func(ctx context.Context, sku uint32) (pp1 *models.Product, err error)) *ProductServiceMock {
 	if mmGetProduct.defaultExpectation != nil {
 		mmGetProduct.mock.t.Fatalf("Default expectation is already set for the ProductService.GetProduct method")
 	}
@@ -186,14 +213,14 @@ func (mmGetProduct *mProductServiceMockGetProduct) Set(f func(sku uint32) (pp1 *
 
 // When sets expectation for the ProductService.GetProduct which will trigger the result defined by the following
 // Then helper
-func (mmGetProduct *mProductServiceMockGetProduct) When(sku uint32) *ProductServiceMockGetProductExpectation {
+func (mmGetProduct *mProductServiceMockGetProduct) When(ctx context.Context, sku uint32) *ProductServiceMockGetProductExpectation {
 	if mmGetProduct.mock.funcGetProduct != nil {
 		mmGetProduct.mock.t.Fatalf("ProductServiceMock.GetProduct mock is already set by Set")
 	}
 
 	expectation := &ProductServiceMockGetProductExpectation{
 		mock:               mmGetProduct.mock,
-		params:             &ProductServiceMockGetProductParams{sku},
+		params:             &ProductServiceMockGetProductParams{ctx, sku},
 		expectationOrigins: ProductServiceMockGetProductExpectationOrigins{origin: minimock.CallerInfo(1)},
 	}
 	mmGetProduct.expectations = append(mmGetProduct.expectations, expectation)
@@ -228,17 +255,17 @@ func (mmGetProduct *mProductServiceMockGetProduct) invocationsDone() bool {
 }
 
 // GetProduct implements mm_cart.ProductService
-func (mmGetProduct *ProductServiceMock) GetProduct(sku uint32) (pp1 *models.Product, err error) {
+func (mmGetProduct *ProductServiceMock) GetProduct(ctx context.Context, sku uint32) (pp1 *models.Product, err error) {
 	mm_atomic.AddUint64(&mmGetProduct.beforeGetProductCounter, 1)
 	defer mm_atomic.AddUint64(&mmGetProduct.afterGetProductCounter, 1)
 
 	mmGetProduct.t.Helper()
 
 	if mmGetProduct.inspectFuncGetProduct != nil {
-		mmGetProduct.inspectFuncGetProduct(sku)
+		mmGetProduct.inspectFuncGetProduct(ctx, sku)
 	}
 
-	mm_params := ProductServiceMockGetProductParams{sku}
+	mm_params := ProductServiceMockGetProductParams{ctx, sku}
 
 	// Record call args
 	mmGetProduct.GetProductMock.mutex.Lock()
@@ -257,10 +284,15 @@ func (mmGetProduct *ProductServiceMock) GetProduct(sku uint32) (pp1 *models.Prod
 		mm_want := mmGetProduct.GetProductMock.defaultExpectation.params
 		mm_want_ptrs := mmGetProduct.GetProductMock.defaultExpectation.paramPtrs
 
-		mm_got := ProductServiceMockGetProductParams{sku}
+		mm_got := ProductServiceMockGetProductParams{ctx, sku}
 
 		if mm_want_ptrs != nil {
 
+			if mm_want_ptrs.ctx != nil && !minimock.Equal(*mm_want_ptrs.ctx, mm_got.ctx) {
+				mmGetProduct.t.Errorf("ProductServiceMock.GetProduct got unexpected parameter ctx, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
+					mmGetProduct.GetProductMock.defaultExpectation.expectationOrigins.originCtx, *mm_want_ptrs.ctx, mm_got.ctx, minimock.Diff(*mm_want_ptrs.ctx, mm_got.ctx))
+			}
+
 			if mm_want_ptrs.sku != nil && !minimock.Equal(*mm_want_ptrs.sku, mm_got.sku) {
 				mmGetProduct.t.Errorf("ProductServiceMock.GetProduct got unexpected parameter sku, expected at\n%s:\nwant: %#v\n got: %#v%s\n",
 					mmGetProduct.GetProductMock.defaultExpectation.expectationOrigins.originSku, *mm_want_ptrs.sku, mm_got.sku, minimock.Diff(*mm_want_ptrs.sku, mm_got.sku))
@@ -278,9 +310,9 @@ func (mmGetProduct *ProductServiceMock) GetProduct(sku uint32) (pp1 *models.Prod
 		return (*mm_results).pp1, (*mm_results).err
 	}
 	if mmGetProduct.funcGetProduct != nil {
-		return mmGetProduct.funcGetProduct(sku)
+		return mmGetProduct.funcGetProduct(ctx, sku)
 	}
-	mmGetProduct.t.Fatalf("Unexpected call to ProductServiceMock.GetProduct. %v", sku)
+	mmGetProduct.t.Fatalf("Unexpected call to ProductServiceMock.GetProduct. %v %v", ctx, sku)
 	return
 }
 
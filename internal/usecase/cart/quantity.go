@@ -0,0 +1,143 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"route256/cart/internal/domain/models"
+)
+
+// errLookupNeeded aborts a quantity update that turns out to be an increase
+// before the product and its stock were looked up
+var errLookupNeeded = errors.New("quantity increase needs a product lookup")
+
+// SetItemQuantity sets the quantity of an item in the user's cart, adding the
+// item if the cart has none; zero removes it
+func (s *CartService) SetItemQuantity(ctx context.Context, userID int64, sku uint32, quantity uint16, expectedVersion uint64) error {
+	// Whether this is an increase depends on the cart, so look up lazily
+	return s.updateQuantity(ctx, userID, sku, expectedVersion, false, func(uint64) uint64 {
+		return uint64(quantity)
+	})
+}
+
+// ChangeItemQuantity adds delta to the quantity of an item in the user's
+// cart. The item is added if the cart has none and removed if its quantity
+// drops to zero or below.
+func (s *CartService) ChangeItemQuantity(ctx context.Context, userID int64, sku uint32, delta int32, expectedVersion uint64) error {
+	return s.updateQuantity(ctx, userID, sku, expectedVersion, delta > 0, func(current uint64) uint64 {
+		if delta < 0 {
+			return current - min(current, uint64(-int64(delta)))
+		}
+		return current + uint64(delta)
+	})
+}
+
+// updateQuantity sets the quantity of sku in the user's cart to what quantity
+// returns for the current one. Increases are checked against the cart limits
+// and the stock, which needs the product and its stock looked up: right away
+// if lookup is set, otherwise only once the update turns out to be an
+// increase. Leaving the quantity as it is succeeds without storing the cart.
+func (s *CartService) updateQuantity(
+	ctx context.Context,
+	userID int64,
+	sku uint32,
+	expectedVersion uint64,
+	lookup bool,
+	quantity func(current uint64) uint64,
+) error {
+	var (
+		product *models.Product
+		stock   uint64
+		err     error
+	)
+	if lookup {
+		if product, stock, err = s.lookupStock(ctx, sku); err != nil {
+			return err
+		}
+	}
+
+	err = s.setQuantity(ctx, userID, sku, expectedVersion, product, stock, quantity)
+	if errors.Is(err, errLookupNeeded) {
+		if product, stock, err = s.lookupStock(ctx, sku); err != nil {
+			return err
+		}
+		err = s.setQuantity(ctx, userID, sku, expectedVersion, product, stock, quantity)
+	}
+	if errors.Is(err, errNoChanges) {
+		return nil
+	}
+
+	return err
+}
+
+// lookupStock gets the product with sku and its stock
+func (s *CartService) lookupStock(ctx context.Context, sku uint32) (*models.Product, uint64, error) {
+	// Get product info
+	product, err := s.productService.GetProduct(ctx, sku)
+	if err != nil {
+		// Cancellation is not a verdict on the product
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, 0, ctxErr
+		}
+		// Neither is an unreachable product service
+		if errors.Is(err, models.ErrDependencyUnavailable) {
+			return nil, 0, err
+		}
+		return nil, 0, models.ErrProductNotFound
+	}
+
+	// Check stock quantity
+	stock, err := s.lomsClient.GetStocksInfo(ctx, sku)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return product, stock, nil
+}
+
+// setQuantity stores the new quantity of sku in the user's cart. It fails with
+// errLookupNeeded if the quantity grows and product is nil.
+func (s *CartService) setQuantity(
+	ctx context.Context,
+	userID int64,
+	sku uint32,
+	expectedVersion uint64,
+	product *models.Product,
+	stock uint64,
+	quantity func(current uint64) uint64,
+) error {
+	return s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		if err := checkVersion(cart, expectedVersion); err != nil {
+			return err
+		}
+
+		current := uint64(cart.Quantity(sku))
+		target := quantity(current)
+		if target == current {
+			return errNoChanges
+		}
+
+		var price models.Money
+		if target > current {
+			if product == nil {
+				return errLookupNeeded
+			}
+
+			if err := s.limits.CheckQuantity(cart, sku, target); err != nil {
+				return err
+			}
+
+			if target > stock {
+				return ErrInsufficientStock
+			}
+			price = product.Price
+		}
+
+		// Limits never allow more than math.MaxUint16
+		cart.SetQuantity(sku, uint16(min(target, math.MaxUint16)), price)
+
+		// Calculate total price
+		return cart.CalculateTotalPrice(s.converter(ctx))
+	})
+}
@@ -0,0 +1,38 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/domain/ports"
+)
+
+// SetRegion sets the region the user's cart is delivered to, which decides
+// its tax rates
+func (s *CartService) SetRegion(ctx context.Context, userID int64, region string, expectedVersion uint64) error {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if s.taxes == nil || !s.taxes.HasRegion(ctx, region) {
+		return fmt.Errorf("%w: %s", ports.ErrRegionNotFound, region)
+	}
+
+	err := s.repo.UpdateCart(ctx, userID, func(cart *models.Cart) error {
+		if err := checkVersion(cart, expectedVersion); err != nil {
+			return err
+		}
+
+		if cart.Region == region {
+			return errNoChanges
+		}
+
+		cart.Region = region
+		return nil
+	})
+	if errors.Is(err, errNoChanges) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,63 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// compensationTimeout bounds the time all compensations of a failed saga may take
+const compensationTimeout = 30 * time.Second
+
+// sagaStep is a single step of a saga together with the action that undoes it
+type sagaStep struct {
+	name string
+
+	// action performs the step
+	action func(ctx context.Context) error
+
+	// compensate undoes a completed action; nil if the step needs no undo
+	compensate func(ctx context.Context) error
+}
+
+// runSaga executes steps in order. When a step fails, the compensations of all
+// previously completed steps run in reverse order. Compensations ignore the
+// cancellation of ctx so that an aborted request still cleans up after itself,
+// but are bounded by compensationTimeout.
+// The returned error wraps the step failure and any compensation failures.
+func runSaga(ctx context.Context, steps []sagaStep) error {
+	for i, step := range steps {
+		err := step.action(ctx)
+		if err == nil {
+			continue
+		}
+
+		err = fmt.Errorf("%s: %w", step.name, err)
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), compensationTimeout)
+		defer cancel()
+		return errors.Join(err, compensate(ctx, steps[:i]))
+	}
+
+	return nil
+}
+
+// compensate undoes completed steps in reverse order
+func compensate(ctx context.Context, completed []sagaStep) error {
+	var errs []error
+	for i := len(completed) - 1; i >= 0; i-- {
+		step := completed[i]
+		if step.compensate == nil {
+			continue
+		}
+
+		if err := step.compensate(ctx); err != nil {
+			log.Printf("Failed to compensate saga step %q: %v", step.name, err)
+			errs = append(errs, fmt.Errorf("compensate %s: %w", step.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
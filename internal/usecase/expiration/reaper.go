@@ -0,0 +1,104 @@
+package expiration
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"route256/cart/internal/domain/ports"
+)
+
+// DefaultBatchSize is the number of carts deleted at once when no batch size is configured
+const DefaultBatchSize = 100
+
+// Reaper periodically deletes carts that have not changed for longer than a TTL
+type Reaper struct {
+	repo      ports.CartRepository
+	ttl       time.Duration
+	interval  time.Duration
+	batchSize int
+	now       func() time.Time
+
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// NewReaper creates a reaper deleting carts of repo unchanged for ttl every
+// interval, batchSize carts at a time
+func NewReaper(repo ports.CartRepository, ttl, interval time.Duration, batchSize int) *Reaper {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	return &Reaper{
+		repo:      repo,
+		ttl:       ttl,
+		interval:  interval,
+		batchSize: batchSize,
+		now:       time.Now,
+	}
+}
+
+// Start runs Sweep every interval in the background until Stop is called
+func (r *Reaper) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.done.Add(1)
+	go func() {
+		defer r.done.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			deleted, err := r.Sweep(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to delete expired carts: %v", err)
+			}
+			if deleted > 0 {
+				log.Printf("Deleted %d expired carts", deleted)
+			}
+		}
+	}()
+}
+
+// Stop stops the background sweeps, interrupting a running one between
+// batches, and waits for them to finish
+func (r *Reaper) Stop() {
+	if r.cancel == nil {
+		return
+	}
+
+	r.cancel()
+	r.done.Wait()
+}
+
+// Sweep deletes the carts that expired by now in batches, so that the
+// repository is not locked for long, and returns how many it deleted
+func (r *Reaper) Sweep(ctx context.Context) (int, error) {
+	cutoff := r.now().Add(-r.ttl)
+
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		deleted, err := r.repo.DeleteExpired(ctx, cutoff, r.batchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < r.batchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package expiration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+	"route256/cart/internal/infrastructure/repository/inmemory"
+)
+
+func TestReaper_Sweep(t *testing.T) {
+	repo := inmemory.NewCartRepository()
+	for userID := int64(1); userID <= 5; userID++ {
+		require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(userID)))
+	}
+
+	reaper := NewReaper(repo, time.Hour, time.Minute, 2)
+
+	deleted, err := reaper.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	// Every cart expires an hour after it was stored
+	reaper.now = func() time.Time { return time.Now().Add(time.Hour + time.Second) }
+
+	deleted, err = reaper.Sweep(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, deleted)
+
+	_, err = repo.GetCart(context.Background(), 1)
+	assert.ErrorIs(t, err, models.ErrCartNotFound)
+}
+
+func TestReaper_Start(t *testing.T) {
+	repo := inmemory.NewCartRepository()
+	require.NoError(t, repo.CreateCart(context.Background(), models.NewCart(1)))
+
+	reaper := NewReaper(repo, time.Millisecond, time.Millisecond, 0)
+	reaper.Start()
+	defer reaper.Stop()
+
+	assert.Eventually(t, func() bool {
+		_, err := repo.GetCart(context.Background(), 1)
+		return err != nil
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,173 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"route256/cart/internal/domain/models"
+)
+
+// Promotion is a discount offered on carts
+type Promotion struct {
+	// ID uniquely identifies the promotion
+	ID string
+
+	// Description tells the customer what the promotion is
+	Description string
+
+	// Coupon is the code that activates the promotion;
+	// promotions without one apply to every cart
+	Coupon string
+
+	// Rule computes the discounts
+	Rule Rule
+}
+
+// Line is an item line of a cart as seen by a Rule
+type Line struct {
+	SKU      uint32
+	Quantity uint16
+
+	// UnitPrice is the price of one item
+	UnitPrice models.Money
+
+	// Payable is the price of all items less the discounts of promotions
+	// applied before; it never goes below zero
+	Payable models.Money
+}
+
+// Rule computes the discounts of a promotion
+type Rule interface {
+	// Discounts returns the discount on each line, in the currency of the
+	// lines and at most their payable amount, or nil if the rule does not
+	// apply. Amounts in other currencies are converted with convert.
+	Discounts(lines []Line, convert models.ConvertFunc) ([]models.Money, error)
+}
+
+// Engine applies promotions to carts
+type Engine struct {
+	promotions []Promotion
+	coupons    map[string]struct{}
+}
+
+// NewEngine creates an engine applying promotions in the given order:
+// each promotion discounts what is left to pay after the ones before it
+func NewEngine(promotions []Promotion) (*Engine, error) {
+	engine := &Engine{
+		promotions: make([]Promotion, 0, len(promotions)),
+		coupons:    make(map[string]struct{}),
+	}
+
+	ids := make(map[string]struct{}, len(promotions))
+	for _, p := range promotions {
+		if p.ID == "" {
+			return nil, errors.New("promotion without an id")
+		}
+		if _, ok := ids[p.ID]; ok {
+			return nil, fmt.Errorf("duplicate promotion %q", p.ID)
+		}
+		if p.Rule == nil {
+			return nil, fmt.Errorf("promotion %q has no rule", p.ID)
+		}
+		ids[p.ID] = struct{}{}
+
+		p.Coupon = NormalizeCoupon(p.Coupon)
+		if p.Coupon != "" {
+			engine.coupons[p.Coupon] = struct{}{}
+		}
+		engine.promotions = append(engine.promotions, p)
+	}
+
+	return engine, nil
+}
+
+// NormalizeCoupon returns the canonical form of a coupon code:
+// codes are case-insensitive and surrounding spaces are ignored
+func NormalizeCoupon(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsCoupon reports whether code activates any promotion
+func (e *Engine) IsCoupon(code string) bool {
+	_, ok := e.coupons[NormalizeCoupon(code)]
+	return ok
+}
+
+// Apply discounts the cart with every promotion that applies to it.
+// The total must already be calculated and all items must be priced in its
+// currency. On error the cart is left unchanged.
+func (e *Engine) Apply(cart *models.Cart, convert models.ConvertFunc) error {
+	currency := cart.TotalPrice.Currency
+
+	lines := make([]Line, len(cart.Items))
+	for i, item := range cart.Items {
+		if item.Price.Currency != currency {
+			return fmt.Errorf("%w: item %d is priced in %s, cart in %s",
+				models.ErrCurrencyMismatch, item.SKU, item.Price.Currency, currency)
+		}
+
+		total, err := item.LineTotal()
+		if err != nil {
+			return err
+		}
+
+		lines[i] = Line{
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: item.Price,
+			Payable:   total,
+		}
+	}
+
+	coupon := NormalizeCoupon(cart.Coupon)
+	discounts := make(map[uint32]models.Money, len(lines))
+	var applied []models.AppliedPromotion
+
+	for _, p := range e.promotions {
+		if p.Coupon != "" && p.Coupon != coupon {
+			continue
+		}
+
+		lineDiscounts, err := p.Rule.Discounts(lines, convert)
+		if err != nil {
+			return fmt.Errorf("promotion %s: %w", p.ID, err)
+		}
+		if lineDiscounts == nil {
+			continue
+		}
+		if len(lineDiscounts) != len(lines) {
+			return fmt.Errorf("promotion %s: %d discounts for %d lines", p.ID, len(lineDiscounts), len(lines))
+		}
+
+		total := models.NewMoney(0, currency)
+		for i, discount := range lineDiscounts {
+			if discount.IsZero() {
+				continue
+			}
+
+			if discount.Amount < 0 || discount.Amount > lines[i].Payable.Amount {
+				return fmt.Errorf("promotion %s: invalid discount %s on sku %d with %s payable", p.ID, discount, lines[i].SKU, lines[i].Payable)
+			}
+
+			lines[i].Payable, err = lines[i].Payable.Sub(discount)
+			if err != nil {
+				return fmt.Errorf("promotion %s: %w", p.ID, err)
+			}
+
+			// Discounts add up to at most the cart total, so the sums cannot overflow
+			discounts[lines[i].SKU], _ = discounts[lines[i].SKU].Add(discount)
+			total, _ = total.Add(discount)
+		}
+
+		if !total.IsZero() {
+			applied = append(applied, models.AppliedPromotion{
+				ID:          p.ID,
+				Description: p.Description,
+				Discount:    total,
+			})
+		}
+	}
+
+	return cart.ApplyDiscounts(discounts, applied)
+}
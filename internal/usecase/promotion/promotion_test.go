@@ -0,0 +1,207 @@
+package promotion
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"route256/cart/internal/domain/models"
+)
+
+// rub returns an amount in the default currency
+func rub(amount int64) models.Money {
+	return models.NewMoney(amount, models.DefaultCurrency)
+}
+
+// newCart returns a cart with the given items and its total calculated
+func newCart(t *testing.T, items ...models.Item) *models.Cart {
+	t.Helper()
+
+	cart := &models.Cart{UserID: 1, Items: items}
+	require.NoError(t, cart.CalculateTotalPrice(nil))
+	return cart
+}
+
+func TestEngine_Apply(t *testing.T) {
+	must := func(rule Rule, err error) Rule {
+		require.NoError(t, err)
+		return rule
+	}
+	percentOff := func(percent int64, skus ...uint32) Rule { return must(NewPercentOff(percent, skus...)) }
+	buyXGetY := func(sku uint32, buy, free uint16) Rule { return must(NewBuyXGetY(sku, buy, free)) }
+	amountOff := func(threshold, amount models.Money) Rule { return must(NewAmountOff(threshold, amount)) }
+
+	tests := []struct {
+		name       string
+		promotions []Promotion
+		coupon     string
+		items      []models.Item
+		want       map[uint32]models.Money
+		wantIDs    []string
+		wantTotal  models.Money
+	}{
+		{
+			name:       "percent off rounds down",
+			promotions: []Promotion{{ID: "p", Rule: percentOff(15, 1)}},
+			items:      []models.Item{{SKU: 1, Quantity: 3, Price: rub(333)}, {SKU: 2, Quantity: 1, Price: rub(1000)}},
+			want:       map[uint32]models.Money{1: rub(149)},
+			wantIDs:    []string{"p"},
+			wantTotal:  rub(1850),
+		},
+		{
+			name:       "buy two get one free",
+			promotions: []Promotion{{ID: "b", Rule: buyXGetY(1, 2, 1)}},
+			items:      []models.Item{{SKU: 1, Quantity: 7, Price: rub(100)}},
+			want:       map[uint32]models.Money{1: rub(200)},
+			wantIDs:    []string{"b"},
+			wantTotal:  rub(500),
+		},
+		{
+			name:       "buy two get one free with too few items",
+			promotions: []Promotion{{ID: "b", Rule: buyXGetY(1, 2, 1)}},
+			items:      []models.Item{{SKU: 1, Quantity: 2, Price: rub(100)}},
+			wantTotal:  rub(200),
+		},
+		{
+			name:       "amount off is spread over lines",
+			promotions: []Promotion{{ID: "a", Rule: amountOff(rub(1000), rub(100))}},
+			items:      []models.Item{{SKU: 1, Quantity: 1, Price: rub(500)}, {SKU: 2, Quantity: 1, Price: rub(250)}, {SKU: 3, Quantity: 1, Price: rub(250)}},
+			want:       map[uint32]models.Money{1: rub(50), 2: rub(25), 3: rub(25)},
+			wantIDs:    []string{"a"},
+			wantTotal:  rub(900),
+		},
+		{
+			name:       "amount off with leftover minor units",
+			promotions: []Promotion{{ID: "a", Rule: amountOff(rub(0), rub(100))}},
+			items:      []models.Item{{SKU: 1, Quantity: 1, Price: rub(100)}, {SKU: 2, Quantity: 1, Price: rub(100)}, {SKU: 3, Quantity: 1, Price: rub(100)}},
+			want:       map[uint32]models.Money{1: rub(34), 2: rub(33), 3: rub(33)},
+			wantIDs:    []string{"a"},
+			wantTotal:  rub(200),
+		},
+		{
+			name:       "amount off below threshold",
+			promotions: []Promotion{{ID: "a", Rule: amountOff(rub(1000), rub(100))}},
+			items:      []models.Item{{SKU: 1, Quantity: 1, Price: rub(999)}},
+			wantTotal:  rub(999),
+		},
+		{
+			name: "threshold applies after earlier discounts",
+			promotions: []Promotion{
+				{ID: "p", Rule: percentOff(10)},
+				{ID: "a", Rule: amountOff(rub(1000), rub(100))},
+			},
+			items:     []models.Item{{SKU: 1, Quantity: 1, Price: rub(1000)}},
+			want:      map[uint32]models.Money{1: rub(100)},
+			wantIDs:   []string{"p"},
+			wantTotal: rub(900),
+		},
+		{
+			name: "discounts stack without exceeding the price",
+			promotions: []Promotion{
+				{ID: "b", Rule: buyXGetY(1, 1, 1)},
+				{ID: "a", Rule: amountOff(rub(0), rub(1000))},
+			},
+			items:     []models.Item{{SKU: 1, Quantity: 2, Price: rub(300)}},
+			want:      map[uint32]models.Money{1: rub(600)},
+			wantIDs:   []string{"b", "a"},
+			wantTotal: rub(0),
+		},
+		{
+			name:       "coupon promotion without the coupon",
+			promotions: []Promotion{{ID: "c", Coupon: "SAVE10", Rule: percentOff(10)}},
+			items:      []models.Item{{SKU: 1, Quantity: 1, Price: rub(1000)}},
+			wantTotal:  rub(1000),
+		},
+		{
+			name:       "coupon promotion with the coupon",
+			promotions: []Promotion{{ID: "c", Coupon: "SAVE10", Rule: percentOff(10)}},
+			coupon:     "save10",
+			items:      []models.Item{{SKU: 1, Quantity: 1, Price: rub(1000)}},
+			want:       map[uint32]models.Money{1: rub(100)},
+			wantIDs:    []string{"c"},
+			wantTotal:  rub(900),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewEngine(tt.promotions)
+			require.NoError(t, err)
+
+			cart := newCart(t, tt.items...)
+			cart.Coupon = tt.coupon
+			require.NoError(t, engine.Apply(cart, nil))
+
+			discounts := make(map[uint32]models.Money)
+			for _, item := range cart.Items {
+				if !item.Discount.IsZero() {
+					discounts[item.SKU] = item.Discount
+				}
+			}
+			if tt.want == nil {
+				tt.want = map[uint32]models.Money{}
+			}
+			assert.Equal(t, tt.want, discounts)
+
+			var ids []string
+			for _, p := range cart.Promotions {
+				ids = append(ids, p.ID)
+			}
+			assert.Equal(t, tt.wantIDs, ids)
+			assert.Equal(t, tt.wantTotal, cart.DiscountedTotal)
+
+			discount, err := cart.TotalPrice.Sub(cart.DiscountedTotal)
+			require.NoError(t, err)
+			assert.Equal(t, discount, cart.Discount)
+		})
+	}
+}
+
+func TestEngine_ApplyConvertsAmounts(t *testing.T) {
+	rule, err := NewAmountOff(models.NewMoney(1000, "USD"), models.NewMoney(100, "USD"))
+	require.NoError(t, err)
+	engine, err := NewEngine([]Promotion{{ID: "a", Rule: rule}})
+	require.NoError(t, err)
+
+	cart := newCart(t, models.Item{SKU: 1, Quantity: 1, Price: rub(100000)})
+	assert.ErrorIs(t, engine.Apply(cart, nil), models.ErrCurrencyMismatch)
+	assert.Empty(t, cart.Promotions)
+
+	// 1 USD = 100 RUB
+	convert := func(amount models.Money, currency string) (models.Money, error) {
+		return amount.Convert(currency, big.NewRat(100, 1))
+	}
+	require.NoError(t, engine.Apply(cart, convert))
+	assert.Equal(t, rub(10000), cart.Discount)
+}
+
+func TestNewEngine_Invalid(t *testing.T) {
+	rule, err := NewPercentOff(10)
+	require.NoError(t, err)
+
+	_, err = NewEngine([]Promotion{{ID: "p", Rule: rule}, {ID: "p", Rule: rule}})
+	assert.Error(t, err)
+	_, err = NewEngine([]Promotion{{ID: "p"}})
+	assert.Error(t, err)
+
+	_, err = NewPercentOff(101)
+	assert.Error(t, err)
+	_, err = NewBuyXGetY(1, 0, 1)
+	assert.Error(t, err)
+	_, err = NewAmountOff(rub(100), rub(0))
+	assert.Error(t, err)
+}
+
+func TestEngine_IsCoupon(t *testing.T) {
+	rule, err := NewPercentOff(10)
+	require.NoError(t, err)
+	engine, err := NewEngine([]Promotion{{ID: "c", Coupon: " Welcome ", Rule: rule}})
+	require.NoError(t, err)
+
+	assert.True(t, engine.IsCoupon("WELCOME"))
+	assert.True(t, engine.IsCoupon("welcome"))
+	assert.False(t, engine.IsCoupon("WELCOME10"))
+	assert.False(t, engine.IsCoupon(""))
+}
@@ -0,0 +1,199 @@
+package promotion
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"route256/cart/internal/domain/models"
+)
+
+// PercentOff takes a percentage off the price of some or all products
+type PercentOff struct {
+	percent int64
+	skus    []uint32
+}
+
+// NewPercentOff creates a rule taking percent off the products with the given
+// SKUs, or off all products if there are none
+func NewPercentOff(percent int64, skus ...uint32) (*PercentOff, error) {
+	if percent <= 0 || percent > 100 {
+		return nil, fmt.Errorf("percent must be between 1 and 100, got %d", percent)
+	}
+
+	return &PercentOff{
+		percent: percent,
+		skus:    skus,
+	}, nil
+}
+
+// Discounts implements Rule. Discounts are rounded down to the minor unit.
+func (r *PercentOff) Discounts(lines []Line, _ models.ConvertFunc) ([]models.Money, error) {
+	var discounts []models.Money
+	for i, line := range lines {
+		if len(r.skus) > 0 && !slices.Contains(r.skus, line.SKU) {
+			continue
+		}
+
+		if discounts == nil {
+			discounts = make([]models.Money, len(lines))
+		}
+
+		// Split the amount so that multiplying by at most 100 cannot overflow
+		amount := line.Payable.Amount
+		discounts[i] = models.NewMoney(amount/100*r.percent+amount%100*r.percent/100, line.Payable.Currency)
+	}
+
+	return discounts, nil
+}
+
+// BuyXGetY gives away some items of a product for every so many bought
+type BuyXGetY struct {
+	sku  uint32
+	buy  uint16
+	free uint16
+}
+
+// NewBuyXGetY creates a rule giving free items of the product with sku away
+// for every buy items paid for
+func NewBuyXGetY(sku uint32, buy, free uint16) (*BuyXGetY, error) {
+	if sku == 0 {
+		return nil, errors.New("sku is required")
+	}
+	if buy == 0 || free == 0 || uint32(buy)+uint32(free) > 1<<16-1 {
+		return nil, fmt.Errorf("invalid buy %d get %d", buy, free)
+	}
+
+	return &BuyXGetY{
+		sku:  sku,
+		buy:  buy,
+		free: free,
+	}, nil
+}
+
+// Discounts implements Rule. An item given away is discounted at its full
+// price, limited by what is left to pay for the line.
+func (r *BuyXGetY) Discounts(lines []Line, _ models.ConvertFunc) ([]models.Money, error) {
+	i := slices.IndexFunc(lines, func(line Line) bool { return line.SKU == r.sku })
+	if i < 0 {
+		return nil, nil
+	}
+
+	line := lines[i]
+	free := line.Quantity / (r.buy + r.free) * r.free
+	if free == 0 {
+		return nil, nil
+	}
+
+	// At most the quantity of items, so this cannot overflow
+	discount, err := line.UnitPrice.Mul(int64(free))
+	if err != nil {
+		return nil, err
+	}
+	if discount.Amount > line.Payable.Amount {
+		discount = line.Payable
+	}
+
+	discounts := make([]models.Money, len(lines))
+	discounts[i] = discount
+	return discounts, nil
+}
+
+// AmountOff takes a fixed amount off carts worth at least a threshold
+type AmountOff struct {
+	threshold models.Money
+	amount    models.Money
+}
+
+// NewAmountOff creates a rule taking amount off carts worth at least threshold
+func NewAmountOff(threshold, amount models.Money) (*AmountOff, error) {
+	if threshold.Amount < 0 || amount.Amount <= 0 {
+		return nil, fmt.Errorf("invalid amount %s off over %s", amount, threshold)
+	}
+	if !models.IsCurrencyCode(threshold.Currency) || !models.IsCurrencyCode(amount.Currency) {
+		return nil, fmt.Errorf("invalid currency of amount %s off over %s", amount, threshold)
+	}
+
+	return &AmountOff{
+		threshold: threshold,
+		amount:    amount,
+	}, nil
+}
+
+// Discounts implements Rule. The threshold applies to what is left to pay,
+// and the discount is spread over the lines in proportion to it.
+func (r *AmountOff) Discounts(lines []Line, convert models.ConvertFunc) ([]models.Money, error) {
+	if len(lines) == 0 {
+		return nil, nil
+	}
+	currency := lines[0].Payable.Currency
+
+	subtotal := models.NewMoney(0, currency)
+	for _, line := range lines {
+		var err error
+		if subtotal, err = subtotal.Add(line.Payable); err != nil {
+			return nil, err
+		}
+	}
+
+	threshold, err := convertTo(r.threshold, currency, convert)
+	if err != nil {
+		return nil, err
+	}
+	if subtotal.IsZero() || subtotal.Amount < threshold.Amount {
+		return nil, nil
+	}
+
+	amount, err := convertTo(r.amount, currency, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	return spread(min(amount.Amount, subtotal.Amount), subtotal.Amount, lines), nil
+}
+
+// spread splits amount over lines in proportion to their payable amounts,
+// which add up to total. Shares are rounded down, and the minor units left
+// over go to the lines with a fractional share, in order.
+func spread(amount, total int64, lines []Line) []models.Money {
+	discounts := make([]models.Money, len(lines))
+	fractional := make([]bool, len(lines))
+
+	left := amount
+	for i, line := range lines {
+		// amount * payable can overflow int64
+		share, rem := new(big.Int).QuoRem(
+			new(big.Int).Mul(big.NewInt(amount), big.NewInt(line.Payable.Amount)),
+			big.NewInt(total),
+			new(big.Int),
+		)
+
+		discounts[i] = models.NewMoney(share.Int64(), line.Payable.Currency)
+		fractional[i] = rem.Sign() != 0
+		left -= share.Int64()
+	}
+
+	for i := range lines {
+		if left == 0 {
+			break
+		}
+		if fractional[i] {
+			discounts[i].Amount++
+			left--
+		}
+	}
+
+	return discounts
+}
+
+// convertTo converts amount to currency unless it already is in it
+func convertTo(amount models.Money, currency string, convert models.ConvertFunc) (models.Money, error) {
+	if amount.Currency == currency {
+		return amount, nil
+	}
+	if convert == nil {
+		return models.Money{}, fmt.Errorf("%w: %s and %s", models.ErrCurrencyMismatch, amount.Currency, currency)
+	}
+	return convert(amount, currency)
+}